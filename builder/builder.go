@@ -46,3 +46,51 @@ func Layout_Fragment(content string) (string, error) {
 
 	return LayoutContent, nil
 }
+
+// Select builds a dropdown out of every option, marking the selected one.
+// Any extra attributes (hx-get, hx-target, etc) are dropped straight onto the select tag.
+func Select(name string, options []string, selected string, attributes string) (string, error) {
+	optionList := ""
+	for _, option := range options {
+		isSelected := ""
+		if option == selected {
+			isSelected = " selected"
+		}
+		// An empty option means "don't care"
+		label := option
+		if option == "" {
+			label = "Any"
+		}
+		optionList = fmt.Sprintf(`%s
+			<option value="%s"%s>%s</option>`, optionList, option, isSelected, label)
+	}
+
+	SelectContent := fmt.Sprintf(`
+		<select name="%s" class="bg-gray-800 text-neutral-200 border border-solid border-neutral-300 p-1" %s>%s
+		</select>
+	`, name, attributes, optionList)
+
+	return SelectContent, nil
+}
+
+// Checkboxes builds a wrapping grid of checkboxes that all submit under the same name
+func Checkboxes(name string, options []string, checked []string) (string, error) {
+	boxes := ""
+	for _, option := range options {
+		isChecked := ""
+		for _, check := range checked {
+			if option == check {
+				isChecked = " checked"
+			}
+		}
+		boxes = fmt.Sprintf(`%s
+			<label class="flex flex-row justify-start items-center gap-1 text-sm"><input type="checkbox" name="%s" value="%s"%s />%s</label>`, boxes, name, option, isChecked, option)
+	}
+
+	CheckboxContent := fmt.Sprintf(`
+		<div class="w-full grid grid-cols-3 gap-1">%s
+		</div>
+	`, boxes)
+
+	return CheckboxContent, nil
+}
@@ -2,13 +2,19 @@ package main
 
 import (
 	"fmt"
+	"html"
 	"io"
 	"log"
+	"maps"
 	"math"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -17,189 +23,2156 @@ import (
 
 	"example.com/builder"
 	"example.com/spacetrader"
+	"example.com/spacetrader/autopilot"
+	"example.com/spacetrader/fake"
+	"example.com/spacetrader/fleet"
+	"example.com/spacetrader/history"
+	"example.com/spacetrader/mining"
+	"example.com/spacetrader/queue"
+	"example.com/spacetrader/replay"
+	"example.com/spacetrader/trade"
 )
 
-func distance(x1 float64, y1 float64, x2 float64, y2 float64) float64 {
-	xRes := math.Pow(x2-x1, 2)
-	yRes := math.Pow(y2-y1, 2)
-	return math.Sqrt(xRes + yRes)
+// waypointList renders a table of waypoints along with how many the API says there are in total
+func waypointList(waypoints []spacetrader.Waypoint, meta spacetrader.Meta) string {
+	list := fmt.Sprintf(`<div class="w-full flex flex-row justify-start items-center text-neutral-200">Showing %d of %d waypoints</div>
+		<div class="w-full flex flex-row justify-between items-center font-bold"><div class="w-1/4">Symbol</div><div class="w-1/4">Type</div><div class="w-1/6">Location</div><div class="w-1/3">Traits</div></div>`,
+		len(waypoints), meta.Total)
+	for _, waypoint := range waypoints {
+		traits := []string{}
+		for _, trait := range waypoint.Traits {
+			traits = append(traits, trait.Name)
+		}
+
+		symbol := waypoint.Symbol
+		if waypoint.IsUnderConstruction {
+			symbol = fmt.Sprintf(`<a href="/system/%s/waypoint/%s/construction" class="hover:underline" title="Under construction">%s 🚧</a>`, spacetrader.SystemSymbol(waypoint.Symbol), waypoint.Symbol, waypoint.Symbol)
+		}
+		if waypoint.HasTrait(spacetrader.WaypointTraitMarketplace) {
+			symbol = fmt.Sprintf(`%s <a href="/system/%s/waypoint/%s/market" class="hover:underline" title="Market">🛒</a>`, symbol, spacetrader.SystemSymbol(waypoint.Symbol), waypoint.Symbol)
+		}
+
+		list = fmt.Sprintf(`%s
+			<div class="w-full flex flex-row justify-between items-start border-t border-solid border-neutral-500 py-1">
+				<div class="w-1/4">%s</div><div class="w-1/4">%s</div><div class="w-1/6">(%d,%d)</div><div class="w-1/3 text-sm">%s</div>
+			</div>`, list, symbol, waypoint.Type, waypoint.PosX, waypoint.PosY, strings.Join(traits, ", "))
+	}
+
+	return fmt.Sprintf(`<div class="w-full flex flex-col justify-start items-center">%s</div>`, list)
+}
+
+// waypointQuery pulls a waypoint search out of the query string, rejecting anything the API wouldn't know
+func waypointQuery(values url.Values) (spacetrader.WaypointQuery, error) {
+	query := spacetrader.WaypointQuery{}
+
+	if kind := values.Get("type"); kind != "" {
+		waypointType, err := spacetrader.ParseWaypointType(kind)
+		if err != nil {
+			return query, err
+		}
+		query.Type = waypointType
+	}
+
+	for _, trait := range values["trait"] {
+		traitSymbol, err := spacetrader.ParseWaypointTraitSymbol(trait)
+		if err != nil {
+			return query, err
+		}
+		query.Traits = append(query.Traits, traitSymbol)
+	}
+
+	if page := values.Get("page"); page != "" {
+		pageNumber, err := strconv.Atoi(page)
+		if err != nil || pageNumber < 1 {
+			return query, fmt.Errorf("invalid page %q", page)
+		}
+		query.Page = pageNumber
+	}
+
+	return query, nil
+}
+
+// pager renders previous/next links that reload target with the same query on a different page,
+// leave target empty to navigate the whole page instead
+func pager(path string, values url.Values, meta spacetrader.Meta, target string) string {
+	if meta.Limit == 0 || meta.Total <= meta.Limit {
+		return ""
+	}
+
+	lastPage := (meta.Total + meta.Limit - 1) / meta.Limit
+	link := func(page int, label string) string {
+		pageValues := url.Values{}
+		for key, value := range values {
+			pageValues[key] = value
+		}
+		pageValues.Set("page", strconv.Itoa(page))
+		// Without a target it's just a plain old link to the next page
+		if target == "" {
+			return fmt.Sprintf(`<a class="hover:underline" href="%s?%s">%s</a>`, path, pageValues.Encode(), label)
+		}
+		return fmt.Sprintf(`<a class="hover:underline cursor-pointer" hx-get="%s?%s" hx-target="%s">%s</a>`, path, pageValues.Encode(), target, label)
+	}
+
+	previous := `<span class="text-neutral-500">Previous</span>`
+	if meta.Page > 1 {
+		previous = link(meta.Page-1, "Previous")
+	}
+	next := `<span class="text-neutral-500">Next</span>`
+	if meta.Page < lastPage {
+		next = link(meta.Page+1, "Next")
+	}
+
+	return fmt.Sprintf(`<div class="w-full flex flex-row justify-between items-center py-2">%s<span>Page %d of %d</span>%s</div>`, previous, meta.Page, lastPage, next)
+}
+
+// writeActionResult answers an htmx action with how it went. Failures get shown to the
+// player instead of taking the whole server down, and successes reload the page to show the change.
+func writeActionResult(w http.ResponseWriter, message string, err error) {
+	if err != nil {
+		message = fmt.Sprintf(`<span class="text-red-600">%s</span>`, html.EscapeString(err.Error()))
+	} else {
+		w.Header().Set("HX-Refresh", "true")
+	}
+
+	laidOut, _ := builder.Layout_Fragment(fmt.Sprintf(`<div class="font-bold">%s</div>`, message))
+
+	w.Write([]byte(laidOut))
+}
+
+// cargoManifest lists what's in the hold, with controls to jettison units or pass them to a neighbouring ship
+func cargoManifest(ship spacetrader.Ship, neighbours []spacetrader.Ship) string {
+	neighbourSymbols := []string{}
+	for _, neighbour := range neighbours {
+		neighbourSymbols = append(neighbourSymbols, neighbour.Symbol)
+	}
+
+	manifest := `<div class="w-full flex flex-row justify-between items-center"><div>Name</div><div>Quantity</div></div>`
+	for _, cargo := range ship.Cargo.Inventory {
+		transfer := ""
+		if len(neighbourSymbols) > 0 {
+			shipSelect, _ := builder.Select("ship", neighbourSymbols, "", "")
+			transfer = fmt.Sprintf(`
+				<form class="flex flex-row justify-end items-center gap-1" hx-post="/ships/%s/cargo:transfer" hx-target="#cargo-result">
+					<input type="hidden" name="symbol" value="%s" />
+					<input type="number" name="units" min="1" max="%d" value="%d" class="w-16 bg-gray-800 text-neutral-200" />
+					%s
+					<button type="submit" class="text-sm hover:underline">Transfer</button>
+				</form>`, ship.Symbol, cargo.Symbol, cargo.Units, cargo.Units, shipSelect)
+		}
+
+		manifest = fmt.Sprintf(`%s
+			<div class="w-full flex flex-col justify-start items-center border-t border-solid border-neutral-500 py-1">
+				<div class="w-full flex flex-row justify-between items-center">
+					<div>%s</div><div>%d</div>
+				</div>
+				<form class="w-full flex flex-row justify-end items-center gap-1" hx-post="/ships/%s/cargo:jettison" hx-target="#cargo-result" hx-confirm="Jettisoned cargo is lost for good, are you sure?">
+					<input type="hidden" name="symbol" value="%s" />
+					<input type="number" name="units" min="1" max="%d" value="%d" class="w-16 bg-gray-800 text-neutral-200" />
+					<button type="submit" class="text-sm text-red-600 hover:underline">Jettison</button>
+				</form>
+				%s
+			</div>`, manifest, cargo.Name, cargo.Units, ship.Symbol, cargo.Symbol, cargo.Units, cargo.Units, transfer)
+	}
+
+	return fmt.Sprintf(`%s<div id="cargo-result" class="w-full"></div>`, manifest)
+}
+
+// cargoAction pulls the good and unit count out of a cargo form
+func cargoAction(r *http.Request) (spacetrader.TradeSymbol, int, error) {
+	tradeSymbol, err := spacetrader.ParseTradeSymbol(r.FormValue("symbol"))
+	if err != nil {
+		return "", 0, err
+	}
+
+	units, err := strconv.Atoi(r.FormValue("units"))
+	if err != nil || units < 1 {
+		return "", 0, fmt.Errorf("invalid units %q", r.FormValue("units"))
+	}
+
+	return tradeSymbol, units, nil
+}
+
+// shipHarvesting offers the refinery and gas siphon, but only to ships that have the kit for them.
+// Siphoning also needs the ship to be sitting at a gas giant.
+func shipHarvesting(ship spacetrader.Ship, location spacetrader.Waypoint) string {
+	products := spacetrader.RefineryProducts(ship)
+	canSiphon := spacetrader.CanSiphon(ship) && location.Type == spacetrader.WaypointTypeGasGiant
+	if len(products) == 0 && !canSiphon {
+		return ""
+	}
+
+	refinery := ""
+	if len(products) > 0 {
+		productOptions := []string{}
+		for _, product := range products {
+			productOptions = append(productOptions, string(product))
+		}
+		productSelect, _ := builder.Select("produce", productOptions, "", "")
+		refinery = fmt.Sprintf(`
+			<form class="w-full flex flex-row justify-between items-center gap-2" hx-post="/ships/%s:refine" hx-target="#harvest-result">
+				<div>Refine into %s</div>
+				<button type="submit" class="hover:underline">Refine</button>
+			</form>`, ship.Symbol, productSelect)
+	}
+
+	siphon := ""
+	if canSiphon {
+		siphon = fmt.Sprintf(`
+			<div class="w-full flex flex-row justify-between items-center gap-2">
+				<div>Siphon gas from %s</div>
+				<a class="hover:underline cursor-pointer" hx-post="/ships/%s:siphon" hx-target="#harvest-result">Siphon</a>
+			</div>`, location.Symbol, ship.Symbol)
+	}
+
+	return fmt.Sprintf(`
+		<div class="w-full p-2 flex flex-col justify-start items-center gap-1 border border-solid border-neutral-200">
+			<span class="text-bold">PROCESSING</span>
+			%s
+			%s
+			<div id="harvest-result" class="w-full"></div>
+		</div>`, refinery, siphon)
+}
+
+// yields lists goods out as "10 IRON, 3 COPPER"
+func yields(goods []spacetrader.Yield) string {
+	listed := []string{}
+	for _, good := range goods {
+		listed = append(listed, fmt.Sprintf("%d %s", good.Units, good.TradeSymbol))
+	}
+
+	return strings.Join(listed, ", ")
+}
+
+// conditionBar draws how worn down a ship component is, with the integrity cap shown behind it
+func conditionBar(label string, condition float64, integrity float64) string {
+	color := "bg-green-600"
+	if condition < 0.5 {
+		color = "bg-red-600"
+	} else if condition < 0.8 {
+		color = "bg-yellow-600"
+	}
+
+	return fmt.Sprintf(`
+		<div class="w-full flex flex-row justify-between items-center gap-2">
+			<div class="w-1/4">%s</div>
+			<div class="w-1/2 h-3 bg-neutral-600 relative" title="Integrity %.0f%%">
+				<div class="h-3 bg-neutral-400 absolute" style="width: %.0f%%"></div>
+				<div class="h-3 %s absolute" style="width: %.0f%%"></div>
+			</div>
+			<div class="w-1/4 text-right text-sm">%.0f%% (max %.0f%%)</div>
+		</div>`, label, integrity*100, integrity*100, color, condition*100, condition*100, integrity*100)
+}
+
+// shipCondition shows the state of the frame, reactor and engine, and offers repair and scrap quotes at a shipyard
+func shipCondition(ship spacetrader.Ship, atShipyard bool) string {
+	actions := `<div class="w-full text-sm text-neutral-400">Dock at a shipyard to repair or scrap this ship</div>`
+	if atShipyard {
+		actions = fmt.Sprintf(`
+			<div class="w-full flex flex-row justify-start items-center gap-4">
+				<a class="hover:underline cursor-pointer" hx-get="/ships/%s/repair:preview" hx-target="#maintenance-result">Repair...</a>
+				<a class="hover:underline cursor-pointer text-red-600" hx-get="/ships/%s/scrap:preview" hx-target="#maintenance-result">Scrap...</a>
+			</div>`, ship.Symbol, ship.Symbol)
+	}
+
+	return fmt.Sprintf(`
+		<div class="w-full p-2 flex flex-col justify-start items-center gap-1 border border-solid border-neutral-200">
+			<span class="text-bold">CONDITION</span>
+			%s
+			%s
+			%s
+			%s
+			<div id="maintenance-result" class="w-full"></div>
+		</div>`,
+		conditionBar("Frame", ship.Frame.Condition, ship.Frame.Integrity),
+		conditionBar("Reactor", ship.Reactor.Condition, ship.Reactor.Integrity),
+		conditionBar("Engine", ship.Engine.Condition, ship.Engine.Integrity),
+		actions)
+}
+
+// shipLoadout lists the ship's mounts and modules, and when it's docked at a shipyard,
+// offers to install parts out of cargo or pull installed ones off
+func shipLoadout(ship spacetrader.Ship, atShipyard bool) string {
+	requirements := func(needs spacetrader.ShipRequirements) string {
+		return fmt.Sprintf(`<span class="text-sm text-neutral-400">⚡%d 👤%d</span>`, needs.Power, needs.Crew)
+	}
+	removeButton := func(kind string, symbol spacetrader.TradeSymbol) string {
+		if !atShipyard {
+			return ""
+		}
+		return fmt.Sprintf(`<a class="hover:underline cursor-pointer text-sm" hx-post="/ships/%s/%s:remove" hx-vals='{"symbol": "%s"}' hx-target="#loadout-result">Remove</a>`, ship.Symbol, kind, symbol)
+	}
+
+	mounts := fmt.Sprintf(`<div class="w-full font-bold">Mounts (%d/%d)</div>`, len(ship.Mounts), ship.Frame.MountingPoints)
+	for _, mount := range ship.Mounts {
+		mounts = fmt.Sprintf(`%s
+			<div class="w-full flex flex-row justify-between items-center" title="%s">
+				<div>%s %s</div><div class="flex flex-row gap-2">%s</div>
+			</div>`, mounts, mount.Description, mount.Name, requirements(mount.Requirements), removeButton("mounts", mount.Symbol))
+	}
+
+	slotsUsed := 0
+	for _, module := range ship.Modules {
+		slotsUsed += module.Requirements.Slots
+	}
+	modules := fmt.Sprintf(`<div class="w-full font-bold">Modules (%d/%d slots)</div>`, slotsUsed, ship.Frame.ModuleSlots)
+	for _, module := range ship.Modules {
+		modules = fmt.Sprintf(`%s
+			<div class="w-full flex flex-row justify-between items-center" title="%s">
+				<div>%s %s</div><div class="flex flex-row gap-2">%s</div>
+			</div>`, modules, module.Description, module.Name, requirements(module.Requirements), removeButton("modules", module.Symbol))
+	}
+
+	installable := ""
+	if atShipyard {
+		installable = `<div class="w-full font-bold">Installable from cargo</div>`
+		for _, cargo := range ship.Cargo.Inventory {
+			var fits error
+			kind := ""
+			if cargo.Symbol.IsMount() {
+				kind = "mounts"
+				fits = spacetrader.CanInstallMount(ship, cargo.Symbol)
+			} else if cargo.Symbol.IsModule() {
+				kind = "modules"
+				fits = spacetrader.CanInstallModule(ship, cargo.Symbol)
+			} else {
+				continue
+			}
+
+			// Only offer the install when we already know it'll fit
+			action := fmt.Sprintf(`<a class="hover:underline cursor-pointer text-sm" hx-post="/ships/%s/%s:install" hx-vals='{"symbol": "%s"}' hx-target="#loadout-result">Install</a>`, ship.Symbol, kind, cargo.Symbol)
+			if fits != nil {
+				action = fmt.Sprintf(`<span class="text-sm text-red-600">%s</span>`, html.EscapeString(fits.Error()))
+			}
+
+			installable = fmt.Sprintf(`%s
+				<div class="w-full flex flex-row justify-between items-center">
+					<div>%s (%d)</div><div>%s</div>
+				</div>`, installable, cargo.Name, cargo.Units, action)
+		}
+	} else {
+		installable = `<div class="w-full text-sm text-neutral-400">Dock at a shipyard to change this ship's loadout</div>`
+	}
+
+	return fmt.Sprintf(`
+		<div class="w-full p-2 flex flex-col justify-start items-center gap-1 border border-solid border-neutral-200">
+			<span class="text-bold">LOADOUT</span>
+			%s
+			%s
+			%s
+			<div id="loadout-result" class="w-full"></div>
+		</div>`, mounts, modules, installable)
+}
+
+// voyageSystems caps how many systems get mapped out looking for a way to another system. It's a
+// cap rather than a promise, a way through that only turns up further out than this isn't found.
+const voyageSystems = 25
+
+// voyagePlanLife is how long a finished voyage plan is kept for flying before it gets planned again
+const voyagePlanLife = 5 * time.Minute
+
+// voyagePlan is a voyage being worked out, or worked out already, from where the ship was at the time
+type voyagePlan struct {
+	from     string
+	route    spacetrader.Route
+	err      error
+	done     bool
+	finished time.Time
+}
+
+// voyagePlans works voyages out in the background, mapping the gates between systems takes a lot
+// longer than a request gets. Plans are kept a while so flying one doesn't mean planning it again.
+type voyagePlans struct {
+	api spacetrader.API
+
+	mu    sync.Mutex
+	plans map[string]*voyagePlan
+}
+
+func newVoyagePlans(api spacetrader.API) *voyagePlans {
+	return &voyagePlans{api: api, plans: map[string]*voyagePlan{}}
+}
+
+// plan hands back ship's voyage to destination, setting off to work it out if there isn't a
+// fresh one from where the ship is now. It's false while that's still going.
+func (v *voyagePlans) plan(ship spacetrader.Ship, destination string) (spacetrader.Route, bool, error) {
+	from := ship.Nav.WaypointSymbol
+	if ship.Nav.Status == spacetrader.ShipNavStatusInTransit {
+		from = ship.Nav.Route.Destination.Symbol
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.prune()
+
+	key := voyageKey(ship.Symbol, destination)
+	if plan, ok := v.plans[key]; ok && plan.from == from {
+		return plan.route, plan.done, plan.err
+	}
+
+	plan := &voyagePlan{from: from}
+	v.plans[key] = plan
+
+	go func() {
+		route, err := planVoyage(v.api, ship, spacetrader.SystemSymbol(destination), destination)
+
+		v.mu.Lock()
+		defer v.mu.Unlock()
+
+		plan.route, plan.err, plan.done, plan.finished = route, err, true, time.Now()
+	}()
+
+	return spacetrader.Route{}, false, nil
+}
+
+// flown forgets ship's plan for destination once the autopilot has it
+func (v *voyagePlans) flown(shipSymbol string, destination string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	delete(v.plans, voyageKey(shipSymbol, destination))
+}
+
+// prune forgets every finished plan older than voyagePlanLife, v.mu must be held
+func (v *voyagePlans) prune() {
+	for key, plan := range v.plans {
+		if plan.done && time.Since(plan.finished) >= voyagePlanLife {
+			delete(v.plans, key)
+		}
+	}
+}
+
+func voyageKey(shipSymbol string, destination string) string {
+	return shipSymbol + ">" + destination
+}
+
+// planRoute works out the quickest way for a ship to get to destination, refuelling at any market selling fuel.
+// The destination can be a waypoint or a system, anywhere outside the ship's own system gets a voyage
+// through the jump gates (and warps, for ships that can) instead. Voyages are planned in the background,
+// it's false until the plan's ready.
+func planRoute(api spacetrader.API, voyages *voyagePlans, shipSymbol string, destination string) (spacetrader.Route, bool, error) {
+	ship, err := api.GetShip(shipSymbol)
+	if err != nil {
+		return spacetrader.Route{}, false, err
+	}
+
+	systemSymbol := spacetrader.SystemSymbol(destination)
+	if systemSymbol != ship.Nav.SystemSymbol {
+		return voyages.plan(ship, destination)
+	}
+
+	system, err := api.GetSystem(ship.Nav.SystemSymbol)
+	if err != nil {
+		return spacetrader.Route{}, false, err
+	}
+
+	fuelStations, err := systemFuelStations(api, system.Symbol)
+	if err != nil {
+		return spacetrader.Route{}, false, err
+	}
+
+	// Asking for the system the ship's already in means staying put
+	if destination == system.Symbol {
+		return spacetrader.Route{Hops: []spacetrader.RouteHop{}}, true, nil
+	}
+
+	route, err := spacetrader.PlanRoute(ship, system.Waypoints, fuelStations, destination)
+
+	return route, true, err
+}
+
+// planning stands in for a voyage plan that isn't ready yet, asking again every few seconds until it is
+func planning(url string, destination string) string {
+	return fmt.Sprintf(`<div class="w-full text-neutral-400" hx-get="%s" hx-trigger="load delay:3s" hx-swap="outerHTML">Mapping the way to %s, this can take a few minutes...</div>`, url, html.EscapeString(destination))
+}
+
+// planVoyage maps out the gate network between the ship and the system it's going to, then plans the whole trip
+func planVoyage(api spacetrader.API, ship spacetrader.Ship, systemSymbol string, destination string) (spacetrader.Route, error) {
+	galaxy, err := spacetrader.ExploreGalaxy(api, ship.Nav.SystemSymbol, systemSymbol, voyageSystems)
+	if err != nil {
+		return spacetrader.Route{}, err
+	}
+
+	fuelStations := map[string]int{}
+	for symbol := range galaxy.Systems {
+		stations, err := systemFuelStations(api, symbol)
+		if err != nil {
+			return spacetrader.Route{}, err
+		}
+		maps.Copy(fuelStations, stations)
+	}
+
+	route, err := galaxy.PlanVoyage(ship, fuelStations, destination)
+	if err != nil && len(galaxy.Systems) >= voyageSystems {
+		return route, fmt.Errorf("%w, only the %d systems nearest were mapped so the way may be further out", err, voyageSystems)
+	}
+
+	return route, err
+}
+
+// systemFuelStations finds every market in a system that sells fuel
+func systemFuelStations(api spacetrader.API, systemSymbol string) (map[string]int, error) {
+	// Only marketplaces can sell fuel, so there's no need to look at every market in the system
+	marketplaces, err := spacetrader.AllWaypoints(api, systemSymbol, spacetrader.WaypointQuery{Traits: []spacetrader.WaypointTraitSymbol{spacetrader.WaypointTraitMarketplace}})
+	if err != nil {
+		return nil, err
+	}
+
+	return spacetrader.FindFuelStations(api, marketplaces)
+}
+
+// hopHow says how a hop gets flown, jumps don't have a flight mode
+func hopHow(hop spacetrader.RouteHop) string {
+	switch hop.Action {
+	case spacetrader.HopJump:
+		return "JUMP"
+	case spacetrader.HopWarp:
+		return fmt.Sprintf("WARP (%s)", hop.Mode)
+	}
+
+	return string(hop.Mode)
+}
+
+// routePlan lays out a planned route hop by hop, with what each leg costs in fuel, time and credits
+func routePlan(shipSymbol string, destination string, route spacetrader.Route) string {
+	if len(route.Hops) == 0 {
+		return `<div class="w-full text-neutral-200">Already there</div>`
+	}
+
+	// Warp times and jump cooldowns are our own guesses, marked ~ so they aren't taken as gospel
+	hops, estimated := "", false
+	for _, hop := range route.Hops {
+		refuel := ""
+		if hop.Refuel > 0 {
+			refuel = fmt.Sprintf("+%d fuel (%d cr)", hop.Refuel, hop.Cost)
+		}
+		duration := hop.Duration.String()
+		switch hop.Action {
+		case spacetrader.HopJump:
+			duration, estimated = fmt.Sprintf("~%s cooldown", hop.Cooldown), true
+		case spacetrader.HopWarp:
+			duration, estimated = "~"+duration, true
+		}
+		hops = fmt.Sprintf(`%s
+			<tr>
+				<td class="pr-2">%s</td>
+				<td class="pr-2">%s</td>
+				<td class="pr-2">%s</td>
+				<td class="pr-2 text-right">%.1f</td>
+				<td class="pr-2 text-right">%d</td>
+				<td class="pr-2 text-right">%s</td>
+				<td class="text-right">%s</td>
+			</tr>`, hops, hop.From, hop.To, hopHow(hop), hop.Distance, hop.Fuel, duration, refuel)
+	}
+
+	antimatter := ""
+	if route.Antimatter > 0 {
+		antimatter = fmt.Sprintf(", %d antimatter", route.Antimatter)
+	}
+
+	total, note := route.Duration.String(), ""
+	if estimated {
+		total = "~" + total
+		note = `<div class="text-xs text-neutral-400">~ Warp times and jump cooldowns are estimates, the game only gives the real ones once the ship's on its way</div>`
+	}
+
+	return fmt.Sprintf(`
+		<div class="w-full flex flex-col justify-start items-start gap-1 text-neutral-200">
+			<table class="w-full text-sm">
+				<tr class="font-bold"><td>From</td><td>To</td><td>Mode</td><td class="text-right">Distance</td><td class="text-right">Fuel</td><td class="text-right">Time</td><td class="text-right">Refuel</td></tr>
+				%s
+			</table>
+			<div class="font-bold">%d hops, %d fuel%s, %s, %d credits</div>
+			%s
+			<form hx-post="/ships/%s/autopilot:start" hx-target="#route-plan-result">
+				<input type="hidden" name="destination" value="%s" />
+				<button type="submit" class="hover:underline">Fly this route</button>
+			</form>
+			<div id="route-plan-result" class="w-full"></div>
+		</div>`, hops, len(route.Hops), route.Fuel, antimatter, total, route.Cost, note, shipSymbol, html.EscapeString(destination))
+}
+
+// autopilotProgress shows how far along its route a ship is, and keeps itself up to date while it's flying
+func autopilotProgress(journey autopilot.Journey, ok bool) string {
+	if !ok {
+		return ""
+	}
+
+	hops := ""
+	for i, hop := range journey.Route.Hops {
+		marker := "⬜"
+		switch {
+		case i < journey.Hop:
+			marker = "✅"
+		case i == journey.Hop && journey.Status == autopilot.StatusRunning:
+			marker = "🚀"
+		}
+		hops = fmt.Sprintf(`%s<div>%s %s → %s (%s)</div>`, hops, marker, hop.From, hop.To, hopHow(hop))
+	}
+
+	// Keep polling while there's still something to watch
+	refresh, cancel := "", ""
+	if journey.Status == autopilot.StatusRunning {
+		refresh = fmt.Sprintf(`hx-get="/ships/%s/autopilot:fragment" hx-trigger="every 5s" hx-swap="outerHTML"`, journey.ShipSymbol)
+		cancel = fmt.Sprintf(`<button class="hover:underline" hx-post="/ships/%s/autopilot:cancel" hx-target="#autopilot-result">Cancel</button>`, journey.ShipSymbol)
+	}
+
+	problem := ""
+	if journey.Error != "" {
+		problem = fmt.Sprintf(`<div class="text-red-600">%s</div>`, html.EscapeString(journey.Error))
+	}
+
+	return fmt.Sprintf(`
+		<div id="autopilot" class="w-full p-2 flex flex-col justify-start items-start border border-solid border-neutral-200 text-neutral-200" %s>
+			<span class="text-bold">AUTOPILOT</span>
+			<div class="font-bold">%s, %s (hop %d of %d)</div>
+			%s
+			%s
+			%s
+			<div id="autopilot-result" class="w-full"></div>
+		</div>`, refresh, journey.Destination(), journey.Status, min(journey.Hop+1, len(journey.Route.Hops)), len(journey.Route.Hops), hops, problem, cancel)
+}
+
+// miningPanel starts and stops a mining ship's loop, and while it runs shows what it's up to
+func miningPanel(ship spacetrader.Ship, asteroids []spacetrader.Waypoint, status mining.Status, ok bool) string {
+	if !spacetrader.CanMine(ship) {
+		return ""
+	}
+
+	if ok && status.Step.Running() {
+		return fmt.Sprintf(`
+		<div id="mining" class="w-full p-2 flex flex-col justify-start items-start border border-solid border-neutral-200 text-neutral-200" hx-get="/ships/%s/mining:fragment" hx-trigger="every 5s" hx-swap="outerHTML">
+			<span class="text-bold">MINING</span>
+			<div class="font-bold">%s at %s</div>
+			<div>%s</div>
+			<div>%d loads sold, %d units extracted, %d credits earned</div>
+			<button class="hover:underline" hx-post="/ships/%s/mining:stop" hx-target="#mining-result">Stop</button>
+			<div id="mining-result" class="w-full"></div>
+		</div>`, ship.Symbol, status.Step, status.Asteroid, html.EscapeString(status.Detail), status.Loads, status.Extracted, status.Earned, ship.Symbol)
+	}
+
+	if len(asteroids) == 0 {
+		return ""
+	}
+
+	// The last run stays up until the next one starts, so a failure doesn't go unnoticed
+	last := ""
+	if ok {
+		last = fmt.Sprintf(`<div>Last run at %s: %s, %d loads sold for %d credits</div>`, status.Asteroid, status.Step, status.Loads, status.Earned)
+		if status.Error != "" {
+			last = fmt.Sprintf(`%s<div class="text-red-600">%s</div>`, last, html.EscapeString(status.Error))
+		}
+	}
+
+	options := []string{}
+	selected := asteroids[0].Symbol
+	for _, asteroid := range asteroids {
+		options = append(options, asteroid.Symbol)
+		if asteroid.Symbol == ship.Nav.WaypointSymbol {
+			selected = asteroid.Symbol
+		}
+	}
+	asteroidSelect, _ := builder.Select("asteroid", options, selected, "")
+
+	return fmt.Sprintf(`
+		<div id="mining" class="w-full p-2 flex flex-col justify-start items-start border border-solid border-neutral-200 text-neutral-200">
+			<span class="text-bold">MINING</span>
+			%s
+			<form class="w-full flex flex-row justify-between items-center gap-2" hx-post="/ships/%s/mining:start" hx-target="#mining-result">
+				<div>Mine %s and sell at the best market nearby</div>
+				<button type="submit" class="hover:underline">Start</button>
+			</form>
+			<div id="mining-result" class="w-full"></div>
+		</div>`, last, ship.Symbol, asteroidSelect)
+}
+
+// asteroids picks out the waypoints in a system that can be mined
+func asteroids(system spacetrader.System) []spacetrader.Waypoint {
+	found := []spacetrader.Waypoint{}
+	for _, waypoint := range system.Waypoints {
+		if spacetrader.Minable(waypoint.Type) {
+			found = append(found, waypoint)
+		}
+	}
+
+	return found
+}
+
+// fleetStatus tables up what every ship has been set to do and how it's going, along with the
+// task queue underneath, refreshing itself as they work
+func fleetStatus(ships []spacetrader.Ship, statuses []fleet.Status, tasks []queue.Task) string {
+	byShip := map[string]fleet.Status{}
+	for _, status := range statuses {
+		byShip[status.ShipSymbol] = status
+	}
+
+	rows := ""
+	for _, ship := range ships {
+		status, ok := byShip[ship.Symbol]
+		if !ok {
+			status = fleet.Status{Behavior: "unassigned", State: "-"}
+		}
+
+		colour := ""
+		switch status.State {
+		case fleet.StateRunning:
+			colour = "text-green-600"
+		case fleet.StateRestarting:
+			colour = "text-red-600"
+		}
+
+		problem := ""
+		if status.Error != "" {
+			problem = fmt.Sprintf(`<div class="text-sm text-red-600">%s</div>`, html.EscapeString(status.Error))
+		}
+
+		rows = fmt.Sprintf(`%s
+			<tr class="align-top">
+				<td class="pr-2"><a class="hover:underline" href="/ships/%s">%s</a></td>
+				<td class="pr-2">%s</td>
+				<td class="pr-2">%s</td>
+				<td class="pr-2 %s">%s</td>
+				<td class="pr-2">%s%s</td>
+				<td class="text-right">%d</td>
+			</tr>`, rows, ship.Symbol, ship.Symbol, ship.Nav.WaypointSymbol, status.Behavior, colour, status.State, html.EscapeString(status.Detail), problem, status.Restarts)
+	}
+
+	// Newest first, the finished ones are only there for a day
+	queued := ""
+	for i := len(tasks) - 1; i >= 0; i-- {
+		task := tasks[i]
+
+		due := ""
+		if task.State == queue.StatePending {
+			due = task.ScheduledAt.Local().Format(time.TimeOnly)
+		}
+
+		problem := ""
+		if task.Error != "" {
+			problem = fmt.Sprintf(`<div class="text-sm text-red-600">%s</div>`, html.EscapeString(task.Error))
+		}
+
+		queued = fmt.Sprintf(`%s
+			<tr class="align-top">
+				<td class="pr-2">%s</td>
+				<td class="pr-2">%s</td>
+				<td class="pr-2">%s%s</td>
+				<td class="pr-2">%s</td>
+				<td class="text-right">%d</td>
+			</tr>`, queued, task.Kind, html.EscapeString(task.Key), task.State, problem, due, task.Attempts)
+	}
+	if queued == "" {
+		queued = `<tr><td colspan="5" class="text-neutral-400">Nothing queued</td></tr>`
+	}
+
+	return fmt.Sprintf(`
+		<div id="fleet-status" class="w-full flex flex-col gap-4 text-neutral-200" hx-get="/fleet:fragment" hx-trigger="every 5s" hx-swap="outerHTML">
+			<table class="w-full">
+				<tr class="font-bold"><td>Ship</td><td>Location</td><td>Behaviour</td><td>State</td><td>Doing</td><td class="text-right">Restarts</td></tr>
+				%s
+			</table>
+			<div class="text-xl">Task queue</div>
+			<table class="w-full">
+				<tr class="font-bold"><td>Kind</td><td>Key</td><td>State</td><td>Due</td><td class="text-right">Failures</td></tr>
+				%s
+			</table>
+		</div>`, rows, queued)
+}
+
+// priceChart draws a good's price history as an SVG line chart, what we pay in red and what we'd get in green
+func priceChart(observations []history.Observation) string {
+	if len(observations) == 0 {
+		return `<div class="w-full text-neutral-400">No prices on record yet, they get saved whenever a ship is at the market</div>`
+	}
+
+	const width, height, padding = 720.0, 200.0, 40.0
+
+	first, last := observations[0].Time, observations[len(observations)-1].Time
+	low, high := math.MaxInt, 0
+	for _, observation := range observations {
+		low = min(low, observation.PurchasePrice, observation.SellPrice)
+		high = max(high, observation.PurchasePrice, observation.SellPrice)
+	}
+	// Give flat lines a bit of room so they don't sit on the edge
+	if low == high {
+		low, high = max(0, low-1), high+1
+	}
+
+	x := func(at time.Time) float64 {
+		if !last.After(first) {
+			return width / 2
+		}
+		return padding + float64(at.Sub(first))/float64(last.Sub(first))*(width-2*padding)
+	}
+	y := func(price int) float64 {
+		return height - padding - float64(price-low)/float64(high-low)*(height-2*padding)
+	}
+
+	purchases, sales, points := "", "", ""
+	for _, observation := range observations {
+		purchases = fmt.Sprintf("%s %.1f,%.1f", purchases, x(observation.Time), y(observation.PurchasePrice))
+		sales = fmt.Sprintf("%s %.1f,%.1f", sales, x(observation.Time), y(observation.SellPrice))
+		points = fmt.Sprintf(`%s<circle cx="%.1f" cy="%.1f" r="2" fill="#f87171"><title>%s buy %d</title></circle><circle cx="%.1f" cy="%.1f" r="2" fill="#4ade80"><title>%s sell %d</title></circle>`,
+			points,
+			x(observation.Time), y(observation.PurchasePrice), observation.Time.Format(time.DateTime), observation.PurchasePrice,
+			x(observation.Time), y(observation.SellPrice), observation.Time.Format(time.DateTime), observation.SellPrice)
+	}
+
+	return fmt.Sprintf(`
+		<svg viewBox="0 0 %.0f %.0f" class="w-full bg-gray-900" xmlns="http://www.w3.org/2000/svg">
+			<line x1="%.0f" y1="%.0f" x2="%.0f" y2="%.0f" stroke="#737373" />
+			<line x1="%.0f" y1="%.0f" x2="%.0f" y2="%.0f" stroke="#737373" />
+			<text x="4" y="%.1f" fill="#d4d4d4" font-size="11">%d</text>
+			<text x="4" y="%.1f" fill="#d4d4d4" font-size="11">%d</text>
+			<text x="%.0f" y="%.0f" fill="#d4d4d4" font-size="11">%s</text>
+			<text x="%.0f" y="%.0f" fill="#d4d4d4" font-size="11" text-anchor="end">%s</text>
+			<polyline points="%s" fill="none" stroke="#f87171" stroke-width="2" />
+			<polyline points="%s" fill="none" stroke="#4ade80" stroke-width="2" />
+			%s
+		</svg>`,
+		width, height,
+		padding, height-padding, width-padding, height-padding,
+		padding, padding, padding, height-padding,
+		y(high)+4, high,
+		y(low)+4, low,
+		padding, height-padding+16, first.Local().Format(time.DateTime),
+		width-padding, height-padding+16, last.Local().Format(time.DateTime),
+		purchases,
+		sales,
+		points,
+	)
 }
 
-func main() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Fatal("Error loading .env file")
-	}
+// marketGoods lists what a market trades at, with how each price has moved over the last day
+func marketGoods(systemSymbol string, waypointSymbol string, goods []spacetrader.MarketTradeGood, prices *history.Store) string {
+	list := `<div class="w-full flex flex-row justify-between items-center font-bold"><div class="w-1/4">Good</div><div class="w-1/6">Type</div><div class="w-1/6">Supply</div><div class="w-1/12 text-right">Buy</div><div class="w-1/12 text-right">Sell</div><div class="w-1/12 text-right">Volume</div><div class="w-1/6 text-right">24h</div></div>`
+	for _, good := range goods {
+		trend := "-"
+		if moved, ok := prices.Trend(waypointSymbol, good.Symbol, time.Now().Add(-24*time.Hour)); ok {
+			trend = fmt.Sprintf("%s %+d (%+.1f%%)", moved.Direction(), moved.PurchaseChange, moved.Percent)
+		}
+
+		list = fmt.Sprintf(`%s
+			<div class="w-full flex flex-row justify-between items-center border-t border-solid border-neutral-500 py-1">
+				<a href="/system/%s/waypoint/%s/market?good=%s" class="w-1/4 hover:underline">%s</a><div class="w-1/6">%s</div><div class="w-1/6">%s</div><div class="w-1/12 text-right">%d</div><div class="w-1/12 text-right">%d</div><div class="w-1/12 text-right">%d</div><div class="w-1/6 text-right">%s</div>
+			</div>`, list, systemSymbol, waypointSymbol, good.Symbol, good.Symbol, good.Type, good.Supply, good.PurchasePrice, good.SellPrice, good.TradeVolume, trend)
+	}
+
+	return list
+}
+
+// tradeOpportunities ranks the runs worth making, with how stale the prices behind each one are
+func tradeOpportunities(opportunities []trade.Opportunity) string {
+	if len(opportunities) == 0 {
+		return `<div class="w-full text-neutral-400">Nothing worth hauling yet, visit more markets to fill in their prices</div>`
+	}
+
+	list := `<div class="w-full flex flex-row justify-between items-center font-bold"><div class="w-1/6">Good</div><div class="w-1/6">Buy at</div><div class="w-1/6">Sell at</div><div class="w-1/12 text-right">Units</div><div class="w-1/12 text-right">Profit</div><div class="w-1/12 text-right">Time</div><div class="w-1/12 text-right">Per hour</div><div class="w-1/12 text-right">Data age</div></div>`
+	for _, opportunity := range opportunities {
+		list = fmt.Sprintf(`%s
+			<div class="w-full flex flex-row justify-between items-center border-t border-solid border-neutral-500 py-1" title="Spend %d on goods, get %d back, %d on fuel">
+				<div class="w-1/6">%s</div>
+				<a href="/system/%s/waypoint/%s/market?good=%s" class="w-1/6 hover:underline">%s @ %d</a>
+				<a href="/system/%s/waypoint/%s/market?good=%s" class="w-1/6 hover:underline">%s @ %d</a>
+				<div class="w-1/12 text-right">%d</div>
+				<div class="w-1/12 text-right">%d</div>
+				<div class="w-1/12 text-right">%s</div>
+				<div class="w-1/12 text-right">%.0f</div>
+				<div class="w-1/12 text-right">%s</div>
+			</div>`,
+			list,
+			opportunity.Cost, opportunity.Revenue, opportunity.FuelCost,
+			opportunity.Good,
+			spacetrader.SystemSymbol(opportunity.Buy.Waypoint), opportunity.Buy.Waypoint, opportunity.Good, opportunity.Buy.Waypoint, opportunity.Buy.PurchasePrice,
+			spacetrader.SystemSymbol(opportunity.Sell.Waypoint), opportunity.Sell.Waypoint, opportunity.Good, opportunity.Sell.Waypoint, opportunity.Sell.SellPrice,
+			opportunity.Units,
+			opportunity.Profit,
+			opportunity.Duration,
+			opportunity.ProfitPerHour(),
+			opportunity.Age.Round(time.Minute),
+		)
+	}
+
+	return list
+}
+
+// contractEconomics shows what a contract should earn once the goods and fuel are paid for, and whether it can be done in time
+func contractEconomics(estimate trade.ContractEstimate, haulerSymbol string) string {
+	sources, unpriced := "", false
+	for _, delivery := range estimate.Deliveries {
+		if delivery.Found {
+			sources = fmt.Sprintf(`%s<div class="text-sm">%s from %s @ %d, %d trip(s)</div>`, sources, delivery.TradeSymbol, delivery.Source.Waypoint, delivery.Source.PurchasePrice, delivery.Trips)
+		} else if delivery.UnitsFulfilled < delivery.UnitsRequired {
+			unpriced = true
+		}
+	}
+
+	// Without a price for everything the net figure would just be the payout, which flatters it
+	net := fmt.Sprintf("%+d", estimate.Profit)
+	colour := "text-green-600"
+	if estimate.Profit < 0 {
+		colour = "text-red-600"
+	}
+	if unpriced {
+		net, colour = "unknown", "text-neutral-400"
+	}
+
+	feasibility := `<div class="text-green-600">✅ Feasible</div>`
+	if !estimate.Feasible() {
+		feasibility = fmt.Sprintf(`<div class="text-red-600">⚠ %s</div>`, html.EscapeString(strings.Join(estimate.Problems, ", ")))
+	}
+
+	return fmt.Sprintf(`
+		<div class="w-full flex flex-col justify-start items-start border-t border-solid border-neutral-500 pt-1 text-neutral-200">
+			<div class="font-bold %s">Net: %s</div>
+			<div class="text-sm">Payout %d, goods %d, fuel %d</div>
+			%s
+			<div class="text-sm">%s with %s, %s to the deadline</div>
+			%s
+		</div>`,
+		colour, net,
+		estimate.Payout, estimate.Procurement, estimate.FuelCost,
+		sources,
+		estimate.Duration.Round(time.Second), haulerSymbol, estimate.TimeLeft.Round(time.Minute),
+		feasibility,
+	)
+}
+
+func main() {
+	// SPACETRADER_FAKE=1 runs everything against an in-memory universe instead of the real API
+	offline := os.Getenv("SPACETRADER_FAKE") != ""
+
+	err := godotenv.Load()
+	if err != nil && !offline {
+		log.Fatal("Error loading .env file")
+	}
+
+	// Grab the Agent token from ENV
+	authToken := os.Getenv("AUTH_TOKEN")
+
+	if offline {
+		universe := httptest.NewServer(fake.New())
+		defer universe.Close()
+
+		spacetrader.BaseURL = universe.URL
+		authToken = fake.Token
+		log.Printf("Running against a fake SpaceTraders API at %s", universe.URL)
+	}
+
+	// SPACETRADER_RECORD=file saves every API exchange as a fixture, SPACETRADER_REPLAY=file plays one back
+	if fixture := os.Getenv("SPACETRADER_RECORD"); fixture != "" {
+		spacetrader.HTTPClient = &http.Client{Transport: replay.NewRecorder(fixture, http.DefaultTransport)}
+		log.Printf("Recording API exchanges to %s", fixture)
+	} else if fixture := os.Getenv("SPACETRADER_REPLAY"); fixture != "" {
+		player, err := replay.NewPlayer(fixture)
+		if err != nil {
+			log.Fatal(err)
+		}
+		spacetrader.HTTPClient = &http.Client{Transport: player}
+		log.Printf("Replaying API exchanges from %s", fixture)
+	}
+
+	log.SetPrefix("Server: ")
+	log.SetFlags(0)
+
+	// Every market price we see is kept here, so there's something to go on for markets we've left
+	historyFile := os.Getenv("HISTORY_FILE")
+	if historyFile == "" {
+		historyFile = "market-history.jsonl"
+	}
+	if offline {
+		historyFile = filepath.Join(os.TempDir(), "spacetrader-fake-history.jsonl")
+		os.Remove(historyFile)
+	}
+
+	prices, err := history.Open(historyFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer prices.Close()
+
+	// Systems and waypoints barely change within a reset so most page loads can skip the API.
+	// Markets get recorded underneath the cache, so only fresh prices go in the history.
+	api := spacetrader.NewCachedAPI(history.Record(spacetrader.NewClient(authToken), prices), spacetrader.DefaultCachePolicy)
+
+	// Journeys in progress are saved here so a restart carries on flying them
+	autopilotFile := os.Getenv("AUTOPILOT_FILE")
+	if autopilotFile == "" {
+		autopilotFile = "autopilot.json"
+	}
+	if offline {
+		// The fake universe starts over every run, so should the journeys flying around it
+		autopilotFile = filepath.Join(os.TempDir(), "spacetrader-fake-autopilot.json")
+		os.Remove(autopilotFile)
+	}
+
+	pilot, err := autopilot.New(api, autopilotFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pilot.Resume()
+	defer pilot.Stop()
+
+	// Mining ships fly through the autopilot, so the miner has to wind down first
+	miner := mining.New(api, pilot, prices)
+	defer miner.Shutdown()
+
+	// Mining and fleet behaviours are queued up here so a restart puts every ship back to work
+	queueFile := os.Getenv("QUEUE_FILE")
+	if queueFile == "" {
+		queueFile = "tasks.json"
+	}
+	if offline {
+		queueFile = filepath.Join(os.TempDir(), "spacetrader-fake-tasks.json")
+		os.Remove(queueFile)
+	}
+
+	tasks, err := queue.Open(queueFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	miner.Queue(tasks)
+	scheduler := fleet.New(api, pilot, miner, prices, tasks)
+
+	// The queue drives the miner and the autopilot, so it goes before either of them
+	tasks.Start()
+	defer tasks.Shutdown()
+
+	r := newRouter(api, pilot, miner, scheduler, tasks, prices)
+
+	http.ListenAndServe(":3000", r)
+}
+
+// newRouter wires up every page and action against api, so handlers can be run against fakes
+func newRouter(api spacetrader.API, pilot *autopilot.Pilot, miner *mining.Miner, scheduler *fleet.Scheduler, tasks *queue.Queue, prices *history.Store) chi.Router {
+	r := chi.NewRouter()
+	voyages := newVoyagePlans(api)
+
+	r.Use(middleware.RequestID)
+	r.Use(middleware.RealIP)
+	r.Use(middleware.Logger)
+	r.Use(middleware.Recoverer)
+
+	// Set a timeout value on the request context (ctx), that will signal
+	// through ctx.Done() that the request has timed out and further
+	// processing should be stopped.
+	r.Use(middleware.Timeout(60 * time.Second))
+
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		agent, err := api.ShowAgent()
+		ships, err := api.GetShips()
+		contracts, err := api.GetContracts()
+		// Failed to get the Agent from the API
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		shipList := `<div class="flex flex-row flex-wrap justify-start items-center gap-4">`
+		for _, ship := range ships {
+			shipList = fmt.Sprintf(`%s
+				<a href="/ships/%s" class="flex flex-col justify-center items-center p-4 border border-solid border-neutral-300 hover:bg-neutral-200/10">
+					<div class="flex flex-row justify-between items-center gap-2"><div class="text-xl text-bold">%s</div><span class="text-sm">(⛽%d/%d)</span></div>
+					<div>Current Location: %s</div>
+					<div>Cargo: %d / %d</div>
+				</a>`,
+				shipList, ship.Symbol, ship.Symbol, ship.Fuel.Current, ship.Fuel.Capacity, ship.Nav.WaypointSymbol, ship.Cargo.Units, ship.Cargo.Capacity)
+		}
+		shipList = fmt.Sprintf(`%s</div>`, shipList)
+
+		// Contracts get costed against the ship with the biggest hold
+		hauler := spacetrader.Ship{}
+		for _, ship := range ships {
+			if ship.Cargo.Capacity > hauler.Cargo.Capacity {
+				hauler = ship
+			}
+		}
+
+		contractList := `<div class="flex flex-row flex-wrap justify-start items-center gap-4">`
+		for _, contract := range contracts {
+			// Deliveries can go to more than one system, so map out all of them
+			waypoints := []spacetrader.Waypoint{}
+			mapped := map[string]bool{}
+			var unmapped error
+			for _, delivery := range contract.Terms.Deliver {
+				systemSymbol := spacetrader.SystemSymbol(delivery.DestinationSymbol)
+				if mapped[systemSymbol] {
+					continue
+				}
+				mapped[systemSymbol] = true
+
+				system, err := api.GetSystem(systemSymbol)
+				// Without the destination system there's nothing to estimate with, the card goes without
+				if err != nil {
+					unmapped = err
+					break
+				}
+				waypoints = append(waypoints, system.Waypoints...)
+			}
+			economics := fmt.Sprintf(`<div class="w-full border-t border-solid border-neutral-500 pt-1 text-sm text-neutral-400" title="%s">Estimate unavailable</div>`, html.EscapeString(fmt.Sprint(unmapped)))
+			if unmapped == nil {
+				economics = contractEconomics(trade.EvaluateContract(contract, hauler, waypoints, prices, time.Now()), hauler.Symbol)
+			}
+
+			deliveries := `<div class="w-full flex flex-col justify-start items-start">`
+			for _, delivery := range contract.Terms.Deliver {
+				deliveries = fmt.Sprintf(`%s
+				<div class="flex flex-row justify-start items-center gap-2">
+					<span>%d</span>
+					<span>%s</span>
+				</div>`,
+					deliveries, delivery.UnitsRequired, delivery.TradeSymbol)
+			}
+			deliveries = fmt.Sprintf(`%s</div>`, deliveries)
+
+			contractList = fmt.Sprintf(`%s
+				<div class="flex flex-col justify-start items-center p-4 border border-solid border-neutral-300 hover:bg-neutral-200/10">
+					<div class="flex flex-row w-full gap-2">
+						<span class="text-2xl text-red-600 font-bold">X</span>
+						<span class="text-2xl text-neutral-200 font-bold">%s</span>
+						<span class="text-2xl text-green-600 font-bold">\,</span>
+					</div>
+					%s
+					%s
+				</div>`,
+				contractList, contract.Type, deliveries, economics)
+		}
+		contractList = fmt.Sprintf(`%s</div>`, contractList)
+
+		content := fmt.Sprintf(`
+			<div class="flex flex-col max-w-[960px] w-full justify-start items-center">	
+				<div class="w-full flex flex-row justify-start items-center px-4 text-2xl text-neutral-200">Welcome %s!</div>
+				<div class="w-full flex flex-row justify-start items-center px-4 text-xl text-neutral-200">Credits: %d</div>
+				<div class="w-full flex flex-row justify-start items-center px-4 text-neutral-200"><a href="/systems" class="hover:underline">Browse the sector</a><span class="px-1">|</span><a href="/trade" class="hover:underline">Trade opportunities</a><span class="px-1">|</span><a href="/fleet" class="hover:underline">Fleet automation</a></div>
+				<div class="w-full p-4">
+					<span class="text-2xl">Ships:</span>
+					%s
+				</div>
+				<div class="w-full p-4">
+					<span class="text-2xl">Contracts:</span>
+					%s
+				</div>
+			</div>`,
+			agent.Symbol,
+			agent.Credits,
+			shipList,
+			contractList)
+		laidOut, err := builder.Layout_Main(content)
+
+		// If the layout fails to build
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		page, err := builder.Document("Space Trader", laidOut)
+
+		// If the document fails to build
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		w.Write([]byte(page))
+	})
+	r.Get("/ships/{shipSymbol}", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		ship, err := api.GetShip(shipSymbol)
+		// Failed to get the ship
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		system, err := api.GetSystem(ship.Nav.SystemSymbol)
+		// Failed to get the system
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fleet, err := api.GetShips()
+		// Failed to get the rest of the fleet
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		// Cargo can only be handed over to ships sitting at the same waypoint
+		neighbours := []spacetrader.Ship{}
+		for _, other := range fleet {
+			if other.Symbol != ship.Symbol && other.Nav.WaypointSymbol == ship.Nav.WaypointSymbol && other.Nav.Status != spacetrader.ShipNavStatusInTransit {
+				neighbours = append(neighbours, other)
+			}
+		}
+
+		// Fuel and ETA are worked out for whatever flight mode the ship is set to
+		flightMode := ship.Nav.FlightMode
+		if flightMode == "" {
+			flightMode = spacetrader.FlightModeCruise
+		}
+
+		travelManifest := fmt.Sprintf(`<div class="w-full grid grid-cols-[1fr_auto_auto] gap-x-4"><div>Name</div><div class="text-right">Fuel</div><div class="text-right">ETA (%s)</div></div>`, flightMode)
+		for _, waypoint := range system.Waypoints {
+			howFar := spacetrader.Distance(ship.Nav.Route.Destination.PosX, ship.Nav.Route.Destination.PosY, waypoint.PosX, waypoint.PosY)
+
+			// Ships without a tank can go anywhere
+			fuel := 0
+			if ship.Fuel.Capacity > 0 {
+				fuel = spacetrader.FuelCost(flightMode, howFar)
+			}
+
+			// Anything out of reach on the fuel we have gets coloured red
+			colour := ""
+			if fuel > ship.Fuel.Current {
+				colour = "text-red-600"
+			}
+
+			// Use flex order style to sort the list by distance from the ship
+			travelManifest = fmt.Sprintf(`%s
+				<div class="w-full grid grid-cols-[1fr_auto_auto] gap-x-4 %s order-[%d]">
+					<div class="hover:underline cursor-pointer" hx-get="/system/%s/waypoint/%s/%s:fragment" hx-target="#viewer">%s (%d,%d)</div><div class="text-right">%d</div><div class="text-right">%s</div>
+				</div>`, travelManifest, colour, int(math.Round(howFar)), ship.Nav.SystemSymbol, waypoint.Symbol, ship.Symbol, waypoint.Symbol, waypoint.PosX, waypoint.PosY, fuel, spacetrader.TravelTime(flightMode, ship.Engine.Speed, howFar))
+		}
+
+		shipNav, err := spacetrader.DisplayShipNav(api, ship.Symbol)
+
+		if err != nil {
+			log.Fatal("Could not retrieve ship")
+		}
+
+		journey, hasJourney := pilot.Journey(ship.Symbol)
+		mined, hasMined := miner.Status(ship.Symbol)
+
+		// Parts, repairs and scrapping all need the ship docked at a shipyard
+		atShipyard := false
+		if ship.Nav.Status == spacetrader.ShipNavStatusDocked {
+			location, err := api.GetWaypoint(ship.Nav.SystemSymbol, ship.Nav.WaypointSymbol)
+			// Failed to get the waypoint the ship is sitting at
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			atShipyard = location.HasTrait(spacetrader.WaypointTraitShipyard)
+		}
+
+		// The system listing is enough to know what kind of place the ship is at
+		location := spacetrader.Waypoint{}
+		for _, waypoint := range system.Waypoints {
+			if waypoint.Symbol == ship.Nav.WaypointSymbol {
+				location = waypoint
+			}
+		}
+
+		content := fmt.Sprintf(`
+			<div class="flex flex-col max-w-[960px] w-full justify-start items-center">	
+				<div class="w-full flex flex-row justify-start items-center px-4 text-4xl text-neutral-200 underline">%s</div>
+				<div class="w-full flex flex-row justify-start items-center px-4 text-neutral-200 font-bold">Location: %s</div>
+				%s
+				<div class="w-full flex flex-row justify-start items-center px-4 text-neutral-200">Fuel: %d/%d</div>
+				<div class="w-full flex flex-row justify-around items-start gap-2">
+					<div class="w-full p-2 flex flex-col justify-start items-center border border-solid border-neutral-200">
+						<span class="text-bold">CARGO</span>
+						%s
+					</div>
+					<div class="w-full max-h-64 overflow-auto p-2 flex flex-col justify-start items-center border border-solid border-neutral-200">
+						<span class="text-bold">TRAVEL</span>
+						%s
+					</div>
+				</div>
+				%s
+				%s
+				%s
+				%s
+				%s
+				<div id="viewer" class="w-full"></div>
+			</div>`,
+			ship.Symbol,
+			ship.Nav.WaypointSymbol,
+			shipNav,
+			ship.Fuel.Current,
+			ship.Fuel.Capacity,
+			cargoManifest(ship, neighbours),
+			travelManifest,
+			autopilotProgress(journey, hasJourney),
+			miningPanel(ship, asteroids(system), mined, hasMined),
+			shipCondition(ship, atShipyard),
+			shipLoadout(ship, atShipyard),
+			shipHarvesting(ship, location),
+		)
+		laidOut, err := builder.Layout_Main(content)
+
+		// If the layout fails to build
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		page, err := builder.Document("Space Trader - System", laidOut)
+
+		// If the document fails to build
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		w.Write([]byte(page))
+	})
+	r.Get("/ships/{shipSymbol}/nav:fragment", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		shipNav, err := spacetrader.DisplayShipNav(api, shipSymbol)
+
+		if err != nil {
+			log.Fatal("Could not retrieve ship")
+		}
+
+		w.Write([]byte(shipNav))
+	})
+	r.Post("/ships/{shipSymbol}:launch", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		success, err := api.LaunchToOrbit(shipSymbol)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		successFragment := fmt.Sprintf(`<div class="font-bold" hx-on:htmx:afterSettle="/ships/%s/nav:fragment">%t</div>`, shipSymbol, success)
+
+		laidOut, err := builder.Layout_Fragment(successFragment)
+
+		w.Write([]byte(laidOut))
+	})
+	r.Post("/ships/{shipSymbol}:dock", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		success, err := api.DockShip(shipSymbol)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		successFragment := fmt.Sprintf(`<div class="font-bold" hx-on:htmx:afterSettle="/ships/%s/nav:fragment">%t</div>`, shipSymbol, success)
+
+		laidOut, err := builder.Layout_Fragment(successFragment)
+
+		w.Write([]byte(laidOut))
+	})
+	r.Post("/ships/{shipSymbol}/mounts:install", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		mountSymbol, err := spacetrader.ParseTradeSymbol(r.FormValue("symbol"))
+		if err != nil {
+			writeActionResult(w, "", err)
+			return
+		}
+
+		ship, err := api.GetShip(shipSymbol)
+		if err != nil {
+			writeActionResult(w, "", err)
+			return
+		}
+
+		// No sense asking the API for something we already know won't fit
+		if err := spacetrader.CanInstallMount(ship, mountSymbol); err != nil {
+			writeActionResult(w, "", err)
+			return
+		}
+
+		change, err := api.InstallMount(shipSymbol, mountSymbol)
+		writeActionResult(w, fmt.Sprintf("Installed %s for %d credits", mountSymbol, change.Transaction.TotalPrice), err)
+	})
+	r.Post("/ships/{shipSymbol}/mounts:remove", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		mountSymbol, err := spacetrader.ParseTradeSymbol(r.FormValue("symbol"))
+		if err != nil {
+			writeActionResult(w, "", err)
+			return
+		}
+
+		change, err := api.RemoveMount(shipSymbol, mountSymbol)
+		writeActionResult(w, fmt.Sprintf("Removed %s for %d credits", mountSymbol, change.Transaction.TotalPrice), err)
+	})
+	r.Post("/ships/{shipSymbol}/modules:install", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		moduleSymbol, err := spacetrader.ParseTradeSymbol(r.FormValue("symbol"))
+		if err != nil {
+			writeActionResult(w, "", err)
+			return
+		}
+
+		ship, err := api.GetShip(shipSymbol)
+		if err != nil {
+			writeActionResult(w, "", err)
+			return
+		}
+
+		// No sense asking the API for something we already know won't fit
+		if err := spacetrader.CanInstallModule(ship, moduleSymbol); err != nil {
+			writeActionResult(w, "", err)
+			return
+		}
+
+		change, err := api.InstallModule(shipSymbol, moduleSymbol)
+		writeActionResult(w, fmt.Sprintf("Installed %s for %d credits", moduleSymbol, change.Transaction.TotalPrice), err)
+	})
+	r.Post("/ships/{shipSymbol}/modules:remove", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		moduleSymbol, err := spacetrader.ParseTradeSymbol(r.FormValue("symbol"))
+		if err != nil {
+			writeActionResult(w, "", err)
+			return
+		}
+
+		change, err := api.RemoveModule(shipSymbol, moduleSymbol)
+		writeActionResult(w, fmt.Sprintf("Removed %s for %d credits", moduleSymbol, change.Transaction.TotalPrice), err)
+	})
+	r.Post("/ships/{shipSymbol}/cargo:jettison", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		tradeSymbol, units, err := cargoAction(r)
+		if err != nil {
+			writeActionResult(w, "", err)
+			return
+		}
+
+		_, err = api.JettisonCargo(shipSymbol, tradeSymbol, units)
+		writeActionResult(w, fmt.Sprintf("Jettisoned %d %s", units, tradeSymbol), err)
+	})
+	r.Post("/ships/{shipSymbol}/cargo:transfer", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		tradeSymbol, units, err := cargoAction(r)
+		if err != nil {
+			writeActionResult(w, "", err)
+			return
+		}
+
+		toShipSymbol := r.FormValue("ship")
+		_, err = api.TransferCargo(shipSymbol, tradeSymbol, units, toShipSymbol)
+		writeActionResult(w, fmt.Sprintf("Transferred %d %s to %s", units, tradeSymbol, toShipSymbol), err)
+	})
+	r.Post("/ships/{shipSymbol}:refine", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		produce, err := spacetrader.ParseTradeSymbol(r.FormValue("produce"))
+		if err != nil {
+			writeActionResult(w, "", err)
+			return
+		}
+
+		refinement, err := api.RefineCargo(shipSymbol, produce)
+		writeActionResult(w, fmt.Sprintf("Refined %s into %s, cooling down for %ds", yields(refinement.Consumed), yields(refinement.Produced), refinement.Cooldown.RemainingSeconds), err)
+	})
+	r.Post("/ships/{shipSymbol}:siphon", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		siphon, err := api.SiphonResources(shipSymbol)
+		writeActionResult(w, fmt.Sprintf("Siphoned %d %s, cooling down for %ds", siphon.Siphon.Yield.Units, siphon.Siphon.Yield.Symbol, siphon.Cooldown.RemainingSeconds), err)
+	})
+	r.Get("/ships/{shipSymbol}/repair:preview", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		quote, err := api.GetRepairShip(shipSymbol)
+		if err != nil {
+			writeActionResult(w, "", err)
+			return
+		}
+
+		preview := fmt.Sprintf(`<div class="w-full flex flex-row justify-start items-center gap-2">Repairs at %s will cost %d credits. <a class="hover:underline cursor-pointer font-bold" hx-post="/ships/%s:repair" hx-target="#maintenance-result">Confirm repair</a></div>`,
+			quote.WaypointSymbol, quote.TotalPrice, shipSymbol)
+		laidOut, err := builder.Layout_Fragment(preview)
+
+		// If the layout fails to build
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		w.Write([]byte(laidOut))
+	})
+	r.Post("/ships/{shipSymbol}:repair", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		repair, err := api.RepairShip(shipSymbol)
+		writeActionResult(w, fmt.Sprintf("Repaired %s for %d credits", shipSymbol, repair.Transaction.TotalPrice), err)
+	})
+	r.Get("/ships/{shipSymbol}/scrap:preview", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		quote, err := api.GetScrapShip(shipSymbol)
+		if err != nil {
+			writeActionResult(w, "", err)
+			return
+		}
+
+		preview := fmt.Sprintf(`<div class="w-full flex flex-row justify-start items-center gap-2">%s will pay %d credits to scrap this ship. <a class="hover:underline cursor-pointer font-bold text-red-600" hx-post="/ships/%s:scrap" hx-target="#maintenance-result" hx-confirm="Scrap %s for %d credits? This can't be undone.">Confirm scrap</a></div>`,
+			quote.WaypointSymbol, quote.TotalPrice, shipSymbol, shipSymbol, quote.TotalPrice)
+		laidOut, err := builder.Layout_Fragment(preview)
+
+		// If the layout fails to build
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		w.Write([]byte(laidOut))
+	})
+	r.Post("/ships/{shipSymbol}:scrap", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		scrap, err := api.ScrapShip(shipSymbol)
+		if err != nil {
+			writeActionResult(w, "", err)
+			return
+		}
+
+		// The ship is gone, so head back to the dashboard instead of reloading its page
+		w.Header().Set("HX-Redirect", "/")
+		w.Write([]byte(fmt.Sprintf("Scrapped %s for %d credits", shipSymbol, scrap.Transaction.TotalPrice)))
+	})
+	r.Post("/ships/{shipSymbol}:navigate", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		// Closing the connection seems important and stuff
+		defer r.Body.Close()
+		// Grab the deets
+		body, err := io.ReadAll(r.Body)
+
+		if err != nil {
+			log.Fatal("No destination provided")
+		}
+
+		fmt.Println(shipSymbol)
+		fmt.Println(body)
+
+		//success, err := api.DockShip(shipSymbol)
+		//if err != nil {
+		//	log.Fatal(err)
+		//}
+
+		//successFragment := fmt.Sprintf(`<div class="font-bold" hx-on:htmx:afterSettle="/ships/%s/nav:fragment">%t</div>`, shipSymbol, success)
+
+		//laidOut, err := builder.Layout_Fragment(successFragment)
+
+		w.Write([]byte("NAVIGATE"))
+	})
+	r.Get("/ships/{shipSymbol}/route:plan", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		destination := r.URL.Query().Get("destination")
+		if destination == "" {
+			http.Error(w, "destination is required", http.StatusBadRequest)
+			return
+		}
+
+		route, ready, err := planRoute(api, voyages, shipSymbol, destination)
+		if err != nil {
+			writeActionResult(w, "", err)
+			return
+		}
+		if !ready {
+			w.Write([]byte(planning(r.URL.String(), destination)))
+			return
+		}
+
+		laidOut, err := builder.Layout_Fragment(routePlan(shipSymbol, destination, route))
+
+		// If the layout fails to build
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		w.Write([]byte(laidOut))
+	})
+	r.Post("/ships/{shipSymbol}/autopilot:start", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		destination := r.FormValue("destination")
+		if destination == "" {
+			http.Error(w, "destination is required", http.StatusBadRequest)
+			return
+		}
+
+		// Plan again rather than trusting the form, things may have moved since the plan was shown.
+		// Voyages come from the plan the preview made, so long as the ship hasn't moved since.
+		route, ready, err := planRoute(api, voyages, shipSymbol, destination)
+		if err != nil {
+			writeActionResult(w, "", err)
+			return
+		}
+		if !ready {
+			writeActionResult(w, "", fmt.Errorf("still mapping the way to %s, fly it once the plan shows up", destination))
+			return
+		}
+
+		err = pilot.Start(shipSymbol, route)
+		if err == nil {
+			voyages.flown(shipSymbol, destination)
+		}
+		writeActionResult(w, fmt.Sprintf("Autopilot engaged, %s is on its way to %s", shipSymbol, destination), err)
+	})
+	r.Post("/ships/{shipSymbol}/autopilot:cancel", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		err := pilot.Cancel(shipSymbol)
+		writeActionResult(w, fmt.Sprintf("Autopilot disengaged for %s", shipSymbol), err)
+	})
+	r.Get("/ships/{shipSymbol}/autopilot:fragment", func(w http.ResponseWriter, r *http.Request) {
+		journey, ok := pilot.Journey(chi.URLParam(r, "shipSymbol"))
+		w.Write([]byte(autopilotProgress(journey, ok)))
+	})
+	r.Post("/ships/{shipSymbol}/mining:start", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		asteroid := r.FormValue("asteroid")
+		if asteroid == "" {
+			http.Error(w, "asteroid is required", http.StatusBadRequest)
+			return
+		}
+
+		err := miner.Start(shipSymbol, asteroid)
+		writeActionResult(w, fmt.Sprintf("%s is off to mine %s", shipSymbol, asteroid), err)
+	})
+	r.Post("/ships/{shipSymbol}/mining:stop", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		err := miner.Stop(shipSymbol)
+		writeActionResult(w, fmt.Sprintf("%s has stopped mining", shipSymbol), err)
+	})
+	r.Get("/ships/{shipSymbol}/mining:fragment", func(w http.ResponseWriter, r *http.Request) {
+		ship, err := api.GetShip(chi.URLParam(r, "shipSymbol"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		system, err := api.GetSystem(ship.Nav.SystemSymbol)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		status, ok := miner.Status(ship.Symbol)
+		w.Write([]byte(miningPanel(ship, asteroids(system), status, ok)))
+	})
+	// htmx fragments use the :fragment identifier on the end
+	r.Get("/system/{system}/waypoint/{waypoint}/{shipSymbol}:fragment", func(w http.ResponseWriter, r *http.Request) {
+		systemSymbol := chi.URLParam(r, "system")
+		waypointSymbol := chi.URLParam(r, "waypoint")
+		shipSymbol := chi.URLParam(r, "shipSymbol")
+		waypoint, err := api.GetWaypoint(systemSymbol, waypointSymbol)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		traits := ""
+		for _, trait := range waypoint.Traits {
+			traits = fmt.Sprintf(`%s
+				<div class="w-full flex flex-col justify-center items-start">
+					<div class="font-bold">%s</div>
+					<div>%s</div>
+				</div>`, traits, trait.Name, trait.Description)
+		}
+
+		construction := ""
+		if waypoint.IsUnderConstruction {
+			construction = fmt.Sprintf(`<div class="w-full flex flex-row justify-start items-center">🚧 Under construction (<a href="/system/%s/waypoint/%s/construction" class="hover:underline">View construction site</a>)</div>`, systemSymbol, waypoint.Symbol)
+		}
+		marketplace := ""
+		if waypoint.HasTrait(spacetrader.WaypointTraitMarketplace) {
+			marketplace = fmt.Sprintf(`<div class="w-full flex flex-row justify-start items-center">🛒 Marketplace (<a href="/system/%s/waypoint/%s/market" class="hover:underline">View market</a>)</div>`, systemSymbol, waypoint.Symbol)
+		}
+
+		content := fmt.Sprintf(`
+			<div class="flex flex-col max-w-[960px] w-full justify-start items-center">	
+			<div class="w-full flex flex-row justify-start items-center text-2xl text-neutral-200">Waypoint: %s (<a class="hover:underline cursor-pointer" hx-post="/ships/%s:navigate" hx-include="#waypoint_symbol">Navigate to this waypoint</a>, <a class="hover:underline cursor-pointer" hx-get="/ships/%s/route:plan?destination=%s" hx-target="#route-plan">Plan route</a>)<input type="hidden" id="waypoint_symbol" value="%s" /></div>
+				<div id="route-plan" class="w-full"></div>
+				%s
+				%s
+				<div class="w-full flex flex-col justify-start items-center>%s</div>
+			</div>`,
+			waypoint.Symbol,
+			shipSymbol,
+			shipSymbol,
+			url.QueryEscape(waypoint.Symbol),
+			waypoint.Symbol,
+			construction,
+			marketplace,
+			traits,
+		)
+		page, err := builder.Layout_Fragment(content)
+
+		// If the layout fails to build
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		w.Write([]byte(page))
+	})
+	r.Get("/systems", func(w http.ResponseWriter, r *http.Request) {
+		pageNumber := 1
+		if requested := r.URL.Query().Get("page"); requested != "" {
+			parsed, err := strconv.Atoi(requested)
+			if err != nil || parsed < 1 {
+				http.Error(w, fmt.Sprintf("invalid page %q", requested), http.StatusBadRequest)
+				return
+			}
+			pageNumber = parsed
+		}
+
+		agent, err := api.ShowAgent()
+		// Failed to get the Agent from the API
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		headquarters, err := api.GetSystem(spacetrader.SystemSymbol(agent.Headquarters))
+		// Failed to get the home system
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		systems, meta, err := api.ListSystems(pageNumber, 20)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		systemList := `<div class="w-full flex flex-row justify-between items-center font-bold"><div class="w-1/4">Symbol</div><div class="w-1/4">Type</div><div class="w-1/6">Location</div><div class="w-1/6">Waypoints</div><div class="w-1/6">Distance from HQ</div></div>`
+		for _, system := range systems {
+			howFar := spacetrader.Distance(headquarters.PosX, headquarters.PosY, system.PosX, system.PosY)
+			systemList = fmt.Sprintf(`%s
+				<div class="w-full flex flex-row justify-between items-center border-t border-solid border-neutral-500 py-1">
+					<a href="/system/%s" class="w-1/4 hover:underline">%s</a><div class="w-1/4">%s</div><div class="w-1/6">(%d,%d)</div><div class="w-1/6">%d</div><div class="w-1/6">%.1f</div>
+				</div>`, systemList, system.Symbol, system.Symbol, system.Type, system.PosX, system.PosY, len(system.Waypoints), howFar)
+		}
+
+		content := fmt.Sprintf(`
+			<div class="flex flex-col max-w-[960px] w-full justify-start items-center">	
+				<div class="w-full flex flex-row justify-start items-center px-4 text-2xl text-neutral-200">Sector Browser</div>
+				<div class="w-full flex flex-row justify-start items-center px-4 text-neutral-200">Headquarters: <a href="/system/%s" class="px-1 hover:underline">%s</a> (%d,%d)</div>
+				<div class="w-full flex flex-col justify-start items-center p-4">
+					%s
+					%s
+				</div>
+			</div>`,
+			headquarters.Symbol,
+			headquarters.Symbol,
+			headquarters.PosX,
+			headquarters.PosY,
+			systemList,
+			pager(r.URL.Path, r.URL.Query(), meta, ""),
+		)
+		laidOut, err := builder.Layout_Main(content)
+
+		// If the layout fails to build
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		page, err := builder.Document("Space Trader - Sector Browser", laidOut)
+
+		// If the document fails to build
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Write([]byte(page))
+	})
+	r.Get("/trade", func(w http.ResponseWriter, r *http.Request) {
+		agent, err := api.ShowAgent()
+		// Failed to get the Agent from the API
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		fleet, err := api.GetShips()
+		// Failed to get the fleet
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		// Only ships with a hold can haul anything, the first of them is picked if none was asked for
+		haulers := []string{}
+		ship := spacetrader.Ship{}
+		for _, candidate := range fleet {
+			if candidate.Cargo.Capacity == 0 {
+				continue
+			}
+			haulers = append(haulers, candidate.Symbol)
+			if candidate.Symbol == r.URL.Query().Get("ship") || ship.Symbol == "" {
+				ship = candidate
+			}
+		}
+
+		opportunities := []trade.Opportunity{}
+		if ship.Symbol != "" {
+			system, err := api.GetSystem(ship.Nav.SystemSymbol)
+			// Failed to get the system
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			opportunities = trade.Find(ship, system.Waypoints, prices, agent.Credits, time.Now())
+		}
+
+		shipSelect, err := builder.Select("ship", haulers, ship.Symbol, `onchange="this.form.submit()"`)
+
+		// If the dropdown fails to build
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		content := fmt.Sprintf(`
+			<div class="flex flex-col max-w-[960px] w-full justify-start items-center">	
+				<div class="w-full flex flex-row justify-start items-center px-4 text-2xl text-neutral-200">Trade opportunities</div>
+				<div class="w-full flex flex-row justify-start items-center gap-2 px-4 text-neutral-200"><form action="/trade" method="get">Ship: %s</form> in %s, %d/%d cargo, %d credits to spend</div>
+				<div class="w-full flex flex-row justify-start items-center px-4 text-sm text-neutral-400">Prices come from the market history and each lot bought or sold is assumed to move the price %.0f%%</div>
+				<div class="w-full flex flex-col justify-start items-center p-4 text-neutral-200">
+					%s
+				</div>
+			</div>`,
+			shipSelect,
+			ship.Nav.SystemSymbol,
+			ship.Cargo.Units,
+			ship.Cargo.Capacity,
+			agent.Credits,
+			trade.PriceImpact*100,
+			tradeOpportunities(opportunities),
+		)
+		laidOut, err := builder.Layout_Main(content)
+
+		// If the layout fails to build
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		page, err := builder.Document("Space Trader - Trade Opportunities", laidOut)
+
+		// If the document fails to build
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Write([]byte(page))
+	})
+	r.Get("/fleet", func(w http.ResponseWriter, r *http.Request) {
+		ships, err := api.GetShips()
+		// Failed to get the fleet
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		shipSymbols := []string{}
+		for _, ship := range ships {
+			shipSymbols = append(shipSymbols, ship.Symbol)
+		}
+		behaviors := []string{}
+		for _, behavior := range fleet.Behaviors {
+			behaviors = append(behaviors, string(behavior))
+		}
+
+		shipSelect, err := builder.Select("shipSymbol", shipSymbols, "", "")
+		// If the dropdown fails to build
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		behaviorSelect, err := builder.Select("behavior", behaviors, "", "")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		content := fmt.Sprintf(`
+			<div class="flex flex-col max-w-[960px] w-full justify-start items-center">	
+				<div class="w-full flex flex-row justify-start items-center px-4 text-2xl text-neutral-200">Fleet automation</div>
+				<form class="w-full flex flex-row justify-start items-center gap-2 px-4 text-neutral-200" hx-post="/fleet:assign" hx-target="#fleet-result">
+					Set %s to %s
+					<button type="submit" class="hover:underline">Assign</button>
+				</form>
+				<div id="fleet-result" class="w-full px-4"></div>
+				<div class="w-full flex flex-col justify-start items-center p-4">
+					%s
+				</div>
+			</div>`,
+			shipSelect,
+			behaviorSelect,
+			fleetStatus(ships, scheduler.Statuses(), tasks.Tasks()),
+		)
+		laidOut, err := builder.Layout_Main(content)
+
+		// If the layout fails to build
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		page, err := builder.Document("Space Trader - Fleet", laidOut)
+
+		// If the document fails to build
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Write([]byte(page))
+	})
+	r.Get("/fleet:fragment", func(w http.ResponseWriter, r *http.Request) {
+		ships, err := api.GetShips()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		w.Write([]byte(fleetStatus(ships, scheduler.Statuses(), tasks.Tasks())))
+	})
+	r.Post("/fleet:assign", func(w http.ResponseWriter, r *http.Request) {
+		shipSymbol := r.FormValue("shipSymbol")
+		behavior := fleet.Behavior(r.FormValue("behavior"))
+		if shipSymbol == "" || behavior == "" {
+			http.Error(w, "shipSymbol and behavior are required", http.StatusBadRequest)
+			return
+		}
+
+		err := scheduler.Assign(shipSymbol, behavior)
+		writeActionResult(w, fmt.Sprintf("%s is now set to %s", shipSymbol, behavior), err)
+	})
+	r.Get("/system/{system}/waypoint/{waypoint}/market", func(w http.ResponseWriter, r *http.Request) {
+		systemSymbol := chi.URLParam(r, "system")
+		waypointSymbol := chi.URLParam(r, "waypoint")
+		market, err := api.GetMarket(systemSymbol, waypointSymbol)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		// Without a ship here the API hides prices, so fall back on the last ones we saw
+		goods := market.TradeGoods
+		seen := "Live prices, one of our ships is here"
+		if len(goods) == 0 {
+			for _, observation := range prices.LatestMarket(waypointSymbol) {
+				goods = append(goods, observation.MarketTradeGood)
+			}
+			seen = "No prices on record"
+			if age, ok := prices.Age(waypointSymbol, time.Now()); ok {
+				seen = fmt.Sprintf("Prices last seen %s ago", age.Round(time.Second))
+			}
+		}
+
+		listed := func(marketGoods []spacetrader.MarketGood) string {
+			symbols := []string{}
+			for _, good := range marketGoods {
+				symbols = append(symbols, string(good.Symbol))
+			}
+			if len(symbols) == 0 {
+				return "-"
+			}
+			return strings.Join(symbols, ", ")
+		}
 
-	// Grab the Agent token from ENV
-	authToken := os.Getenv("AUTH_TOKEN")
+		charted := spacetrader.TradeSymbol(r.URL.Query().Get("good"))
+		if charted != "" && !charted.Valid() {
+			http.Error(w, "good must be a trade symbol", http.StatusBadRequest)
+			return
+		}
+		if charted == "" && len(goods) > 0 {
+			charted = goods[0].Symbol
+		}
 
-	log.SetPrefix("Server: ")
-	log.SetFlags(0)
+		content := fmt.Sprintf(`
+			<div class="flex flex-col max-w-[960px] w-full justify-start items-center">	
+				<div class="w-full flex flex-row justify-start items-center px-4 text-2xl text-neutral-200">Market: %s</div>
+				<div class="w-full flex flex-row justify-start items-center px-4 text-neutral-200">System: <a href="/system/%s" class="px-1 hover:underline">%s</a></div>
+				<div class="w-full flex flex-row justify-start items-center px-4 text-neutral-200">Imports: %s</div>
+				<div class="w-full flex flex-row justify-start items-center px-4 text-neutral-200">Exports: %s</div>
+				<div class="w-full flex flex-row justify-start items-center px-4 text-neutral-200">Exchange: %s</div>
+				<div class="w-full flex flex-row justify-start items-center px-4 text-neutral-200">%s</div>
+				<div class="w-full flex flex-col justify-start items-center p-4 text-neutral-200">
+					%s
+				</div>
+				<div class="w-full flex flex-col justify-start items-start gap-1 p-4 text-neutral-200">
+					<div class="font-bold">Price history: %s</div>
+					%s
+				</div>
+			</div>`,
+			market.Symbol,
+			systemSymbol,
+			systemSymbol,
+			listed(market.Imports),
+			listed(market.Exports),
+			listed(market.Exchange),
+			seen,
+			marketGoods(systemSymbol, waypointSymbol, goods, prices),
+			charted,
+			priceChart(prices.History(waypointSymbol, charted, time.Time{})),
+		)
+		laidOut, err := builder.Layout_Main(content)
 
-	r := chi.NewRouter()
+		// If the layout fails to build
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
-	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
+		page, err := builder.Document("Space Trader - Market", laidOut)
 
-	// Set a timeout value on the request context (ctx), that will signal
-	// through ctx.Done() that the request has timed out and further
-	// processing should be stopped.
-	r.Use(middleware.Timeout(60 * time.Second))
+		// If the document fails to build
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
-	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
-		agent, err := spacetrader.ShowAgent(authToken)
-		ships, err := spacetrader.GetShips(authToken)
-		contracts, err := spacetrader.GetContracts(authToken)
-		// Failed to get the Agent from the API
+		w.Write([]byte(page))
+	})
+	r.Get("/system/{system}/waypoint/{waypoint}/construction", func(w http.ResponseWriter, r *http.Request) {
+		systemSymbol := chi.URLParam(r, "system")
+		waypointSymbol := chi.URLParam(r, "waypoint")
+		construction, err := api.GetConstructionSite(systemSymbol, waypointSymbol)
 		if err != nil {
-			log.Fatal(err)
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
 		}
 
-		shipList := `<div class="flex flex-row flex-wrap justify-start items-center gap-4">`
-		for _, ship := range ships {
-			shipList = fmt.Sprintf(`%s
-				<a href="/ships/%s" class="flex flex-col justify-center items-center p-4 border border-solid border-neutral-300 hover:bg-neutral-200/10">
-					<div class="flex flex-row justify-between items-center gap-2"><div class="text-xl text-bold">%s</div><span class="text-sm">(⛽%d/%d)</span></div>
-					<div>Current Location: %s</div>
-					<div>Cargo: %d / %d</div>
-				</a>`,
-				shipList, ship.Symbol, ship.Symbol, ship.Fuel.Current, ship.Fuel.Capacity, ship.Nav.WaypointSymbol, ship.Cargo.Units, ship.Cargo.Capacity)
+		fleet, err := api.GetShips()
+		// Failed to get the fleet
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
 		}
-		shipList = fmt.Sprintf(`%s</div>`, shipList)
 
-		contractList := `<div class="flex flex-row flex-wrap justify-start items-center gap-4">`
-		for _, contract := range contracts {
-			deliveries := `<div class="w-full flex flex-col justify-start items-start">`
-			for _, delivery := range contract.Terms.Deliver {
-				deliveries = fmt.Sprintf(`%s
-				<div class="flex flex-row justify-start items-center gap-2">
-					<span>%d</span>
-					<span>%s</span>
-				</div>`,
-					deliveries, delivery.UnitsRequired, delivery.TradeSymbol)
+		// Only ships docked right here can drop materials off
+		docked := []spacetrader.Ship{}
+		for _, ship := range fleet {
+			if ship.Nav.WaypointSymbol == waypointSymbol && ship.Nav.Status == spacetrader.ShipNavStatusDocked {
+				docked = append(docked, ship)
 			}
-			deliveries = fmt.Sprintf(`%s</div>`, deliveries)
+		}
 
-			contractList = fmt.Sprintf(`%s
-				<div class="flex flex-col justify-start items-center p-4 border border-solid border-neutral-300 hover:bg-neutral-200/10">
-					<div class="flex flex-row w-full gap-2">
-						<span class="text-2xl text-red-600 font-bold">X</span>
-						<span class="text-2xl text-neutral-200 font-bold">%s</span>
-						<span class="text-2xl text-green-600 font-bold">\,</span>
+		materials := `<div class="w-full flex flex-row justify-between items-center font-bold"><div class="w-1/3">Material</div><div class="w-1/3">Progress</div><div class="w-1/3 text-right">Fulfilled / Required</div></div>`
+		for _, material := range construction.Materials {
+			progress := 100.0
+			if material.Required > 0 {
+				progress = float64(material.Fulfilled) / float64(material.Required) * 100
+			}
+
+			supplies := ""
+			for _, ship := range docked {
+				for _, cargo := range ship.Cargo.Inventory {
+					if cargo.Symbol != material.TradeSymbol || material.Remaining() == 0 {
+						continue
+					}
+					units := min(cargo.Units, material.Remaining())
+					supplies = fmt.Sprintf(`%s
+						<form class="w-full flex flex-row justify-end items-center gap-1" hx-post="/system/%s/waypoint/%s/construction:supply" hx-target="#construction-result">
+							<input type="hidden" name="ship" value="%s" />
+							<input type="hidden" name="symbol" value="%s" />
+							<span class="text-sm">%s has %d</span>
+							<input type="number" name="units" min="1" max="%d" value="%d" class="w-16 bg-gray-800 text-neutral-200" />
+							<button type="submit" class="text-sm hover:underline">Supply</button>
+						</form>`, supplies, systemSymbol, waypointSymbol, ship.Symbol, cargo.Symbol, ship.Symbol, cargo.Units, units, units)
+				}
+			}
+
+			materials = fmt.Sprintf(`%s
+				<div class="w-full flex flex-col justify-start items-center border-t border-solid border-neutral-500 py-1">
+					<div class="w-full flex flex-row justify-between items-center">
+						<div class="w-1/3">%s</div>
+						<div class="w-1/3 h-3 bg-neutral-600"><div class="h-3 bg-green-600" style="width: %.0f%%"></div></div>
+						<div class="w-1/3 text-right">%d / %d</div>
 					</div>
 					%s
-				</div>`,
-				contractList, contract.Type, deliveries)
+				</div>`, materials, material.TradeSymbol, progress, material.Fulfilled, material.Required, supplies)
+		}
+
+		status := "Under construction"
+		if construction.IsComplete {
+			status = "Complete"
 		}
-		contractList = fmt.Sprintf(`%s</div>`, contractList)
 
 		content := fmt.Sprintf(`
 			<div class="flex flex-col max-w-[960px] w-full justify-start items-center">	
-				<div class="w-full flex flex-row justify-start items-center px-4 text-2xl text-neutral-200">Welcome %s!</div>
-				<div class="w-full flex flex-row justify-start items-center px-4 text-xl text-neutral-200">Credits: %d</div>
-				<div class="w-full p-4">
-					<span class="text-2xl">Ships:</span>
-					%s
-				</div>
-				<div class="w-full p-4">
-					<span class="text-2xl">Contracts:</span>
+				<div class="w-full flex flex-row justify-start items-center px-4 text-2xl text-neutral-200">Construction: %s</div>
+				<div class="w-full flex flex-row justify-start items-center px-4 text-neutral-200">System: <a href="/system/%s" class="px-1 hover:underline">%s</a></div>
+				<div class="w-full flex flex-row justify-start items-center px-4 text-neutral-200">Status: %s</div>
+				<div class="w-full flex flex-row justify-start items-center px-4 text-neutral-200">Ships docked here: %d</div>
+				<div class="w-full flex flex-col justify-start items-center p-4">
 					%s
+					<div id="construction-result" class="w-full"></div>
 				</div>
 			</div>`,
-			agent.Symbol,
-			agent.Credits,
-			shipList,
-			contractList)
+			construction.Symbol,
+			systemSymbol,
+			systemSymbol,
+			status,
+			len(docked),
+			materials,
+		)
 		laidOut, err := builder.Layout_Main(content)
 
 		// If the layout fails to build
 		if err != nil {
-			log.Fatal(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
 
-		page, err := builder.Document("Space Trader", laidOut)
+		page, err := builder.Document("Space Trader - Construction", laidOut)
 
 		// If the document fails to build
 		if err != nil {
-			log.Fatal(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
 
 		w.Write([]byte(page))
 	})
-	r.Get("/ships/{shipSymbol}", func(w http.ResponseWriter, r *http.Request) {
-		shipSymbol := chi.URLParam(r, "shipSymbol")
-		ship, err := spacetrader.GetShip(authToken, shipSymbol)
-		// Failed to get the ship
+	r.Post("/system/{system}/waypoint/{waypoint}/construction:supply", func(w http.ResponseWriter, r *http.Request) {
+		systemSymbol := chi.URLParam(r, "system")
+		waypointSymbol := chi.URLParam(r, "waypoint")
+		tradeSymbol, units, err := cargoAction(r)
 		if err != nil {
-			log.Fatal(err)
+			writeActionResult(w, "", err)
+			return
 		}
 
-		system, err := spacetrader.GetSystem(authToken, ship.Nav.SystemSymbol)
-		// Failed to get the system
+		shipSymbol := r.FormValue("ship")
+		_, err = api.SupplyConstruction(systemSymbol, waypointSymbol, shipSymbol, tradeSymbol, units)
+		writeActionResult(w, fmt.Sprintf("%s supplied %d %s", shipSymbol, units, tradeSymbol), err)
+	})
+	r.Get("/system/{system}", func(w http.ResponseWriter, r *http.Request) {
+		systemSymbol := chi.URLParam(r, "system")
+		system, err := api.GetSystem(systemSymbol)
 		if err != nil {
 			log.Fatal(err)
 		}
 
-		cargoManifest := `<div class="w-full flex flex-row justify-between items-center"><div>Name</div><div>Quantity</div></div>`
-		for _, cargo := range ship.Cargo.Inventory {
-			cargoManifest = fmt.Sprintf(`%s
-				<div class="w-full flex flex-row justify-between items-center">
-					<div>%s</div><div>%d</div>
-				</div>`, cargoManifest, cargo.Name, cargo.Units)
+		traitOptions := []string{}
+		for _, trait := range spacetrader.WaypointTraitSymbols {
+			traitOptions = append(traitOptions, string(trait))
 		}
+		traitSelect, err := builder.Select("trait", traitOptions, "", fmt.Sprintf(`hx-get="/system/%s/waypoints" hx-target="#waypoints"`, system.Symbol))
 
-		travelManifest := `<div class="w-full flex flex-row justify-between items-center"><div>Name</div><div>Distance</div></div>`
-		for _, waypoint := range system.Waypoints {
-			howFar := distance(float64(ship.Nav.Route.Destination.PosX), float64(ship.Nav.Route.Destination.PosY), float64(waypoint.PosX), float64(waypoint.PosY))
+		// If the dropdown fails to build
+		if err != nil {
+			log.Fatal(err)
+		}
 
-			// Use flex order style to sort the list by distance from the ship
-			if howFar <= float64(ship.Fuel.Current) {
-				travelManifest = fmt.Sprintf(`%s
-				<div class="w-full flex flex-row justify-between items-center order-[%d]">
-					<div class="hover:underline cursor-pointer" hx-get="/system/%s/waypoint/%s/%s:fragment" hx-target="#viewer">%s (%d,%d)</div><div>%f</div>
-				</div>`, travelManifest, int(math.Round(howFar)), ship.Nav.SystemSymbol, waypoint.Symbol, ship.Symbol, waypoint.Symbol, waypoint.PosX, waypoint.PosY, howFar)
-			} else {
-				travelManifest = fmt.Sprintf(`%s
-				<div class="w-full flex flex-row justify-between items-center text-red-600 order-[%d]">
-					<div class="hover:underline cursor-pointer" hx-get="/system/%s/waypoint/%s/%s:fragment" hx-target="#viewer">%s (%d,%d)</div><div>%f</div>
-				</div>`, travelManifest, int(math.Round(howFar)), ship.Nav.SystemSymbol, waypoint.Symbol, ship.Symbol, waypoint.Symbol, waypoint.PosX, waypoint.PosY, howFar)
-			}
+		ships, err := api.GetShips()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
 		}
 
-		shipNav, err := spacetrader.DisplayShipNav(authToken, ship.Symbol)
+		shipOptions := []string{}
+		for _, ship := range ships {
+			shipOptions = append(shipOptions, ship.Symbol)
+		}
+		shipSelect, err := builder.Select("shipSymbol", shipOptions, "", "")
 
+		// If the dropdown fails to build
 		if err != nil {
-			log.Fatal("Could not retrieve ship")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
 
 		content := fmt.Sprintf(`
 			<div class="flex flex-col max-w-[960px] w-full justify-start items-center">	
-				<div class="w-full flex flex-row justify-start items-center px-4 text-4xl text-neutral-200 underline">%s</div>
-				<div class="w-full flex flex-row justify-start items-center px-4 text-neutral-200 font-bold">Location: %s</div>
-				%s
-				<div class="w-full flex flex-row justify-start items-center px-4 text-neutral-200">Fuel: %d/%d</div>
-				<div class="w-full flex flex-row justify-around items-start gap-2">
-					<div class="w-full p-2 flex flex-col justify-start items-center border border-solid border-neutral-200">
-						<span class="text-bold">CARGO</span>
-						%s
-					</div>
-					<div class="w-full max-h-64 overflow-auto p-2 flex flex-col justify-start items-center border border-solid border-neutral-200">
-						<span class="text-bold">TRAVEL</span>
-						%s
-					</div>
-				</div>
-				<div id="viewer" class="w-full"></div>
+				<div class="w-full flex flex-row justify-start items-center px-4 text-2xl text-neutral-200">System: %s</div>
+				<div class="w-full flex flex-row justify-start items-center px-4 text-neutral-200">Waypoints: %d</div>
+				<div class="w-full flex flex-row justify-start items-center gap-2 px-4 text-neutral-200">Send a ship here: %s <button class="hover:underline" hx-get="/system/%s/voyage:plan" hx-include="[name='shipSymbol']" hx-target="#voyage-plan">Plan voyage</button></div>
+				<div id="voyage-plan" class="w-full px-4"></div>
+				<div class="w-full flex flex-row justify-start items-center gap-2 px-4 text-neutral-200">Find waypoints with trait: %s <a href="/system/%s/waypoints/search" class="hover:underline">(Advanced search)</a></div>
+				<div id="waypoints" class="w-full p-4"></div>
 			</div>`,
-			ship.Symbol,
-			ship.Nav.WaypointSymbol,
-			shipNav,
-			ship.Fuel.Current,
-			ship.Fuel.Capacity,
-			cargoManifest,
-			travelManifest,
+			system.Symbol,
+			len(system.Waypoints),
+			shipSelect,
+			system.Symbol,
+			traitSelect,
+			system.Symbol,
 		)
 		laidOut, err := builder.Layout_Main(content)
 
@@ -217,119 +2190,147 @@ func main() {
 
 		w.Write([]byte(page))
 	})
-	r.Get("/ships/{shipSymbol}/nav:fragment", func(w http.ResponseWriter, r *http.Request) {
-		shipSymbol := chi.URLParam(r, "shipSymbol")
-		shipNav, err := spacetrader.DisplayShipNav(authToken, shipSymbol)
-
-		if err != nil {
-			log.Fatal("Could not retrieve ship")
+	r.Get("/system/{system}/voyage:plan", func(w http.ResponseWriter, r *http.Request) {
+		systemSymbol := chi.URLParam(r, "system")
+		shipSymbol := r.URL.Query().Get("shipSymbol")
+		if shipSymbol == "" {
+			http.Error(w, "shipSymbol is required", http.StatusBadRequest)
+			return
 		}
 
-		w.Write([]byte(shipNav))
-	})
-	r.Post("/ships/{shipSymbol}:launch", func(w http.ResponseWriter, r *http.Request) {
-		shipSymbol := chi.URLParam(r, "shipSymbol")
-		success, err := spacetrader.LaunchToOrbit(authToken, shipSymbol)
+		route, ready, err := planRoute(api, voyages, shipSymbol, systemSymbol)
 		if err != nil {
-			log.Fatal(err)
+			writeActionResult(w, "", err)
+			return
+		}
+		if !ready {
+			w.Write([]byte(planning(r.URL.String(), systemSymbol)))
+			return
 		}
 
-		successFragment := fmt.Sprintf(`<div class="font-bold" hx-on:htmx:afterSettle="/ships/%s/nav:fragment">%t</div>`, shipSymbol, success)
+		// Flying it goes through the autopilot, which picks this same plan back up
+		laidOut, err := builder.Layout_Fragment(routePlan(shipSymbol, systemSymbol, route))
 
-		laidOut, err := builder.Layout_Fragment(successFragment)
+		// If the layout fails to build
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
 		w.Write([]byte(laidOut))
 	})
-	r.Post("/ships/{shipSymbol}:dock", func(w http.ResponseWriter, r *http.Request) {
-		shipSymbol := chi.URLParam(r, "shipSymbol")
-		success, err := spacetrader.DockShip(authToken, shipSymbol)
-		if err != nil {
-			log.Fatal(err)
-		}
+	r.Get("/system/{system}/waypoints/search", func(w http.ResponseWriter, r *http.Request) {
+		system := chi.URLParam(r, "system")
 
-		successFragment := fmt.Sprintf(`<div class="font-bold" hx-on:htmx:afterSettle="/ships/%s/nav:fragment">%t</div>`, shipSymbol, success)
+		typeOptions := []string{""}
+		for _, waypointType := range spacetrader.WaypointTypes {
+			typeOptions = append(typeOptions, string(waypointType))
+		}
+		typeSelect, err := builder.Select("type", typeOptions, "", "")
 
-		laidOut, err := builder.Layout_Fragment(successFragment)
+		// If the dropdown fails to build
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
-		w.Write([]byte(laidOut))
-	})
-	r.Post("/ships/{shipSymbol}:navigate", func(w http.ResponseWriter, r *http.Request) {
-		shipSymbol := chi.URLParam(r, "shipSymbol")
-		// Closing the connection seems important and stuff
-		defer r.Body.Close()
-		// Grab the deets
-		body, err := io.ReadAll(r.Body)
+		traitOptions := []string{}
+		for _, trait := range spacetrader.WaypointTraitSymbols {
+			traitOptions = append(traitOptions, string(trait))
+		}
+		traitBoxes, err := builder.Checkboxes("trait", traitOptions, []string{})
 
+		// If the checkboxes fail to build
 		if err != nil {
-			log.Fatal("No destination provided")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
 
-		fmt.Println(shipSymbol)
-		fmt.Println(body)
+		content := fmt.Sprintf(`
+			<div class="flex flex-col max-w-[960px] w-full justify-start items-center">	
+				<div class="w-full flex flex-row justify-start items-center px-4 text-2xl text-neutral-200">Search waypoints in <a href="/system/%s" class="hover:underline">%s</a></div>
+				<form class="w-full flex flex-col justify-start items-start gap-2 p-4" hx-get="/system/%s/waypoints" hx-target="#waypoints">
+					<div class="flex flex-row justify-start items-center gap-2">Type: %s</div>
+					<div class="w-full">Traits (waypoints must have all of them):</div>
+					%s
+					<button type="submit" class="px-4 py-1 border border-solid border-neutral-300 hover:bg-neutral-200/10">Search</button>
+				</form>
+				<div id="waypoints" class="w-full p-4"></div>
+			</div>`,
+			system,
+			system,
+			system,
+			typeSelect,
+			traitBoxes,
+		)
+		laidOut, err := builder.Layout_Main(content)
 
-		//success, err := spacetrader.DockShip(authToken, shipSymbol)
-		//if err != nil {
-		//	log.Fatal(err)
-		//}
+		// If the layout fails to build
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
-		//successFragment := fmt.Sprintf(`<div class="font-bold" hx-on:htmx:afterSettle="/ships/%s/nav:fragment">%t</div>`, shipSymbol, success)
+		page, err := builder.Document("Space Trader - Waypoint Search", laidOut)
 
-		//laidOut, err := builder.Layout_Fragment(successFragment)
+		// If the document fails to build
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
-		w.Write([]byte("NAVIGATE"))
+		w.Write([]byte(page))
 	})
-	// htmx fragments use the :fragment identifier on the end
-	r.Get("/system/{system}/waypoint/{waypoint}/{shipSymbol}:fragment", func(w http.ResponseWriter, r *http.Request) {
-		systemSymbol := chi.URLParam(r, "system")
-		waypointSymbol := chi.URLParam(r, "waypoint")
-		shipSymbol := chi.URLParam(r, "shipSymbol")
-		waypoint, err := spacetrader.GetWaypoint(authToken, systemSymbol, waypointSymbol)
+	r.Get("/system/{system}/waypoints", func(w http.ResponseWriter, r *http.Request) {
+		system := chi.URLParam(r, "system")
+		query, err := waypointQuery(r.URL.Query())
+		// Don't bother the API with a type or trait it has never heard of
 		if err != nil {
-			log.Fatal(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
 
-		traits := ""
-		for _, trait := range waypoint.Traits {
-			traits = fmt.Sprintf(`%s
-				<div class="w-full flex flex-col justify-center items-start">
-					<div class="font-bold">%s</div>
-					<div>%s</div>
-				</div>`, traits, trait.Name, trait.Description)
+		waypoints, meta, err := api.GetWaypoints(system, query)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
 		}
 
-		content := fmt.Sprintf(`
-			<div class="flex flex-col max-w-[960px] w-full justify-start items-center">	
-			<div class="w-full flex flex-row justify-start items-center text-2xl text-neutral-200">Waypoint: %s (<a class="hover:underline cursor-pointer" hx-post="/ships/%s:navigate" hx-include="#waypoint_symbol">Navigate to this waypoint</a>)<input type="hidden" id="waypoint_symbol" value="%s" /></div>
-				<div class="w-full flex flex-col justify-start items-center>%s</div>
-			</div>`,
-			waypoint.Symbol,
-			shipSymbol,
-			waypoint.Symbol,
-			traits,
-		)
-		page, err := builder.Layout_Fragment(content)
+		results := fmt.Sprintf(`%s%s`, waypointList(waypoints, meta), pager(r.URL.Path, r.URL.Query(), meta, "#waypoints"))
+		page, err := builder.Layout_Fragment(results)
 
 		// If the layout fails to build
 		if err != nil {
-			log.Fatal(err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
 
 		w.Write([]byte(page))
 	})
-	r.Get("/system/{system}", func(w http.ResponseWriter, r *http.Request) {
-		systemSymbol := chi.URLParam(r, "system")
-		system, err := spacetrader.GetSystem(authToken, systemSymbol)
+	r.Get("/{system}/waypoints/{kind}", func(w http.ResponseWriter, r *http.Request) {
+		system := chi.URLParam(r, "system")
+		kind, err := spacetrader.ParseWaypointTraitSymbol(chi.URLParam(r, "kind"))
+		// Don't bother the API with a trait it has never heard of
 		if err != nil {
-			log.Fatal(err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		waypoints, meta, err := api.GetWaypoints(system, spacetrader.WaypointQuery{Traits: []spacetrader.WaypointTraitSymbol{kind}})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
 		}
 
 		content := fmt.Sprintf(`
 			<div class="flex flex-col max-w-[960px] w-full justify-start items-center">	
-				<div class="w-full flex flex-row justify-start items-center px-4 text-2xl text-neutral-200">System: %s</div>
-				<div class="w-full flex flex-row justify-start items-center px-4 text-neutral-200">Waypoints: %d</div>
+				<div class="w-full flex flex-row justify-start items-center px-4 text-2xl text-neutral-200">%s waypoints in <a href="/system/%s" class="hover:underline">%s</a></div>
+				<div class="w-full p-4">%s</div>
 			</div>`,
-			system.Symbol,
-			len(system.Waypoints),
+			kind,
+			system,
+			system,
+			waypointList(waypoints, meta),
 		)
 		laidOut, err := builder.Layout_Main(content)
 
@@ -338,7 +2339,7 @@ func main() {
 			log.Fatal(err)
 		}
 
-		page, err := builder.Document("Space Trader - System", laidOut)
+		page, err := builder.Document("Space Trader - Waypoints", laidOut)
 
 		// If the document fails to build
 		if err != nil {
@@ -347,16 +2348,6 @@ func main() {
 
 		w.Write([]byte(page))
 	})
-	r.Get("/{system}/waypoints/{kind}", func(w http.ResponseWriter, r *http.Request) {
-		system := chi.URLParam(r, "system")
-		kind := chi.URLParam(r, "kind")
-		message, err := spacetrader.GetWaypoints(authToken, system, kind)
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		w.Write([]byte(message))
-	})
 
 	// Create a route along /files that will serve contents from
 	// the ./data/ folder.
@@ -364,7 +2355,7 @@ func main() {
 	filesDir := http.Dir(filepath.Join(workDir, "static"))
 	FileServer(r, "/plugins", filesDir)
 
-	http.ListenAndServe(":3000", r)
+	return r
 }
 
 // FileServer conveniently sets up a http.FileServer handler to serve
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"example.com/spacetrader"
+	"example.com/spacetrader/autopilot"
+	"example.com/spacetrader/fake"
+	"example.com/spacetrader/fleet"
+	"example.com/spacetrader/history"
+	"example.com/spacetrader/mining"
+	"example.com/spacetrader/queue"
+)
+
+// testRouter wires the router up the way main does, against a fresh fake universe and with
+// everything that saves to disk kept in a temporary directory
+func testRouter(t *testing.T) (chi.Router, *fake.Server) {
+	t.Helper()
+
+	universe := fake.New()
+	server := httptest.NewServer(universe)
+	baseURL := spacetrader.BaseURL
+	spacetrader.BaseURL = server.URL
+	t.Cleanup(func() {
+		server.Close()
+		spacetrader.BaseURL = baseURL
+	})
+
+	dir := t.TempDir()
+	prices, err := history.Open(filepath.Join(dir, "market-history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { prices.Close() })
+
+	api := spacetrader.NewCachedAPI(history.Record(spacetrader.NewClient(fake.Token), prices), spacetrader.DefaultCachePolicy)
+	pilot, err := autopilot.New(api, filepath.Join(dir, "autopilot.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pilot.Stop)
+
+	miner := mining.New(api, pilot, prices)
+	t.Cleanup(miner.Shutdown)
+
+	tasks, err := queue.Open(filepath.Join(dir, "tasks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	miner.Queue(tasks)
+	scheduler := fleet.New(api, pilot, miner, prices, tasks)
+	tasks.Start()
+	t.Cleanup(tasks.Shutdown)
+
+	return newRouter(api, pilot, miner, scheduler, tasks, prices), universe
+}
+
+// serve runs a single request through the router and hands back the status and body
+func serve(t *testing.T, router http.Handler, method string, target string) (int, string) {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(method, target, nil))
+	body, _ := io.ReadAll(recorder.Result().Body)
+
+	return recorder.Code, string(body)
+}
+
+func TestDashboardListsShips(t *testing.T) {
+	router, _ := testRouter(t)
+
+	status, body := serve(t, router, http.MethodGet, "/")
+	if status != http.StatusOK {
+		t.Fatalf("got status %d: %s", status, body)
+	}
+	for _, ship := range []string{"FAKE-AGENT-1", "FAKE-AGENT-2"} {
+		if !strings.Contains(body, ship) {
+			t.Errorf("dashboard doesn't list %s", ship)
+		}
+	}
+}
+
+func TestLaunchAndDock(t *testing.T) {
+	router, universe := testRouter(t)
+
+	if status, body := serve(t, router, http.MethodPost, "/ships/FAKE-AGENT-1:launch"); status != http.StatusOK {
+		t.Fatalf("launch got status %d: %s", status, body)
+	}
+	if ship, _ := universe.Ship("FAKE-AGENT-1"); ship.Nav.Status != spacetrader.ShipNavStatusInOrbit {
+		t.Errorf("ship is %s after launching, want IN_ORBIT", ship.Nav.Status)
+	}
+
+	if status, body := serve(t, router, http.MethodPost, "/ships/FAKE-AGENT-1:dock"); status != http.StatusOK {
+		t.Fatalf("dock got status %d: %s", status, body)
+	}
+	if ship, _ := universe.Ship("FAKE-AGENT-1"); ship.Nav.Status != spacetrader.ShipNavStatusDocked {
+		t.Errorf("ship is %s after docking, want DOCKED", ship.Nav.Status)
+	}
+}
+
+func TestRoutePlan(t *testing.T) {
+	router, _ := testRouter(t)
+
+	// The asteroids are 50 out and the tank holds 400, so it's worth burning there
+	status, body := serve(t, router, http.MethodGet, "/ships/FAKE-AGENT-1/route:plan?destination=X1-TEST-B7")
+	if status != http.StatusOK {
+		t.Fatalf("got status %d: %s", status, body)
+	}
+	if !strings.Contains(body, "X1-TEST-B7") || !strings.Contains(body, string(spacetrader.FlightModeBurn)) {
+		t.Errorf("plan doesn't burn to X1-TEST-B7: %s", body)
+	}
+
+	if status, _ := serve(t, router, http.MethodGet, "/ships/FAKE-AGENT-1/route:plan"); status != http.StatusBadRequest {
+		t.Errorf("plan without a destination got status %d, want %d", status, http.StatusBadRequest)
+	}
+}
+
+func TestRoutePlanMarksEstimates(t *testing.T) {
+	navigate := spacetrader.RouteHop{From: "X1-TEST-A1", To: "X1-TEST-B7", Mode: spacetrader.FlightModeCruise, Duration: 57 * time.Second}
+	warp := spacetrader.RouteHop{Action: spacetrader.HopWarp, From: "X1-TEST-A1", To: "X1-NEAR-A1", Mode: spacetrader.FlightModeCruise, Duration: 90 * time.Second}
+	jump := spacetrader.RouteHop{Action: spacetrader.HopJump, From: "X1-NEAR-I1", To: "X1-FAR-I4", Cooldown: time.Minute}
+
+	cases := []struct {
+		name      string
+		hops      []spacetrader.RouteHop
+		estimated []string
+	}{
+		{"navigating is worked out the game's way", []spacetrader.RouteHop{navigate}, nil},
+		{"a warp is a guess", []spacetrader.RouteHop{warp}, []string{"~1m30s", "~1m30s, 0 credits"}},
+		{"so is a jump's cooldown", []spacetrader.RouteHop{navigate, jump}, []string{"~1m0s cooldown", "~1m57s, 0 credits"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			route := spacetrader.Route{Hops: c.hops}
+			for _, hop := range c.hops {
+				route.Duration += hop.Duration + hop.Cooldown
+			}
+			body := routePlan("FAKE-AGENT-1", "X1-FAR-I4", route)
+
+			if noted := strings.Contains(body, "are estimates"); noted != (len(c.estimated) > 0) {
+				t.Errorf("plan notes estimates: %t, want %t", noted, len(c.estimated) > 0)
+			}
+			if c.estimated == nil && strings.Contains(body, "~") {
+				t.Errorf("plan marks something as a guess: %s", body)
+			}
+			for _, want := range c.estimated {
+				if !strings.Contains(body, want) {
+					t.Errorf("plan doesn't show %q: %s", want, body)
+				}
+			}
+		})
+	}
+}
+
+func TestVoyagePlansArePruned(t *testing.T) {
+	voyages := newVoyagePlans(nil)
+	stale := time.Now().Add(-voyagePlanLife - time.Second)
+	voyages.plans = map[string]*voyagePlan{
+		voyageKey("SHIP-1", "X1-A"): {done: true, finished: stale},
+		voyageKey("SHIP-1", "X1-B"): {done: true, finished: time.Now()},
+		voyageKey("SHIP-2", "X1-A"): {},
+		voyageKey("SHIP-2", "X1-B"): {done: true, finished: time.Now()},
+	}
+
+	voyages.flown("SHIP-2", "X1-B")
+	voyages.mu.Lock()
+	voyages.prune()
+	voyages.mu.Unlock()
+
+	cases := []struct {
+		shipSymbol  string
+		destination string
+		kept        bool
+	}{
+		{"SHIP-1", "X1-A", false},
+		{"SHIP-1", "X1-B", true},
+		// Still being worked out, however long it's taking
+		{"SHIP-2", "X1-A", true},
+		{"SHIP-2", "X1-B", false},
+	}
+	for _, c := range cases {
+		if _, kept := voyages.plans[voyageKey(c.shipSymbol, c.destination)]; kept != c.kept {
+			t.Errorf("%s's plan for %s kept: %t, want %t", c.shipSymbol, c.destination, kept, c.kept)
+		}
+	}
+}
+
+func TestMarketPage(t *testing.T) {
+	router, _ := testRouter(t)
+
+	status, body := serve(t, router, http.MethodGet, "/system/X1-TEST/waypoint/X1-TEST-A1/market")
+	if status != http.StatusOK {
+		t.Fatalf("got status %d: %s", status, body)
+	}
+	if !strings.Contains(body, string(spacetrader.TradeFuel)) {
+		t.Errorf("market page doesn't list FUEL")
+	}
+
+	// A market the API won't hand over is reported rather than taking the server down
+	if status, _ := serve(t, router, http.MethodGet, "/system/X1-TEST/waypoint/X1-TEST-ZZ/market"); status != http.StatusBadGateway {
+		t.Errorf("missing market got status %d, want %d", status, http.StatusBadGateway)
+	}
+
+	// Only real goods get charted, anything else in the query is turned away before it reaches the page
+	status, body = serve(t, router, http.MethodGet, "/system/X1-TEST/waypoint/X1-TEST-A1/market?good=%3Cscript%3Ealert(1)%3C/script%3E")
+	if status != http.StatusBadRequest {
+		t.Errorf("charting a made up good got status %d, want %d", status, http.StatusBadRequest)
+	}
+	if strings.Contains(body, "<script>") {
+		t.Errorf("made up good was echoed back unescaped: %s", body)
+	}
+}
@@ -0,0 +1,244 @@
+package spacetrader
+
+// The SpaceTraders API is split up by area so code can ask for only the slice it needs.
+// API pulls them all together. Decorators (caching, logging, etc) can embed an API and
+// only override the calls they care about.
+
+type AgentAPI interface {
+	ShowAgent() (Agent, error)
+}
+
+type FleetAPI interface {
+	GetShips() ([]Ship, error)
+	GetShip(shipSymbol string) (Ship, error)
+	GetMounts(shipSymbol string) ([]ShipMount, error)
+	InstallMount(shipSymbol string, mountSymbol TradeSymbol) (MountChange, error)
+	RemoveMount(shipSymbol string, mountSymbol TradeSymbol) (MountChange, error)
+	GetModules(shipSymbol string) ([]ShipModule, error)
+	InstallModule(shipSymbol string, moduleSymbol TradeSymbol) (ModuleChange, error)
+	RemoveModule(shipSymbol string, moduleSymbol TradeSymbol) (ModuleChange, error)
+	GetRepairShip(shipSymbol string) (MaintenanceTransaction, error)
+	RepairShip(shipSymbol string) (ShipRepair, error)
+	GetScrapShip(shipSymbol string) (MaintenanceTransaction, error)
+	ScrapShip(shipSymbol string) (ShipScrap, error)
+	JettisonCargo(shipSymbol string, tradeSymbol TradeSymbol, units int) (ShipCargo, error)
+	TransferCargo(shipSymbol string, tradeSymbol TradeSymbol, units int, toShipSymbol string) (ShipCargo, error)
+	RefineCargo(shipSymbol string, produce TradeSymbol) (Refinement, error)
+	SiphonResources(shipSymbol string) (Siphon, error)
+	CreateSurvey(shipSymbol string) (ShipSurvey, error)
+	ExtractResources(shipSymbol string, survey *Survey) (Extract, error)
+}
+
+type NavigationAPI interface {
+	LaunchToOrbit(shipSymbol string) (bool, error)
+	DockShip(shipSymbol string) (bool, error)
+	NavigateShip(shipSymbol string, waypointSymbol string) (bool, error)
+	SetFlightMode(shipSymbol string, mode FlightMode) (ShipNav, error)
+	RefuelShip(shipSymbol string, units int) (ShipRefuel, error)
+	JumpShip(shipSymbol string, waypointSymbol string) (ShipJump, error)
+	WarpShip(shipSymbol string, waypointSymbol string) (ShipTransit, error)
+}
+
+type SystemsAPI interface {
+	ListSystems(page int, limit int) ([]System, Meta, error)
+	GetSystem(systemSymbol string) (System, error)
+	GetWaypoints(systemSymbol string, query WaypointQuery) ([]Waypoint, Meta, error)
+	GetWaypoint(systemSymbol string, waypointSymbol string) (Waypoint, error)
+	GetConstructionSite(systemSymbol string, waypointSymbol string) (Construction, error)
+	GetJumpGate(systemSymbol string, waypointSymbol string) (JumpGate, error)
+	SupplyConstruction(systemSymbol string, waypointSymbol string, shipSymbol string, tradeSymbol TradeSymbol, units int) (ConstructionSupply, error)
+}
+
+type MarketAPI interface {
+	GetMarket(systemSymbol string, waypointSymbol string) (Market, error)
+	SellCargo(shipSymbol string, tradeSymbol TradeSymbol, units int) (CargoTrade, error)
+	PurchaseCargo(shipSymbol string, tradeSymbol TradeSymbol, units int) (CargoTrade, error)
+}
+
+type ContractsAPI interface {
+	GetContracts() ([]Contract, error)
+	AcceptContract(contractId string) (ContractUpdate, error)
+	DeliverContract(contractId string, shipSymbol string, tradeSymbol TradeSymbol, units int) (ContractDeliveryUpdate, error)
+	FulfillContract(contractId string) (ContractUpdate, error)
+}
+
+// API is everything we can do with the SpaceTraders API
+type API interface {
+	AgentAPI
+	FleetAPI
+	NavigationAPI
+	SystemsAPI
+	MarketAPI
+	ContractsAPI
+}
+
+// Client is the real API, it hangs on to the agent token so callers don't have to pass it around
+type Client struct {
+	Token string
+}
+
+var _ API = (*Client)(nil)
+
+func NewClient(token string) *Client {
+	return &Client{Token: token}
+}
+
+func (c *Client) ShowAgent() (Agent, error) {
+	return ShowAgent(c.Token)
+}
+
+func (c *Client) GetShips() ([]Ship, error) {
+	return GetShips(c.Token)
+}
+
+func (c *Client) GetShip(shipSymbol string) (Ship, error) {
+	return GetShip(c.Token, shipSymbol)
+}
+
+func (c *Client) GetMounts(shipSymbol string) ([]ShipMount, error) {
+	return GetMounts(c.Token, shipSymbol)
+}
+
+func (c *Client) InstallMount(shipSymbol string, mountSymbol TradeSymbol) (MountChange, error) {
+	return InstallMount(c.Token, shipSymbol, mountSymbol)
+}
+
+func (c *Client) RemoveMount(shipSymbol string, mountSymbol TradeSymbol) (MountChange, error) {
+	return RemoveMount(c.Token, shipSymbol, mountSymbol)
+}
+
+func (c *Client) GetModules(shipSymbol string) ([]ShipModule, error) {
+	return GetModules(c.Token, shipSymbol)
+}
+
+func (c *Client) InstallModule(shipSymbol string, moduleSymbol TradeSymbol) (ModuleChange, error) {
+	return InstallModule(c.Token, shipSymbol, moduleSymbol)
+}
+
+func (c *Client) RemoveModule(shipSymbol string, moduleSymbol TradeSymbol) (ModuleChange, error) {
+	return RemoveModule(c.Token, shipSymbol, moduleSymbol)
+}
+
+func (c *Client) GetRepairShip(shipSymbol string) (MaintenanceTransaction, error) {
+	return GetRepairShip(c.Token, shipSymbol)
+}
+
+func (c *Client) RepairShip(shipSymbol string) (ShipRepair, error) {
+	return RepairShip(c.Token, shipSymbol)
+}
+
+func (c *Client) GetScrapShip(shipSymbol string) (MaintenanceTransaction, error) {
+	return GetScrapShip(c.Token, shipSymbol)
+}
+
+func (c *Client) ScrapShip(shipSymbol string) (ShipScrap, error) {
+	return ScrapShip(c.Token, shipSymbol)
+}
+
+func (c *Client) JettisonCargo(shipSymbol string, tradeSymbol TradeSymbol, units int) (ShipCargo, error) {
+	return JettisonCargo(c.Token, shipSymbol, tradeSymbol, units)
+}
+
+func (c *Client) TransferCargo(shipSymbol string, tradeSymbol TradeSymbol, units int, toShipSymbol string) (ShipCargo, error) {
+	return TransferCargo(c.Token, shipSymbol, tradeSymbol, units, toShipSymbol)
+}
+
+func (c *Client) RefineCargo(shipSymbol string, produce TradeSymbol) (Refinement, error) {
+	return RefineCargo(c.Token, shipSymbol, produce)
+}
+
+func (c *Client) SiphonResources(shipSymbol string) (Siphon, error) {
+	return SiphonResources(c.Token, shipSymbol)
+}
+
+func (c *Client) CreateSurvey(shipSymbol string) (ShipSurvey, error) {
+	return CreateSurvey(c.Token, shipSymbol)
+}
+
+func (c *Client) ExtractResources(shipSymbol string, survey *Survey) (Extract, error) {
+	return ExtractResources(c.Token, shipSymbol, survey)
+}
+
+func (c *Client) LaunchToOrbit(shipSymbol string) (bool, error) {
+	return LaunchToOrbit(c.Token, shipSymbol)
+}
+
+func (c *Client) DockShip(shipSymbol string) (bool, error) {
+	return DockShip(c.Token, shipSymbol)
+}
+
+func (c *Client) NavigateShip(shipSymbol string, waypointSymbol string) (bool, error) {
+	return NavigateShip(c.Token, shipSymbol, waypointSymbol)
+}
+
+func (c *Client) SetFlightMode(shipSymbol string, mode FlightMode) (ShipNav, error) {
+	return SetFlightMode(c.Token, shipSymbol, mode)
+}
+
+func (c *Client) RefuelShip(shipSymbol string, units int) (ShipRefuel, error) {
+	return RefuelShip(c.Token, shipSymbol, units)
+}
+
+func (c *Client) JumpShip(shipSymbol string, waypointSymbol string) (ShipJump, error) {
+	return JumpShip(c.Token, shipSymbol, waypointSymbol)
+}
+
+func (c *Client) WarpShip(shipSymbol string, waypointSymbol string) (ShipTransit, error) {
+	return WarpShip(c.Token, shipSymbol, waypointSymbol)
+}
+
+func (c *Client) ListSystems(page int, limit int) ([]System, Meta, error) {
+	return ListSystems(c.Token, page, limit)
+}
+
+func (c *Client) GetSystem(systemSymbol string) (System, error) {
+	return GetSystem(c.Token, systemSymbol)
+}
+
+func (c *Client) GetWaypoints(systemSymbol string, query WaypointQuery) ([]Waypoint, Meta, error) {
+	return GetWaypoints(c.Token, systemSymbol, query)
+}
+
+func (c *Client) GetWaypoint(systemSymbol string, waypointSymbol string) (Waypoint, error) {
+	return GetWaypoint(c.Token, systemSymbol, waypointSymbol)
+}
+
+func (c *Client) GetConstructionSite(systemSymbol string, waypointSymbol string) (Construction, error) {
+	return GetConstructionSite(c.Token, systemSymbol, waypointSymbol)
+}
+
+func (c *Client) GetJumpGate(systemSymbol string, waypointSymbol string) (JumpGate, error) {
+	return GetJumpGate(c.Token, systemSymbol, waypointSymbol)
+}
+
+func (c *Client) SupplyConstruction(systemSymbol string, waypointSymbol string, shipSymbol string, tradeSymbol TradeSymbol, units int) (ConstructionSupply, error) {
+	return SupplyConstruction(c.Token, systemSymbol, waypointSymbol, shipSymbol, tradeSymbol, units)
+}
+
+func (c *Client) GetMarket(systemSymbol string, waypointSymbol string) (Market, error) {
+	return GetMarket(c.Token, systemSymbol, waypointSymbol)
+}
+
+func (c *Client) SellCargo(shipSymbol string, tradeSymbol TradeSymbol, units int) (CargoTrade, error) {
+	return SellCargo(c.Token, shipSymbol, tradeSymbol, units)
+}
+
+func (c *Client) PurchaseCargo(shipSymbol string, tradeSymbol TradeSymbol, units int) (CargoTrade, error) {
+	return PurchaseCargo(c.Token, shipSymbol, tradeSymbol, units)
+}
+
+func (c *Client) GetContracts() ([]Contract, error) {
+	return GetContracts(c.Token)
+}
+
+func (c *Client) AcceptContract(contractId string) (ContractUpdate, error) {
+	return AcceptContract(c.Token, contractId)
+}
+
+func (c *Client) DeliverContract(contractId string, shipSymbol string, tradeSymbol TradeSymbol, units int) (ContractDeliveryUpdate, error) {
+	return DeliverContract(c.Token, contractId, shipSymbol, tradeSymbol, units)
+}
+
+func (c *Client) FulfillContract(contractId string) (ContractUpdate, error) {
+	return FulfillContract(c.Token, contractId)
+}
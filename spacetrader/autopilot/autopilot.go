@@ -0,0 +1,507 @@
+// Package autopilot flies ships along planned routes without anyone at the controls. For each
+// hop it refuels if the plan says to, launches, sets the flight mode, navigates (or warps, or
+// jumps once the reactor has cooled down) and waits out the trip, then docks once the ship
+// reaches its destination.
+//
+// Journeys are saved to a JSON file as they go, so a restart picks them back up mid-route:
+//
+//	pilot, err := autopilot.New(api, "autopilot.json")
+//	pilot.Resume()
+//	pilot.Start("SHIP-1", route)
+package autopilot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"example.com/spacetrader"
+)
+
+type Status string
+
+const (
+	StatusRunning   Status = "RUNNING"
+	StatusArrived   Status = "ARRIVED"
+	StatusFailed    Status = "FAILED"
+	StatusCancelled Status = "CANCELLED"
+)
+
+// Journey is a ship's trip along a route and how far it has got. Hop is the leg being flown,
+// it only moves on once the ship has actually arrived at the end of it.
+type Journey struct {
+	ShipSymbol string            `json:"shipSymbol"`
+	Route      spacetrader.Route `json:"route"`
+	Hop        int               `json:"hop"`
+	Status     Status            `json:"status"`
+	Error      string            `json:"error,omitempty"`
+	Started    time.Time         `json:"started"`
+	Updated    time.Time         `json:"updated"`
+}
+
+// Destination is where the journey ends up
+func (j Journey) Destination() string {
+	if len(j.Route.Hops) == 0 {
+		return ""
+	}
+
+	return j.Route.Hops[len(j.Route.Hops)-1].To
+}
+
+// Pilot runs every journey, one goroutine per ship
+type Pilot struct {
+	// Now is the clock arrival times are checked against
+	Now func() time.Time
+	// Slack is added on to every wait so the ship has definitely landed when we look again
+	Slack time.Duration
+	// Poll is how often Fly checks whether the journey it's waiting on is over
+	Poll time.Duration
+
+	api  spacetrader.API
+	path string
+
+	mu       sync.Mutex
+	journeys map[string]*Journey
+	cancels  map[string]context.CancelFunc
+	running  sync.WaitGroup
+}
+
+// New loads any journeys saved at path, nothing gets flown until Resume is called
+func New(api spacetrader.API, path string) (*Pilot, error) {
+	p := &Pilot{
+		Now:      time.Now,
+		Slack:    time.Second,
+		Poll:     2 * time.Second,
+		api:      api,
+		path:     path,
+		journeys: map[string]*Journey{},
+		cancels:  map[string]context.CancelFunc{},
+	}
+
+	encoded, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(encoded, &p.journeys); err != nil {
+		return nil, fmt.Errorf("autopilot: could not read %s: %w", path, err)
+	}
+
+	return p, nil
+}
+
+// Resume picks up every journey that was still running when the pilot was last saved
+func (p *Pilot) Resume() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, journey := range p.journeys {
+		if journey.Status == StatusRunning {
+			p.fly(journey)
+		}
+	}
+}
+
+// Start sends a ship off along route, replacing whatever it was doing before
+func (p *Pilot) Start(shipSymbol string, route spacetrader.Route) error {
+	if len(route.Hops) == 0 {
+		return fmt.Errorf("%s is already there", shipSymbol)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if journey, ok := p.journeys[shipSymbol]; ok && journey.Status == StatusRunning {
+		return fmt.Errorf("%s is already on its way to %s, cancel that first", shipSymbol, journey.Destination())
+	}
+
+	now := p.Now()
+	journey := &Journey{
+		ShipSymbol: shipSymbol,
+		Route:      route,
+		Status:     StatusRunning,
+		Started:    now,
+		Updated:    now,
+	}
+	p.journeys[shipSymbol] = journey
+	if err := p.save(); err != nil {
+		return err
+	}
+
+	p.fly(journey)
+
+	return nil
+}
+
+// Cancel stops a journey where it is. A ship already in flight still gets to the end of its current hop.
+func (p *Pilot) Cancel(shipSymbol string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	journey, ok := p.journeys[shipSymbol]
+	if !ok || journey.Status != StatusRunning {
+		return fmt.Errorf("%s isn't on autopilot", shipSymbol)
+	}
+
+	if cancel, ok := p.cancels[shipSymbol]; ok {
+		cancel()
+		delete(p.cancels, shipSymbol)
+	}
+
+	journey.Status = StatusCancelled
+	journey.Updated = p.Now()
+
+	return p.save()
+}
+
+// Fly plans the ship's way to destination inside its system, sets off and waits for it to
+// get there. A journey already running to the same place is waited on rather than started over.
+func (p *Pilot) Fly(ctx context.Context, ship spacetrader.Ship, waypoints []spacetrader.Waypoint, fuelStations map[string]int, destination string) error {
+	journey, ok := p.Journey(ship.Symbol)
+	if !ok || journey.Status != StatusRunning || journey.Destination() != destination {
+		if ship.Nav.WaypointSymbol == destination && ship.Nav.Status != spacetrader.ShipNavStatusInTransit {
+			return nil
+		}
+
+		route, err := spacetrader.PlanRoute(ship, waypoints, fuelStations, destination)
+		if err != nil {
+			return err
+		}
+		// Don't set off anywhere if the caller gave up while the route was being planned
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := p.Start(ship.Symbol, route); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(p.Poll)
+	defer ticker.Stop()
+
+	for {
+		journey, _ := p.Journey(ship.Symbol)
+		switch journey.Status {
+		case StatusArrived:
+			return nil
+		case StatusFailed:
+			return fmt.Errorf("flying to %s: %s", destination, journey.Error)
+		case StatusCancelled:
+			return fmt.Errorf("the autopilot to %s was cancelled", destination)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Journey hands back a copy of the ship's latest journey, finished or not
+func (p *Pilot) Journey(shipSymbol string) (Journey, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	journey, ok := p.journeys[shipSymbol]
+	if !ok {
+		return Journey{}, false
+	}
+
+	return *journey, true
+}
+
+// Journeys lists every journey the pilot knows about, by ship
+func (p *Pilot) Journeys() []Journey {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	journeys := []Journey{}
+	for _, journey := range p.journeys {
+		journeys = append(journeys, *journey)
+	}
+	sort.Slice(journeys, func(i, j int) bool { return journeys[i].ShipSymbol < journeys[j].ShipSymbol })
+
+	return journeys
+}
+
+// Stop cancels every running journey's goroutine without marking it cancelled, so the next
+// Resume carries on from where they were
+func (p *Pilot) Stop() {
+	p.mu.Lock()
+	for shipSymbol, cancel := range p.cancels {
+		cancel()
+		delete(p.cancels, shipSymbol)
+	}
+	p.mu.Unlock()
+
+	p.running.Wait()
+}
+
+// fly starts the goroutine for a journey, p.mu must be held. The goroutine only ever touches
+// the journey it was started with, once the ship's been sent somewhere else it leaves the new
+// journey to its own goroutine.
+func (p *Pilot) fly(journey *Journey) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancels[journey.ShipSymbol] = cancel
+	p.running.Add(1)
+
+	go func() {
+		defer p.running.Done()
+
+		err := p.run(ctx, journey)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		// Cancelling has already recorded what happened
+		if ctx.Err() != nil || p.journeys[journey.ShipSymbol] != journey {
+			return
+		}
+		delete(p.cancels, journey.ShipSymbol)
+
+		journey.Updated = p.Now()
+		if err != nil {
+			journey.Status = StatusFailed
+			journey.Error = err.Error()
+		} else {
+			journey.Status = StatusArrived
+		}
+		p.save()
+	}()
+}
+
+// run flies the rest of a journey. Each step looks at where the ship really is first,
+// so picking a journey back up after a restart doesn't repeat anything already done.
+func (p *Pilot) run(ctx context.Context, journey *Journey) error {
+	shipSymbol := journey.ShipSymbol
+	for {
+		hop, ok, err := p.next(ctx, journey)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+
+		ship, err := p.api.GetShip(shipSymbol)
+		if err != nil {
+			return err
+		}
+
+		if ship.Nav.WaypointSymbol != hop.To {
+			if ship.Nav.Status == spacetrader.ShipNavStatusInTransit {
+				return fmt.Errorf("%s is heading to %s, not %s", shipSymbol, ship.Nav.WaypointSymbol, hop.To)
+			}
+
+			if err := p.depart(ctx, ship, hop); err != nil {
+				return fmt.Errorf("leaving %s: %w", hop.From, err)
+			}
+
+			if ship, err = p.api.GetShip(shipSymbol); err != nil {
+				return err
+			}
+		}
+
+		if err := p.wait(ctx, ship); err != nil {
+			return err
+		}
+
+		if err := p.advance(ctx, journey); err != nil {
+			return err
+		}
+	}
+
+	// Park at the destination so the ship's ready for whatever's next
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if _, err := p.api.DockShip(shipSymbol); err != nil {
+		return fmt.Errorf("docking at the destination: %w", err)
+	}
+
+	return nil
+}
+
+// depart gets the ship from sitting at hop.From to flying towards hop.To, or already through the
+// gate for a jump. A cancelled journey stops before the next call to the API rather than after
+// the ship's been sent on its way.
+func (p *Pilot) depart(ctx context.Context, ship spacetrader.Ship, hop spacetrader.RouteHop) error {
+	if hop.Refuel > 0 && ship.Fuel.Current < ship.Fuel.Capacity {
+		if ship.Nav.Status != spacetrader.ShipNavStatusDocked {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if _, err := p.api.DockShip(ship.Symbol); err != nil {
+				return err
+			}
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := p.api.RefuelShip(ship.Symbol, 0); err != nil {
+			return err
+		}
+		ship.Nav.Status = spacetrader.ShipNavStatusDocked
+	}
+
+	if ship.Nav.Status == spacetrader.ShipNavStatusDocked {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := p.api.LaunchToOrbit(ship.Symbol); err != nil {
+			return err
+		}
+	}
+
+	if hop.Action == spacetrader.HopJump {
+		if err := p.cooldown(ctx, ship); err != nil {
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		_, err := p.api.JumpShip(ship.Symbol, hop.To)
+
+		return err
+	}
+
+	if ship.Nav.FlightMode != hop.Mode {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := p.api.SetFlightMode(ship.Symbol, hop.Mode); err != nil {
+			return err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if hop.Action == spacetrader.HopWarp {
+		_, err := p.api.WarpShip(ship.Symbol, hop.To)
+
+		return err
+	}
+
+	_, err := p.api.NavigateShip(ship.Symbol, hop.To)
+
+	return err
+}
+
+// cooldown sleeps until the ship's reactor is ready to jump again
+func (p *Pilot) cooldown(ctx context.Context, ship spacetrader.Ship) error {
+	if ship.Cooldown.RemainingSeconds <= 0 {
+		return nil
+	}
+
+	expiration, err := time.Parse(time.RFC3339Nano, ship.Cooldown.Expiration)
+	if err != nil {
+		return fmt.Errorf("%s has a strange cooldown expiry %q: %w", ship.Symbol, ship.Cooldown.Expiration, err)
+	}
+
+	return p.sleep(ctx, expiration)
+}
+
+// wait sleeps until the ship lands, or the journey is cancelled
+func (p *Pilot) wait(ctx context.Context, ship spacetrader.Ship) error {
+	if ship.Nav.Status != spacetrader.ShipNavStatusInTransit {
+		return nil
+	}
+
+	arrival, err := time.Parse(time.RFC3339Nano, ship.Nav.Route.Arrival)
+	if err != nil {
+		return fmt.Errorf("%s has a strange arrival time %q: %w", ship.Symbol, ship.Nav.Route.Arrival, err)
+	}
+
+	return p.sleep(ctx, arrival)
+}
+
+// sleep waits until just after until, or the journey is cancelled
+func (p *Pilot) sleep(ctx context.Context, until time.Time) error {
+	timer := time.NewTimer(until.Sub(p.Now()) + p.Slack)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// next is the hop the journey is up to, it's false once every hop has been flown. It fails if
+// the journey has been cancelled or replaced meanwhile.
+func (p *Pilot) next(ctx context.Context, journey *Journey) (spacetrader.RouteHop, bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.current(ctx, journey); err != nil {
+		return spacetrader.RouteHop{}, false, err
+	}
+	if journey.Hop >= len(journey.Route.Hops) {
+		return spacetrader.RouteHop{}, false, nil
+	}
+
+	return journey.Route.Hops[journey.Hop], true, nil
+}
+
+// advance marks the current hop as flown and saves, unless the journey has been cancelled meanwhile
+func (p *Pilot) advance(ctx context.Context, journey *Journey) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.current(ctx, journey); err != nil {
+		return err
+	}
+
+	journey.Hop++
+	journey.Updated = p.Now()
+
+	return p.save()
+}
+
+// current fails if the journey's goroutine should give up, because it's been cancelled or the
+// ship has set off on another journey since. p.mu must be held.
+func (p *Pilot) current(ctx context.Context, journey *Journey) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if p.journeys[journey.ShipSymbol] != journey {
+		return fmt.Errorf("%s has been sent somewhere else", journey.ShipSymbol)
+	}
+
+	return nil
+}
+
+// save writes every journey out, p.mu must be held. It goes to a temporary file first
+// so a crash halfway through never leaves a broken save behind.
+func (p *Pilot) save() error {
+	encoded, err := json.MarshalIndent(p.journeys, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.path), 0o755); err != nil {
+		return err
+	}
+
+	temporary := p.path + ".tmp"
+	if err := os.WriteFile(temporary, encoded, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(temporary, p.path)
+}
@@ -0,0 +1,128 @@
+package autopilot_test
+
+import (
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"example.com/spacetrader"
+	"example.com/spacetrader/autopilot"
+)
+
+// tower answers for a single ship that gets wherever it's sent straight away. Looking the ship up
+// waits until hold is closed, so a test can line things up while a journey's goroutine is stuck
+// before its first step.
+type tower struct {
+	spacetrader.API
+
+	hold chan struct{}
+
+	mu   sync.Mutex
+	ship spacetrader.Ship
+	sent []string
+}
+
+func (t *tower) GetShip(shipSymbol string) (spacetrader.Ship, error) {
+	<-t.hold
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.ship, nil
+}
+
+func (t *tower) LaunchToOrbit(shipSymbol string) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.ship.Nav.Status = spacetrader.ShipNavStatusInOrbit
+	return true, nil
+}
+
+func (t *tower) DockShip(shipSymbol string) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.ship.Nav.Status = spacetrader.ShipNavStatusDocked
+	return true, nil
+}
+
+func (t *tower) SetFlightMode(shipSymbol string, mode spacetrader.FlightMode) (spacetrader.ShipNav, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.ship.Nav.FlightMode = mode
+	return t.ship.Nav, nil
+}
+
+func (t *tower) NavigateShip(shipSymbol string, waypointSymbol string) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.sent = append(t.sent, waypointSymbol)
+	t.ship.Nav.WaypointSymbol = waypointSymbol
+	return true, nil
+}
+
+func hopTo(to string) spacetrader.Route {
+	return spacetrader.Route{Hops: []spacetrader.RouteHop{{From: "X1-A-HOME", To: to, Mode: spacetrader.FlightModeCruise}}}
+}
+
+func TestCancelledJourneyLeavesTheShipAlone(t *testing.T) {
+	cases := []struct {
+		name string
+		// restart sends the ship off again once the first journey's been cancelled
+		restart  string
+		want     []string
+		finished autopilot.Status
+	}{
+		{"cancelled before setting off", "", nil, autopilot.StatusCancelled},
+		{"sent somewhere else instead", "X1-A-MOON", []string{"X1-A-MOON"}, autopilot.StatusArrived},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			api := &tower{hold: make(chan struct{})}
+			api.ship.Symbol = "SHIP-1"
+			api.ship.Nav = spacetrader.ShipNav{SystemSymbol: "X1-A", WaypointSymbol: "X1-A-HOME", Status: spacetrader.ShipNavStatusDocked}
+
+			pilot, err := autopilot.New(api, filepath.Join(t.TempDir(), "autopilot.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			pilot.Slack = 0
+
+			if err := pilot.Start("SHIP-1", hopTo("X1-A-ASTEROID")); err != nil {
+				t.Fatal(err)
+			}
+			if err := pilot.Cancel("SHIP-1"); err != nil {
+				t.Fatal(err)
+			}
+			if c.restart != "" {
+				if err := pilot.Start("SHIP-1", hopTo(c.restart)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			close(api.hold)
+
+			deadline := time.Now().Add(5 * time.Second)
+			for {
+				if journey, _ := pilot.Journey("SHIP-1"); journey.Status == c.finished {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("journey never got to %s", c.finished)
+				}
+				time.Sleep(time.Millisecond)
+			}
+			pilot.Stop()
+
+			api.mu.Lock()
+			defer api.mu.Unlock()
+			if !slices.Equal(api.sent, c.want) {
+				t.Errorf("ship was sent to %v, want %v", api.sent, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,434 @@
+package spacetrader
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Forever keeps an entry until the cache is Reset, which is what you want for anything
+// that only changes when the universe does
+const Forever time.Duration = -1
+
+// CachePolicy is how long each kind of resource is trusted before it's fetched again.
+// Zero means don't cache it at all.
+type CachePolicy struct {
+	Systems      time.Duration
+	Waypoints    time.Duration
+	Construction time.Duration
+	Markets      time.Duration
+	Ships        time.Duration
+	Agent        time.Duration
+	Contracts    time.Duration
+}
+
+// DefaultCachePolicy holds on to the universe for the whole reset, waypoints for an hour since
+// charting and construction change them, markets for a few minutes and anything that moves or
+// spends money for a few seconds
+var DefaultCachePolicy = CachePolicy{
+	Systems:      Forever,
+	Waypoints:    time.Hour,
+	Construction: 5 * time.Minute,
+	Markets:      5 * time.Minute,
+	Ships:        10 * time.Second,
+	Agent:        10 * time.Second,
+	Contracts:    30 * time.Second,
+}
+
+type cacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+// CachedAPI is a read-through cache in front of another API. Reads are answered from the cache
+// while they're fresh, and anything that changes a ship, the agent or a contract drops the
+// entries it would have made stale.
+type CachedAPI struct {
+	API
+	Policy CachePolicy
+	// Now is the clock used for expiry, swap it out to control time
+	Now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+var _ API = (*CachedAPI)(nil)
+
+func NewCachedAPI(api API, policy CachePolicy) *CachedAPI {
+	return &CachedAPI{
+		API:     api,
+		Policy:  policy,
+		Now:     time.Now,
+		entries: map[string]cacheEntry{},
+	}
+}
+
+// cached hands back the entry under key if it's still fresh, otherwise it fetches and stores it
+func cached[T any](c *CachedAPI, key string, ttl time.Duration, fetch func() (T, error)) (T, error) {
+	if ttl == 0 {
+		return fetch()
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if ok && (entry.expires.IsZero() || c.Now().Before(entry.expires)) {
+		return entry.value.(T), nil
+	}
+
+	value, err := fetch()
+
+	if err != nil {
+		return value, err
+	}
+
+	c.store(key, ttl, value)
+
+	return value, nil
+}
+
+func (c *CachedAPI) store(key string, ttl time.Duration, value any) {
+	if ttl == 0 {
+		return
+	}
+
+	entry := cacheEntry{value: value}
+	if ttl != Forever {
+		entry.expires = c.Now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = entry
+}
+
+// Invalidate drops every entry whose key starts with one of the prefixes
+func (c *CachedAPI) Invalidate(prefixes ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(key, prefix) {
+				delete(c.entries, key)
+				break
+			}
+		}
+	}
+}
+
+// Forget drops the entries with exactly these keys. Symbols can be prefixes of each other
+// (SHIP-1 and SHIP-10), so anything keyed on one should be forgotten rather than invalidated.
+func (c *CachedAPI) Forget(keys ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+}
+
+// InvalidateShip forgets a ship, and the fleet list it shows up in
+func (c *CachedAPI) InvalidateShip(shipSymbols ...string) {
+	keys := []string{"ships"}
+	for _, shipSymbol := range shipSymbols {
+		keys = append(keys, "ship:"+shipSymbol)
+	}
+
+	c.Forget(keys...)
+}
+
+// Reset empties the whole cache, the universe gets rebuilt every server reset
+func (c *CachedAPI) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = map[string]cacheEntry{}
+}
+
+func (c *CachedAPI) ShowAgent() (Agent, error) {
+	return cached(c, "agent", c.Policy.Agent, c.API.ShowAgent)
+}
+
+// Ships are handed out as copies, a caller changing its cargo or mounts mustn't change ours
+func (c *CachedAPI) GetShips() ([]Ship, error) {
+	ships, err := cached(c, "ships", c.Policy.Ships, c.API.GetShips)
+
+	if err != nil {
+		return ships, err
+	}
+
+	// The fleet list has every ship in it so each one can be answered from it too
+	copies := make([]Ship, len(ships))
+	for i, ship := range ships {
+		c.store("ship:"+ship.Symbol, c.Policy.Ships, ship)
+		copies[i] = copyShip(ship)
+	}
+
+	return copies, nil
+}
+
+func (c *CachedAPI) GetShip(shipSymbol string) (Ship, error) {
+	ship, err := cached(c, "ship:"+shipSymbol, c.Policy.Ships, func() (Ship, error) {
+		return c.API.GetShip(shipSymbol)
+	})
+
+	return copyShip(ship), err
+}
+
+// copyShip copies everything a ship holds in a slice, the rest of it is copied by value anyway
+func copyShip(ship Ship) Ship {
+	ship.Cargo.Inventory = slices.Clone(ship.Cargo.Inventory)
+	ship.Modules = slices.Clone(ship.Modules)
+	ship.Mounts = slices.Clone(ship.Mounts)
+	for i := range ship.Mounts {
+		ship.Mounts[i].Deposits = slices.Clone(ship.Mounts[i].Deposits)
+	}
+
+	return ship
+}
+
+func (c *CachedAPI) ListSystems(page int, limit int) ([]System, Meta, error) {
+	type systemsPage struct {
+		systems []System
+		meta    Meta
+	}
+
+	listed, err := cached(c, fmt.Sprintf("systems:%d:%d", page, limit), c.Policy.Systems, func() (systemsPage, error) {
+		systems, meta, err := c.API.ListSystems(page, limit)
+		return systemsPage{systems, meta}, err
+	})
+
+	return listed.systems, listed.meta, err
+}
+
+func (c *CachedAPI) GetSystem(systemSymbol string) (System, error) {
+	return cached(c, "system:"+systemSymbol, c.Policy.Systems, func() (System, error) {
+		return c.API.GetSystem(systemSymbol)
+	})
+}
+
+func (c *CachedAPI) GetWaypoints(systemSymbol string, query WaypointQuery) ([]Waypoint, Meta, error) {
+	type waypointsPage struct {
+		waypoints []Waypoint
+		meta      Meta
+	}
+
+	values, err := query.Values()
+
+	if err != nil {
+		return []Waypoint{}, Meta{}, err
+	}
+
+	listed, err := cached(c, "waypoints:"+systemSymbol+"?"+values.Encode(), c.Policy.Waypoints, func() (waypointsPage, error) {
+		waypoints, meta, err := c.API.GetWaypoints(systemSymbol, query)
+		return waypointsPage{waypoints, meta}, err
+	})
+
+	return listed.waypoints, listed.meta, err
+}
+
+func (c *CachedAPI) GetWaypoint(systemSymbol string, waypointSymbol string) (Waypoint, error) {
+	return cached(c, "waypoint:"+waypointSymbol, c.Policy.Waypoints, func() (Waypoint, error) {
+		return c.API.GetWaypoint(systemSymbol, waypointSymbol)
+	})
+}
+
+func (c *CachedAPI) GetConstructionSite(systemSymbol string, waypointSymbol string) (Construction, error) {
+	return cached(c, "construction:"+waypointSymbol, c.Policy.Construction, func() (Construction, error) {
+		return c.API.GetConstructionSite(systemSymbol, waypointSymbol)
+	})
+}
+
+// Gate connections are part of the universe, they only change with a reset
+func (c *CachedAPI) GetJumpGate(systemSymbol string, waypointSymbol string) (JumpGate, error) {
+	return cached(c, "jumpgate:"+waypointSymbol, c.Policy.Systems, func() (JumpGate, error) {
+		return c.API.GetJumpGate(systemSymbol, waypointSymbol)
+	})
+}
+
+func (c *CachedAPI) GetMarket(systemSymbol string, waypointSymbol string) (Market, error) {
+	return cached(c, "market:"+waypointSymbol, c.Policy.Markets, func() (Market, error) {
+		return c.API.GetMarket(systemSymbol, waypointSymbol)
+	})
+}
+
+func (c *CachedAPI) GetContracts() ([]Contract, error) {
+	return cached(c, "contracts", c.Policy.Contracts, c.API.GetContracts)
+}
+
+// Everything below changes something we might have cached, so the stale bits get dropped
+// whether or not the call worked. A failed call is cheap to recover from, a stale page isn't.
+
+func (c *CachedAPI) InstallMount(shipSymbol string, mountSymbol TradeSymbol) (MountChange, error) {
+	defer c.Invalidate("agent")
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.InstallMount(shipSymbol, mountSymbol)
+}
+
+func (c *CachedAPI) RemoveMount(shipSymbol string, mountSymbol TradeSymbol) (MountChange, error) {
+	defer c.Invalidate("agent")
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.RemoveMount(shipSymbol, mountSymbol)
+}
+
+func (c *CachedAPI) InstallModule(shipSymbol string, moduleSymbol TradeSymbol) (ModuleChange, error) {
+	defer c.Invalidate("agent")
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.InstallModule(shipSymbol, moduleSymbol)
+}
+
+func (c *CachedAPI) RemoveModule(shipSymbol string, moduleSymbol TradeSymbol) (ModuleChange, error) {
+	defer c.Invalidate("agent")
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.RemoveModule(shipSymbol, moduleSymbol)
+}
+
+func (c *CachedAPI) RepairShip(shipSymbol string) (ShipRepair, error) {
+	defer c.Invalidate("agent")
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.RepairShip(shipSymbol)
+}
+
+func (c *CachedAPI) ScrapShip(shipSymbol string) (ShipScrap, error) {
+	defer c.Invalidate("agent")
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.ScrapShip(shipSymbol)
+}
+
+func (c *CachedAPI) JettisonCargo(shipSymbol string, tradeSymbol TradeSymbol, units int) (ShipCargo, error) {
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.JettisonCargo(shipSymbol, tradeSymbol, units)
+}
+
+func (c *CachedAPI) TransferCargo(shipSymbol string, tradeSymbol TradeSymbol, units int, toShipSymbol string) (ShipCargo, error) {
+	defer c.InvalidateShip(shipSymbol, toShipSymbol)
+
+	return c.API.TransferCargo(shipSymbol, tradeSymbol, units, toShipSymbol)
+}
+
+func (c *CachedAPI) RefineCargo(shipSymbol string, produce TradeSymbol) (Refinement, error) {
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.RefineCargo(shipSymbol, produce)
+}
+
+func (c *CachedAPI) SiphonResources(shipSymbol string) (Siphon, error) {
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.SiphonResources(shipSymbol)
+}
+
+func (c *CachedAPI) CreateSurvey(shipSymbol string) (ShipSurvey, error) {
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.CreateSurvey(shipSymbol)
+}
+
+func (c *CachedAPI) ExtractResources(shipSymbol string, survey *Survey) (Extract, error) {
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.ExtractResources(shipSymbol, survey)
+}
+
+// Trading moves the market's prices as well as the ship's hold and our credits, and the
+// cache has no idea which market the ship is at, so every market goes
+func (c *CachedAPI) SellCargo(shipSymbol string, tradeSymbol TradeSymbol, units int) (CargoTrade, error) {
+	defer c.Invalidate("agent", "market:")
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.SellCargo(shipSymbol, tradeSymbol, units)
+}
+
+func (c *CachedAPI) PurchaseCargo(shipSymbol string, tradeSymbol TradeSymbol, units int) (CargoTrade, error) {
+	defer c.Invalidate("agent", "market:")
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.PurchaseCargo(shipSymbol, tradeSymbol, units)
+}
+
+func (c *CachedAPI) LaunchToOrbit(shipSymbol string) (bool, error) {
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.LaunchToOrbit(shipSymbol)
+}
+
+// Docking puts a ship where it can see a market's prices, so a copy fetched from afar without them has to go
+func (c *CachedAPI) DockShip(shipSymbol string) (bool, error) {
+	defer c.Invalidate("market:")
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.DockShip(shipSymbol)
+}
+
+func (c *CachedAPI) NavigateShip(shipSymbol string, waypointSymbol string) (bool, error) {
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.NavigateShip(shipSymbol, waypointSymbol)
+}
+
+func (c *CachedAPI) SetFlightMode(shipSymbol string, mode FlightMode) (ShipNav, error) {
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.SetFlightMode(shipSymbol, mode)
+}
+
+func (c *CachedAPI) RefuelShip(shipSymbol string, units int) (ShipRefuel, error) {
+	defer c.Invalidate("agent")
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.RefuelShip(shipSymbol, units)
+}
+
+func (c *CachedAPI) JumpShip(shipSymbol string, waypointSymbol string) (ShipJump, error) {
+	defer c.Invalidate("agent")
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.JumpShip(shipSymbol, waypointSymbol)
+}
+
+func (c *CachedAPI) WarpShip(shipSymbol string, waypointSymbol string) (ShipTransit, error) {
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.WarpShip(shipSymbol, waypointSymbol)
+}
+
+// Supplying the last of the materials finishes the site, so the waypoint stops being under construction
+func (c *CachedAPI) SupplyConstruction(systemSymbol string, waypointSymbol string, shipSymbol string, tradeSymbol TradeSymbol, units int) (ConstructionSupply, error) {
+	defer c.Invalidate("waypoints:" + systemSymbol + "?")
+	defer c.Forget("construction:"+waypointSymbol, "waypoint:"+waypointSymbol, "system:"+systemSymbol)
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.SupplyConstruction(systemSymbol, waypointSymbol, shipSymbol, tradeSymbol, units)
+}
+
+func (c *CachedAPI) AcceptContract(contractId string) (ContractUpdate, error) {
+	defer c.Invalidate("contracts", "agent")
+
+	return c.API.AcceptContract(contractId)
+}
+
+func (c *CachedAPI) DeliverContract(contractId string, shipSymbol string, tradeSymbol TradeSymbol, units int) (ContractDeliveryUpdate, error) {
+	defer c.Invalidate("contracts")
+	defer c.InvalidateShip(shipSymbol)
+
+	return c.API.DeliverContract(contractId, shipSymbol, tradeSymbol, units)
+}
+
+func (c *CachedAPI) FulfillContract(contractId string) (ContractUpdate, error) {
+	defer c.Invalidate("contracts", "agent")
+
+	return c.API.FulfillContract(contractId)
+}
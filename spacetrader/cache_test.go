@@ -0,0 +1,258 @@
+package spacetrader_test
+
+import (
+	"testing"
+	"time"
+
+	"example.com/spacetrader"
+)
+
+// counter stands in for the API behind the cache, counting what actually gets asked of it. Anything
+// it doesn't answer itself panics on the nil API.
+type counter struct {
+	spacetrader.API
+	calls map[string]int
+}
+
+func (a *counter) ShowAgent() (spacetrader.Agent, error) {
+	a.calls["agent"]++
+	return spacetrader.Agent{Symbol: "AGENT", Credits: 1000}, nil
+}
+
+func (a *counter) GetShips() ([]spacetrader.Ship, error) {
+	a.calls["ships"]++
+	return []spacetrader.Ship{miningShip("SHIP-1"), miningShip("SHIP-10")}, nil
+}
+
+func (a *counter) GetShip(shipSymbol string) (spacetrader.Ship, error) {
+	a.calls["ship:"+shipSymbol]++
+	return miningShip(shipSymbol), nil
+}
+
+func (a *counter) GetSystem(systemSymbol string) (spacetrader.System, error) {
+	a.calls["system:"+systemSymbol]++
+	return spacetrader.System{Symbol: systemSymbol}, nil
+}
+
+func (a *counter) GetWaypoints(systemSymbol string, query spacetrader.WaypointQuery) ([]spacetrader.Waypoint, spacetrader.Meta, error) {
+	a.calls["waypoints:"+systemSymbol]++
+	return []spacetrader.Waypoint{}, spacetrader.Meta{}, nil
+}
+
+func (a *counter) GetWaypoint(systemSymbol string, waypointSymbol string) (spacetrader.Waypoint, error) {
+	a.calls["waypoint:"+waypointSymbol]++
+	return spacetrader.Waypoint{Symbol: waypointSymbol, SystemSymbol: systemSymbol}, nil
+}
+
+func (a *counter) GetConstructionSite(systemSymbol string, waypointSymbol string) (spacetrader.Construction, error) {
+	a.calls["construction:"+waypointSymbol]++
+	return spacetrader.Construction{Symbol: waypointSymbol}, nil
+}
+
+func (a *counter) GetMarket(systemSymbol string, waypointSymbol string) (spacetrader.Market, error) {
+	a.calls["market:"+waypointSymbol]++
+	return spacetrader.Market{Symbol: waypointSymbol}, nil
+}
+
+func (a *counter) SupplyConstruction(systemSymbol string, waypointSymbol string, shipSymbol string, tradeSymbol spacetrader.TradeSymbol, units int) (spacetrader.ConstructionSupply, error) {
+	a.calls["supply"]++
+	return spacetrader.ConstructionSupply{}, nil
+}
+
+// miningShip has a bit of everything a ship keeps in slices
+func miningShip(shipSymbol string) spacetrader.Ship {
+	return spacetrader.Ship{
+		Symbol: shipSymbol,
+		Cargo: spacetrader.ShipCargo{Capacity: 30, Units: 12, Inventory: []spacetrader.Cargo{
+			{Symbol: spacetrader.TradeIronOre, Units: 12},
+		}},
+		Modules: []spacetrader.ShipModule{{Symbol: spacetrader.TradeModuleCargoHoldI}},
+		Mounts: []spacetrader.ShipMount{
+			{Symbol: spacetrader.TradeMountSurveyorI, Deposits: []spacetrader.TradeSymbol{spacetrader.TradeIronOre, spacetrader.TradeCopperOre}},
+		},
+	}
+}
+
+// caching puts a cache with policy in front of a counter, on a clock that only moves when the test moves it
+func caching(policy spacetrader.CachePolicy) (*spacetrader.CachedAPI, *counter, *time.Time) {
+	api := &counter{calls: map[string]int{}}
+	cache := spacetrader.NewCachedAPI(api, policy)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache.Now = func() time.Time { return now }
+
+	return cache, api, &now
+}
+
+func TestCacheTTL(t *testing.T) {
+	policy := spacetrader.CachePolicy{
+		Systems: spacetrader.Forever,
+		Markets: 0,
+		Ships:   10 * time.Second,
+		Agent:   10 * time.Second,
+	}
+
+	cases := []struct {
+		name  string
+		read  func(*spacetrader.CachedAPI) error
+		call  string
+		after time.Duration
+		want  int
+	}{
+		{"agent within its time", func(c *spacetrader.CachedAPI) error { _, err := c.ShowAgent(); return err }, "agent", 9 * time.Second, 1},
+		{"agent once it's run out", func(c *spacetrader.CachedAPI) error { _, err := c.ShowAgent(); return err }, "agent", 10 * time.Second, 2},
+		{"systems are kept for good", func(c *spacetrader.CachedAPI) error { _, err := c.GetSystem("X1-A"); return err }, "system:X1-A", 1000 * time.Hour, 1},
+		{"markets aren't kept at all", func(c *spacetrader.CachedAPI) error { _, err := c.GetMarket("X1-A", "X1-A-M1"); return err }, "market:X1-A-M1", 0, 2},
+		{"ships within their time", func(c *spacetrader.CachedAPI) error { _, err := c.GetShip("SHIP-1"); return err }, "ship:SHIP-1", 5 * time.Second, 1},
+		{"ships once they've run out", func(c *spacetrader.CachedAPI) error { _, err := c.GetShip("SHIP-1"); return err }, "ship:SHIP-1", 11 * time.Second, 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cache, api, now := caching(policy)
+
+			if err := c.read(cache); err != nil {
+				t.Fatal(err)
+			}
+			*now = now.Add(c.after)
+			if err := c.read(cache); err != nil {
+				t.Fatal(err)
+			}
+
+			if api.calls[c.call] != c.want {
+				t.Errorf("reading twice %s apart asked the API %d times, want %d", c.after, api.calls[c.call], c.want)
+			}
+		})
+	}
+}
+
+func TestCacheAnswersShipsFromTheFleet(t *testing.T) {
+	cache, api, _ := caching(spacetrader.DefaultCachePolicy)
+
+	if _, err := cache.GetShips(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.GetShip("SHIP-10"); err != nil {
+		t.Fatal(err)
+	}
+
+	if api.calls["ships"] != 1 || api.calls["ship:SHIP-10"] != 0 {
+		t.Errorf("got calls %v, want the fleet list to answer for SHIP-10", api.calls)
+	}
+}
+
+func TestCacheHandsOutCopies(t *testing.T) {
+	cache, _, _ := caching(spacetrader.DefaultCachePolicy)
+
+	ships, err := cache.GetShips()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ships[0].Cargo.Inventory[0].Units = 0
+	ships[0].Mounts[0].Deposits[0] = spacetrader.TradeGold
+
+	ship, err := cache.GetShip("SHIP-10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ship.Modules[0].Symbol = spacetrader.TradeModuleMineralProcessorI
+	ship.Cargo.Inventory = append(ship.Cargo.Inventory[:0], spacetrader.Cargo{Symbol: spacetrader.TradeGold, Units: 1})
+
+	for _, shipSymbol := range []string{"SHIP-1", "SHIP-10"} {
+		cached, err := cache.GetShip(shipSymbol)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := miningShip(shipSymbol); cached.Cargo.Inventory[0] != want.Cargo.Inventory[0] || cached.Mounts[0].Deposits[0] != want.Mounts[0].Deposits[0] || cached.Modules[0] != want.Modules[0] {
+			t.Errorf("cached %s was changed through a copy handed out: %+v", shipSymbol, cached)
+		}
+	}
+}
+
+func TestCacheInvalidateShip(t *testing.T) {
+	cases := []struct {
+		name       string
+		invalidate func(*spacetrader.CachedAPI)
+		// refetched says which of the reads below have to go back to the API
+		refetched map[string]bool
+	}{
+		{"invalidating a ship leaves one it prefixes alone", func(c *spacetrader.CachedAPI) { c.InvalidateShip("SHIP-1") }, map[string]bool{"ship:SHIP-1": true, "ship:SHIP-10": false, "ships": true}},
+		{"invalidating several ships", func(c *spacetrader.CachedAPI) { c.InvalidateShip("SHIP-1", "SHIP-10") }, map[string]bool{"ship:SHIP-1": true, "ship:SHIP-10": true, "ships": true}},
+		{"forgetting a key is exact", func(c *spacetrader.CachedAPI) { c.Forget("ship:SHIP-1") }, map[string]bool{"ship:SHIP-1": true, "ship:SHIP-10": false, "ships": false}},
+		{"invalidating a prefix takes everything under it", func(c *spacetrader.CachedAPI) { c.Invalidate("ship:SHIP-1") }, map[string]bool{"ship:SHIP-1": true, "ship:SHIP-10": true, "ships": false}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cache, api, _ := caching(spacetrader.DefaultCachePolicy)
+
+			read := func() {
+				for _, shipSymbol := range []string{"SHIP-1", "SHIP-10"} {
+					if _, err := cache.GetShip(shipSymbol); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if _, err := cache.GetShips(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			read()
+			before := map[string]int{}
+			for call, count := range api.calls {
+				before[call] = count
+			}
+
+			c.invalidate(cache)
+			read()
+
+			for call, refetched := range c.refetched {
+				if got := api.calls[call] > before[call]; got != refetched {
+					t.Errorf("%s went back to the API: %t, want %t", call, got, refetched)
+				}
+			}
+		})
+	}
+}
+
+func TestCacheSupplyConstruction(t *testing.T) {
+	cache, api, _ := caching(spacetrader.DefaultCachePolicy)
+
+	reads := []struct {
+		call      string
+		read      func() error
+		refetched bool
+	}{
+		{"construction:X1-A-I1", func() error { _, err := cache.GetConstructionSite("X1-A", "X1-A-I1"); return err }, true},
+		{"waypoint:X1-A-I1", func() error { _, err := cache.GetWaypoint("X1-A", "X1-A-I1"); return err }, true},
+		{"system:X1-A", func() error { _, err := cache.GetSystem("X1-A"); return err }, true},
+		{"waypoints:X1-A", func() error {
+			_, _, err := cache.GetWaypoints("X1-A", spacetrader.WaypointQuery{Type: spacetrader.WaypointTypeJumpGate})
+			return err
+		}, true},
+		{"ship:SHIP-1", func() error { _, err := cache.GetShip("SHIP-1"); return err }, true},
+		// Nothing else the site's symbols happen to prefix is touched
+		{"construction:X1-A-I10", func() error { _, err := cache.GetConstructionSite("X1-A", "X1-A-I10"); return err }, false},
+		{"waypoint:X1-A-I10", func() error { _, err := cache.GetWaypoint("X1-A", "X1-A-I10"); return err }, false},
+		{"system:X1-AB", func() error { _, err := cache.GetSystem("X1-AB"); return err }, false},
+		{"waypoints:X1-AB", func() error { _, _, err := cache.GetWaypoints("X1-AB", spacetrader.WaypointQuery{}); return err }, false},
+		{"ship:SHIP-10", func() error { _, err := cache.GetShip("SHIP-10"); return err }, false},
+	}
+
+	for _, r := range reads {
+		if err := r.read(); err != nil {
+			t.Fatalf("%s: %v", r.call, err)
+		}
+	}
+
+	if _, err := cache.SupplyConstruction("X1-A", "X1-A-I1", "SHIP-1", spacetrader.TradeFabMats, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, r := range reads {
+		before := api.calls[r.call]
+		if err := r.read(); err != nil {
+			t.Fatalf("%s: %v", r.call, err)
+		}
+		if got := api.calls[r.call] > before; got != r.refetched {
+			t.Errorf("%s went back to the API after supplying: %t, want %t", r.call, got, r.refetched)
+		}
+	}
+}
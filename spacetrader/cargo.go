@@ -0,0 +1,56 @@
+package spacetrader
+
+import (
+	"fmt"
+
+	"example.com/spacetrader/model"
+)
+
+// JettisonCargo dumps units of a good out into space, they're gone for good
+func JettisonCargo(token string, shipSymbol string, tradeSymbol TradeSymbol, units int) (ShipCargo, error) {
+	if !tradeSymbol.Valid() {
+		return ShipCargo{}, fmt.Errorf("unknown trade symbol %q", tradeSymbol)
+	}
+
+	if units < 1 {
+		return ShipCargo{}, fmt.Errorf("must jettison at least 1 unit, got %d", units)
+	}
+
+	// Jettison and transfer both only hand back what's left in the hold
+	jettison := model.JettisonRequest{Symbol: tradeSymbol, Units: units}
+	update, err := model.Jettison(NewClient(token), shipSymbol, jettison)
+
+	if err != nil {
+		return ShipCargo{}, err
+	}
+
+	return update.Cargo, nil
+}
+
+// TransferCargo moves units of a good into another of our ships, both have to be at the same waypoint
+func TransferCargo(token string, shipSymbol string, tradeSymbol TradeSymbol, units int, toShipSymbol string) (ShipCargo, error) {
+	if !tradeSymbol.Valid() {
+		return ShipCargo{}, fmt.Errorf("unknown trade symbol %q", tradeSymbol)
+	}
+
+	if units < 1 {
+		return ShipCargo{}, fmt.Errorf("must transfer at least 1 unit, got %d", units)
+	}
+
+	if toShipSymbol == shipSymbol {
+		return ShipCargo{}, fmt.Errorf("%s can't transfer cargo to itself", shipSymbol)
+	}
+
+	transfer := model.TransferCargoRequest{
+		TradeSymbol: tradeSymbol,
+		Units:       units,
+		ShipSymbol:  toShipSymbol,
+	}
+	update, err := model.TransferCargo(NewClient(token), shipSymbol, transfer)
+
+	if err != nil {
+		return ShipCargo{}, err
+	}
+
+	return update.Cargo, nil
+}
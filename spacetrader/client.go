@@ -0,0 +1,181 @@
+package spacetrader
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"example.com/spacetrader/model"
+)
+
+// BaseURL is where every request gets sent, swap it out to point at a different server
+var BaseURL = "https://api.spacetraders.io/v2"
+
+// HTTPClient is the client every request goes through
+var HTTPClient = &http.Client{
+	CheckRedirect: nil,
+}
+
+// Logger gets a line for every request made to the API
+var Logger = log.Default()
+
+// The API allows 2 requests a second with a burst of 30 every minute
+var limiter = newRateLimiter(2, 30)
+
+// How many times a rate limited (429) request gets retried before giving up
+const maxRetries = 3
+
+// Meta is the pagination info that comes back with list endpoints
+type Meta = model.Meta
+
+// APIError is what the API sends back when it refuses a request
+type APIError struct {
+	StatusCode int             `json:"-"`
+	Code       int             `json:"code"`
+	Message    string          `json:"message"`
+	Data       json.RawMessage `json:"data"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("spacetrader: %s (code %d, status %d)", e.Message, e.Code, e.StatusCode)
+}
+
+// request sends a request to the API, retrying while it's rate limited, and decodes a
+// successful response body into out as it comes. Every response is wrapped up as
+// {"data": ...}, with meta on lists, and a refusal comes back as an *APIError.
+func request(token string, method string, path string, payload any, out any) error {
+	var encoded []byte
+	if payload != nil {
+		var err error
+		encoded, err = json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		limiter.wait()
+
+		body, status, retryAfter, err := send(token, method, path, encoded)
+		if err != nil {
+			return err
+		}
+
+		// Back off and try again when we've been going too fast
+		if status == http.StatusTooManyRequests && attempt < maxRetries {
+			Logger.Printf("%s %s rate limited, retrying in %s", method, path, retryAfter)
+			time.Sleep(retryAfter)
+			continue
+		}
+
+		if status >= http.StatusBadRequest {
+			var failure struct {
+				Error *APIError `json:"error"`
+			}
+			if json.Unmarshal(body, &failure) == nil && failure.Error != nil {
+				failure.Error.StatusCode = status
+				return failure.Error
+			}
+
+			return &APIError{StatusCode: status, Message: http.StatusText(status)}
+		}
+
+		if len(body) > 0 {
+			if err := json.Unmarshal(body, out); err != nil {
+				return fmt.Errorf("spacetrader: could not decode %s %s: %w", method, path, err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// send makes a single round trip to the API and hands back the raw body
+func send(token string, method string, path string, payload []byte) ([]byte, int, time.Duration, error) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, BaseURL+path, reader)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	req.Header.Add("Authorization", fmt.Sprint("Bearer ", token))
+	req.Header.Add("Accept", "application/json")
+	if payload != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+
+	start := time.Now()
+	resp, err := HTTPClient.Do(req)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// Closing the connection seems important and stuff
+	defer resp.Body.Close()
+	// Grab the deets
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	Logger.Printf("%s %s %d (%s)", method, path, resp.StatusCode, time.Since(start).Round(time.Millisecond))
+
+	return body, resp.StatusCode, retryDelay(resp.Header), nil
+}
+
+// retryDelay reads how long the API wants us to wait, falling back to a second
+func retryDelay(header http.Header) time.Duration {
+	seconds, err := strconv.ParseFloat(header.Get("Retry-After"), 64)
+	if err != nil || seconds <= 0 {
+		return time.Second
+	}
+
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// rateLimiter is a token bucket shared by every request in the package
+type rateLimiter struct {
+	mu       sync.Mutex
+	tokens   float64
+	burst    float64
+	perSec   float64
+	lastFill time.Time
+}
+
+func newRateLimiter(perSec float64, burst float64) *rateLimiter {
+	return &rateLimiter{
+		tokens:   burst,
+		burst:    burst,
+		perSec:   perSec,
+		lastFill: time.Now(),
+	}
+}
+
+// wait blocks until there is a token available and then takes it
+func (l *rateLimiter) wait() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.lastFill).Seconds()*l.perSec)
+	l.lastFill = now
+
+	if l.tokens < 1 {
+		delay := time.Duration((1 - l.tokens) / l.perSec * float64(time.Second))
+		time.Sleep(delay)
+		l.tokens = 1
+		l.lastFill = time.Now()
+	}
+
+	l.tokens--
+}
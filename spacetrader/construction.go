@@ -0,0 +1,51 @@
+package spacetrader
+
+import (
+	"fmt"
+
+	"example.com/spacetrader/model"
+)
+
+type (
+	ConstructionMaterial = model.ConstructionMaterial
+
+	// Construction is a waypoint that's still being built, along with what it still needs
+	Construction = model.Construction
+
+	// ConstructionSupply is what comes back after dropping materials off at a construction site
+	ConstructionSupply = model.ConstructionSupply
+)
+
+func GetConstructionSite(token string, systemSymbol string, waypointSymbol string) (Construction, error) {
+	construction, err := model.GetConstruction(NewClient(token), systemSymbol, waypointSymbol)
+
+	if err != nil {
+		return Construction{}, err
+	}
+
+	return construction, nil
+}
+
+// SupplyConstruction hands over materials from a ship docked at the construction site
+func SupplyConstruction(token string, systemSymbol string, waypointSymbol string, shipSymbol string, tradeSymbol TradeSymbol, units int) (ConstructionSupply, error) {
+	if !tradeSymbol.Valid() {
+		return ConstructionSupply{}, fmt.Errorf("unknown trade symbol %q", tradeSymbol)
+	}
+
+	if units < 1 {
+		return ConstructionSupply{}, fmt.Errorf("must supply at least 1 unit, got %d", units)
+	}
+
+	supply := model.SupplyConstructionRequest{
+		ShipSymbol:  shipSymbol,
+		TradeSymbol: tradeSymbol,
+		Units:       units,
+	}
+	supplied, err := model.SupplyConstruction(NewClient(token), systemSymbol, waypointSymbol, supply)
+
+	if err != nil {
+		return ConstructionSupply{}, err
+	}
+
+	return supplied, nil
+}
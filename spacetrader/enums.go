@@ -0,0 +1,362 @@
+package spacetrader
+
+import (
+	"fmt"
+	"slices"
+
+	"example.com/spacetrader/model"
+)
+
+// The enums are the generated ones, with the names, lists and parsers the client has always
+// had kept on here
+
+// parseEnum checks value against the known members of an enum and hands back
+// the typed value, so bad input gets caught before it ever hits the API
+func parseEnum[T ~string](kind string, value string, known []T) (T, error) {
+	if !slices.Contains(known, T(value)) {
+		return "", fmt.Errorf("unknown %s %q", kind, value)
+	}
+
+	return T(value), nil
+}
+
+// ShipNavStatus is where a ship is at in its flight cycle
+type ShipNavStatus = model.ShipNavStatus
+
+const (
+	ShipNavStatusInTransit = model.ShipNavStatusInTransit
+	ShipNavStatusInOrbit   = model.ShipNavStatusInOrbit
+	ShipNavStatusDocked    = model.ShipNavStatusDocked
+)
+
+// ShipNavStatuses lists every ShipNavStatus the API knows about
+var ShipNavStatuses = model.ShipNavStatusValues
+
+func ParseShipNavStatus(value string) (ShipNavStatus, error) {
+	return parseEnum("ship nav status", value, ShipNavStatuses)
+}
+
+// FlightMode trades fuel for speed when a ship travels
+type FlightMode = model.ShipNavFlightMode
+
+const (
+	FlightModeDrift   = model.ShipNavFlightModeDrift
+	FlightModeStealth = model.ShipNavFlightModeStealth
+	FlightModeCruise  = model.ShipNavFlightModeCruise
+	FlightModeBurn    = model.ShipNavFlightModeBurn
+)
+
+// FlightModes lists every FlightMode the API knows about
+var FlightModes = model.ShipNavFlightModeValues
+
+func ParseFlightMode(value string) (FlightMode, error) {
+	return parseEnum("flight mode", value, FlightModes)
+}
+
+// ShipRole is the job a ship was registered for
+type ShipRole = model.ShipRole
+
+const (
+	ShipRoleFabricator  = model.ShipRoleFabricator
+	ShipRoleHarvester   = model.ShipRoleHarvester
+	ShipRoleHauler      = model.ShipRoleHauler
+	ShipRoleInterceptor = model.ShipRoleInterceptor
+	ShipRoleExcavator   = model.ShipRoleExcavator
+	ShipRoleTransport   = model.ShipRoleTransport
+	ShipRoleRepair      = model.ShipRoleRepair
+	ShipRoleSurveyor    = model.ShipRoleSurveyor
+	ShipRoleCommand     = model.ShipRoleCommand
+	ShipRoleCarrier     = model.ShipRoleCarrier
+	ShipRolePatrol      = model.ShipRolePatrol
+	ShipRoleSatellite   = model.ShipRoleSatellite
+	ShipRoleExplorer    = model.ShipRoleExplorer
+	ShipRoleRefinery    = model.ShipRoleRefinery
+)
+
+// ShipRoles lists every ShipRole the API knows about
+var ShipRoles = model.ShipRoleValues
+
+func ParseShipRole(value string) (ShipRole, error) {
+	return parseEnum("ship role", value, ShipRoles)
+}
+
+// WaypointType is the kind of thing sitting at a waypoint
+type WaypointType = model.WaypointType
+
+const (
+	WaypointTypePlanet                = model.WaypointTypePlanet
+	WaypointTypeGasGiant              = model.WaypointTypeGasGiant
+	WaypointTypeMoon                  = model.WaypointTypeMoon
+	WaypointTypeOrbitalStation        = model.WaypointTypeOrbitalStation
+	WaypointTypeJumpGate              = model.WaypointTypeJumpGate
+	WaypointTypeAsteroidField         = model.WaypointTypeAsteroidField
+	WaypointTypeAsteroid              = model.WaypointTypeAsteroid
+	WaypointTypeEngineeredAsteroid    = model.WaypointTypeEngineeredAsteroid
+	WaypointTypeAsteroidBase          = model.WaypointTypeAsteroidBase
+	WaypointTypeNebula                = model.WaypointTypeNebula
+	WaypointTypeDebrisField           = model.WaypointTypeDebrisField
+	WaypointTypeGravityWell           = model.WaypointTypeGravityWell
+	WaypointTypeArtificialGravityWell = model.WaypointTypeArtificialGravityWell
+	WaypointTypeFuelStation           = model.WaypointTypeFuelStation
+)
+
+// WaypointTypes lists every WaypointType the API knows about
+var WaypointTypes = model.WaypointTypeValues
+
+func ParseWaypointType(value string) (WaypointType, error) {
+	return parseEnum("waypoint type", value, WaypointTypes)
+}
+
+// WaypointTraitSymbol identifies a trait a waypoint can have
+type WaypointTraitSymbol = model.WaypointTraitSymbol
+
+const (
+	WaypointTraitUncharted             = model.WaypointTraitSymbolUncharted
+	WaypointTraitUnderConstruction     = model.WaypointTraitSymbolUnderConstruction
+	WaypointTraitMarketplace           = model.WaypointTraitSymbolMarketplace
+	WaypointTraitShipyard              = model.WaypointTraitSymbolShipyard
+	WaypointTraitOutpost               = model.WaypointTraitSymbolOutpost
+	WaypointTraitScatteredSettlements  = model.WaypointTraitSymbolScatteredSettlements
+	WaypointTraitSprawlingCities       = model.WaypointTraitSymbolSprawlingCities
+	WaypointTraitMegaStructures        = model.WaypointTraitSymbolMegaStructures
+	WaypointTraitPirateBase            = model.WaypointTraitSymbolPirateBase
+	WaypointTraitOvercrowded           = model.WaypointTraitSymbolOvercrowded
+	WaypointTraitHighTech              = model.WaypointTraitSymbolHighTech
+	WaypointTraitCorrupt               = model.WaypointTraitSymbolCorrupt
+	WaypointTraitBureaucratic          = model.WaypointTraitSymbolBureaucratic
+	WaypointTraitTradingHub            = model.WaypointTraitSymbolTradingHub
+	WaypointTraitIndustrial            = model.WaypointTraitSymbolIndustrial
+	WaypointTraitBlackMarket           = model.WaypointTraitSymbolBlackMarket
+	WaypointTraitResearchFacility      = model.WaypointTraitSymbolResearchFacility
+	WaypointTraitMilitaryBase          = model.WaypointTraitSymbolMilitaryBase
+	WaypointTraitSurveillanceOutpost   = model.WaypointTraitSymbolSurveillanceOutpost
+	WaypointTraitExplorationOutpost    = model.WaypointTraitSymbolExplorationOutpost
+	WaypointTraitMineralDeposits       = model.WaypointTraitSymbolMineralDeposits
+	WaypointTraitCommonMetalDeposits   = model.WaypointTraitSymbolCommonMetalDeposits
+	WaypointTraitPreciousMetalDeposits = model.WaypointTraitSymbolPreciousMetalDeposits
+	WaypointTraitRareMetalDeposits     = model.WaypointTraitSymbolRareMetalDeposits
+	WaypointTraitMethanePools          = model.WaypointTraitSymbolMethanePools
+	WaypointTraitIceCrystals           = model.WaypointTraitSymbolIceCrystals
+	WaypointTraitExplosiveGases        = model.WaypointTraitSymbolExplosiveGases
+	WaypointTraitStrongMagnetosphere   = model.WaypointTraitSymbolStrongMagnetosphere
+	WaypointTraitVibrantAuroras        = model.WaypointTraitSymbolVibrantAuroras
+	WaypointTraitSaltFlats             = model.WaypointTraitSymbolSaltFlats
+	WaypointTraitCanyons               = model.WaypointTraitSymbolCanyons
+	WaypointTraitPerpetualDaylight     = model.WaypointTraitSymbolPerpetualDaylight
+	WaypointTraitPerpetualOvercast     = model.WaypointTraitSymbolPerpetualOvercast
+	WaypointTraitDrySeabeds            = model.WaypointTraitSymbolDrySeabeds
+	WaypointTraitMagmaSeas             = model.WaypointTraitSymbolMagmaSeas
+	WaypointTraitSupervolcanoes        = model.WaypointTraitSymbolSupervolcanoes
+	WaypointTraitAshClouds             = model.WaypointTraitSymbolAshClouds
+	WaypointTraitVastRuins             = model.WaypointTraitSymbolVastRuins
+	WaypointTraitMutatedFlora          = model.WaypointTraitSymbolMutatedFlora
+	WaypointTraitTerraformed           = model.WaypointTraitSymbolTerraformed
+	WaypointTraitExtremeTemperatures   = model.WaypointTraitSymbolExtremeTemperatures
+	WaypointTraitExtremePressure       = model.WaypointTraitSymbolExtremePressure
+	WaypointTraitDiverseLife           = model.WaypointTraitSymbolDiverseLife
+	WaypointTraitScarceLife            = model.WaypointTraitSymbolScarceLife
+	WaypointTraitFossils               = model.WaypointTraitSymbolFossils
+	WaypointTraitWeakGravity           = model.WaypointTraitSymbolWeakGravity
+	WaypointTraitStrongGravity         = model.WaypointTraitSymbolStrongGravity
+	WaypointTraitCrushingGravity       = model.WaypointTraitSymbolCrushingGravity
+	WaypointTraitToxicAtmosphere       = model.WaypointTraitSymbolToxicAtmosphere
+	WaypointTraitCorrosiveAtmosphere   = model.WaypointTraitSymbolCorrosiveAtmosphere
+	WaypointTraitBreathableAtmosphere  = model.WaypointTraitSymbolBreathableAtmosphere
+	WaypointTraitThinAtmosphere        = model.WaypointTraitSymbolThinAtmosphere
+	WaypointTraitJovian                = model.WaypointTraitSymbolJovian
+	WaypointTraitRocky                 = model.WaypointTraitSymbolRocky
+	WaypointTraitVolcanic              = model.WaypointTraitSymbolVolcanic
+	WaypointTraitFrozen                = model.WaypointTraitSymbolFrozen
+	WaypointTraitSwamp                 = model.WaypointTraitSymbolSwamp
+	WaypointTraitBarren                = model.WaypointTraitSymbolBarren
+	WaypointTraitTemperate             = model.WaypointTraitSymbolTemperate
+	WaypointTraitJungle                = model.WaypointTraitSymbolJungle
+	WaypointTraitOcean                 = model.WaypointTraitSymbolOcean
+	WaypointTraitRadioactive           = model.WaypointTraitSymbolRadioactive
+	WaypointTraitMicroGravityAnomalies = model.WaypointTraitSymbolMicroGravityAnomalies
+	WaypointTraitDebrisCluster         = model.WaypointTraitSymbolDebrisCluster
+	WaypointTraitDeepCraters           = model.WaypointTraitSymbolDeepCraters
+	WaypointTraitShallowCraters        = model.WaypointTraitSymbolShallowCraters
+	WaypointTraitUnstableComposition   = model.WaypointTraitSymbolUnstableComposition
+	WaypointTraitHollowedInterior      = model.WaypointTraitSymbolHollowedInterior
+	WaypointTraitStripped              = model.WaypointTraitSymbolStripped
+)
+
+// WaypointTraitSymbols lists every WaypointTraitSymbol the API knows about
+var WaypointTraitSymbols = model.WaypointTraitSymbolValues
+
+func ParseWaypointTraitSymbol(value string) (WaypointTraitSymbol, error) {
+	return parseEnum("waypoint trait", value, WaypointTraitSymbols)
+}
+
+// TradeSymbol identifies a good that can be bought, sold, mined or hauled
+type TradeSymbol = model.TradeSymbol
+
+const (
+	TradePreciousStones          = model.TradeSymbolPreciousStones
+	TradeQuartzSand              = model.TradeSymbolQuartzSand
+	TradeSiliconCrystals         = model.TradeSymbolSiliconCrystals
+	TradeAmmoniaIce              = model.TradeSymbolAmmoniaIce
+	TradeLiquidHydrogen          = model.TradeSymbolLiquidHydrogen
+	TradeLiquidNitrogen          = model.TradeSymbolLiquidNitrogen
+	TradeIceWater                = model.TradeSymbolIceWater
+	TradeExoticMatter            = model.TradeSymbolExoticMatter
+	TradeAdvancedCircuitry       = model.TradeSymbolAdvancedCircuitry
+	TradeGravitonEmitters        = model.TradeSymbolGravitonEmitters
+	TradeIron                    = model.TradeSymbolIron
+	TradeIronOre                 = model.TradeSymbolIronOre
+	TradeCopper                  = model.TradeSymbolCopper
+	TradeCopperOre               = model.TradeSymbolCopperOre
+	TradeAluminum                = model.TradeSymbolAluminum
+	TradeAluminumOre             = model.TradeSymbolAluminumOre
+	TradeSilver                  = model.TradeSymbolSilver
+	TradeSilverOre               = model.TradeSymbolSilverOre
+	TradeGold                    = model.TradeSymbolGold
+	TradeGoldOre                 = model.TradeSymbolGoldOre
+	TradePlatinum                = model.TradeSymbolPlatinum
+	TradePlatinumOre             = model.TradeSymbolPlatinumOre
+	TradeDiamonds                = model.TradeSymbolDiamonds
+	TradeUranite                 = model.TradeSymbolUranite
+	TradeUraniteOre              = model.TradeSymbolUraniteOre
+	TradeMeritium                = model.TradeSymbolMeritium
+	TradeMeritiumOre             = model.TradeSymbolMeritiumOre
+	TradeHydrocarbon             = model.TradeSymbolHydrocarbon
+	TradeAntimatter              = model.TradeSymbolAntimatter
+	TradeFabMats                 = model.TradeSymbolFabMats
+	TradeFertilizers             = model.TradeSymbolFertilizers
+	TradeFabrics                 = model.TradeSymbolFabrics
+	TradeFood                    = model.TradeSymbolFood
+	TradeJewelry                 = model.TradeSymbolJewelry
+	TradeMachinery               = model.TradeSymbolMachinery
+	TradeFirearms                = model.TradeSymbolFirearms
+	TradeAssaultRifles           = model.TradeSymbolAssaultRifles
+	TradeMilitaryEquipment       = model.TradeSymbolMilitaryEquipment
+	TradeExplosives              = model.TradeSymbolExplosives
+	TradeLabInstruments          = model.TradeSymbolLabInstruments
+	TradeAmmunition              = model.TradeSymbolAmmunition
+	TradeElectronics             = model.TradeSymbolElectronics
+	TradeShipPlating             = model.TradeSymbolShipPlating
+	TradeShipParts               = model.TradeSymbolShipParts
+	TradeEquipment               = model.TradeSymbolEquipment
+	TradeFuel                    = model.TradeSymbolFuel
+	TradeMedicine                = model.TradeSymbolMedicine
+	TradeDrugs                   = model.TradeSymbolDrugs
+	TradeClothing                = model.TradeSymbolClothing
+	TradeMicroprocessors         = model.TradeSymbolMicroprocessors
+	TradePlastics                = model.TradeSymbolPlastics
+	TradePolynucleotides         = model.TradeSymbolPolynucleotides
+	TradeBiocomposites           = model.TradeSymbolBiocomposites
+	TradeQuantumStabilizers      = model.TradeSymbolQuantumStabilizers
+	TradeNanobots                = model.TradeSymbolNanobots
+	TradeAiMainframes            = model.TradeSymbolAiMainframes
+	TradeQuantumDrives           = model.TradeSymbolQuantumDrives
+	TradeRoboticDrones           = model.TradeSymbolRoboticDrones
+	TradeCyberImplants           = model.TradeSymbolCyberImplants
+	TradeGeneTherapeutics        = model.TradeSymbolGeneTherapeutics
+	TradeNeuralChips             = model.TradeSymbolNeuralChips
+	TradeMoodRegulators          = model.TradeSymbolMoodRegulators
+	TradeViralAgents             = model.TradeSymbolViralAgents
+	TradeMicroFusionGenerators   = model.TradeSymbolMicroFusionGenerators
+	TradeSupergrains             = model.TradeSymbolSupergrains
+	TradeLaserRifles             = model.TradeSymbolLaserRifles
+	TradeHolographics            = model.TradeSymbolHolographics
+	TradeShipSalvage             = model.TradeSymbolShipSalvage
+	TradeRelicTech               = model.TradeSymbolRelicTech
+	TradeNovelLifeforms          = model.TradeSymbolNovelLifeforms
+	TradeBotanicalSpecimens      = model.TradeSymbolBotanicalSpecimens
+	TradeCulturalArtifacts       = model.TradeSymbolCulturalArtifacts
+	TradeFrameProbe              = model.TradeSymbolFrameProbe
+	TradeFrameDrone              = model.TradeSymbolFrameDrone
+	TradeFrameInterceptor        = model.TradeSymbolFrameInterceptor
+	TradeFrameRacer              = model.TradeSymbolFrameRacer
+	TradeFrameFighter            = model.TradeSymbolFrameFighter
+	TradeFrameFrigate            = model.TradeSymbolFrameFrigate
+	TradeFrameShuttle            = model.TradeSymbolFrameShuttle
+	TradeFrameExplorer           = model.TradeSymbolFrameExplorer
+	TradeFrameMiner              = model.TradeSymbolFrameMiner
+	TradeFrameLightFreighter     = model.TradeSymbolFrameLightFreighter
+	TradeFrameHeavyFreighter     = model.TradeSymbolFrameHeavyFreighter
+	TradeFrameTransport          = model.TradeSymbolFrameTransport
+	TradeFrameDestroyer          = model.TradeSymbolFrameDestroyer
+	TradeFrameCruiser            = model.TradeSymbolFrameCruiser
+	TradeFrameCarrier            = model.TradeSymbolFrameCarrier
+	TradeReactorSolarI           = model.TradeSymbolReactorSolarI
+	TradeReactorFusionI          = model.TradeSymbolReactorFusionI
+	TradeReactorFissionI         = model.TradeSymbolReactorFissionI
+	TradeReactorChemicalI        = model.TradeSymbolReactorChemicalI
+	TradeReactorAntimatterI      = model.TradeSymbolReactorAntimatterI
+	TradeEngineImpulseDriveI     = model.TradeSymbolEngineImpulseDriveI
+	TradeEngineIonDriveI         = model.TradeSymbolEngineIonDriveI
+	TradeEngineIonDriveII        = model.TradeSymbolEngineIonDriveIi
+	TradeEngineHyperDriveI       = model.TradeSymbolEngineHyperDriveI
+	TradeModuleMineralProcessorI = model.TradeSymbolModuleMineralProcessorI
+	TradeModuleGasProcessorI     = model.TradeSymbolModuleGasProcessorI
+	TradeModuleCargoHoldI        = model.TradeSymbolModuleCargoHoldI
+	TradeModuleCargoHoldII       = model.TradeSymbolModuleCargoHoldIi
+	TradeModuleCargoHoldIII      = model.TradeSymbolModuleCargoHoldIii
+	TradeModuleCrewQuartersI     = model.TradeSymbolModuleCrewQuartersI
+	TradeModuleEnvoyQuartersI    = model.TradeSymbolModuleEnvoyQuartersI
+	TradeModulePassengerCabinI   = model.TradeSymbolModulePassengerCabinI
+	TradeModuleMicroRefineryI    = model.TradeSymbolModuleMicroRefineryI
+	TradeModuleScienceLabI       = model.TradeSymbolModuleScienceLabI
+	TradeModuleJumpDriveI        = model.TradeSymbolModuleJumpDriveI
+	TradeModuleJumpDriveII       = model.TradeSymbolModuleJumpDriveIi
+	TradeModuleJumpDriveIII      = model.TradeSymbolModuleJumpDriveIii
+	TradeModuleWarpDriveI        = model.TradeSymbolModuleWarpDriveI
+	TradeModuleWarpDriveII       = model.TradeSymbolModuleWarpDriveIi
+	TradeModuleWarpDriveIII      = model.TradeSymbolModuleWarpDriveIii
+	TradeModuleShieldGeneratorI  = model.TradeSymbolModuleShieldGeneratorI
+	TradeModuleShieldGeneratorII = model.TradeSymbolModuleShieldGeneratorIi
+	TradeModuleOreRefineryI      = model.TradeSymbolModuleOreRefineryI
+	TradeModuleFuelRefineryI     = model.TradeSymbolModuleFuelRefineryI
+	TradeMountGasSiphonI         = model.TradeSymbolMountGasSiphonI
+	TradeMountGasSiphonII        = model.TradeSymbolMountGasSiphonIi
+	TradeMountGasSiphonIII       = model.TradeSymbolMountGasSiphonIii
+	TradeMountSurveyorI          = model.TradeSymbolMountSurveyorI
+	TradeMountSurveyorII         = model.TradeSymbolMountSurveyorIi
+	TradeMountSurveyorIII        = model.TradeSymbolMountSurveyorIii
+	TradeMountSensorArrayI       = model.TradeSymbolMountSensorArrayI
+	TradeMountSensorArrayII      = model.TradeSymbolMountSensorArrayIi
+	TradeMountSensorArrayIII     = model.TradeSymbolMountSensorArrayIii
+	TradeMountMiningLaserI       = model.TradeSymbolMountMiningLaserI
+	TradeMountMiningLaserII      = model.TradeSymbolMountMiningLaserIi
+	TradeMountMiningLaserIII     = model.TradeSymbolMountMiningLaserIii
+	TradeMountLaserCannonI       = model.TradeSymbolMountLaserCannonI
+	TradeMountMissileLauncherI   = model.TradeSymbolMountMissileLauncherI
+	TradeMountTurretI            = model.TradeSymbolMountTurretI
+	TradeShipProbe               = model.TradeSymbolShipProbe
+	TradeShipMiningDrone         = model.TradeSymbolShipMiningDrone
+	TradeShipSiphonDrone         = model.TradeSymbolShipSiphonDrone
+	TradeShipInterceptor         = model.TradeSymbolShipInterceptor
+	TradeShipLightHauler         = model.TradeSymbolShipLightHauler
+	TradeShipCommandFrigate      = model.TradeSymbolShipCommandFrigate
+	TradeShipExplorer            = model.TradeSymbolShipExplorer
+	TradeShipHeavyFreighter      = model.TradeSymbolShipHeavyFreighter
+	TradeShipLightShuttle        = model.TradeSymbolShipLightShuttle
+	TradeShipOreHound            = model.TradeSymbolShipOreHound
+	TradeShipRefiningFreighter   = model.TradeSymbolShipRefiningFreighter
+	TradeShipSurveyor            = model.TradeSymbolShipSurveyor
+)
+
+// TradeSymbols lists every TradeSymbol the API knows about
+var TradeSymbols = model.TradeSymbolValues
+
+func ParseTradeSymbol(value string) (TradeSymbol, error) {
+	return parseEnum("trade symbol", value, TradeSymbols)
+}
+
+// SurveySize is how big a surveyed deposit is, bigger ones take longer to mine out
+type SurveySize = model.SurveySize
+
+const (
+	SurveySizeSmall    = model.SurveySizeSmall
+	SurveySizeModerate = model.SurveySizeModerate
+	SurveySizeLarge    = model.SurveySizeLarge
+)
+
+// SurveySizes lists every SurveySize the API knows about
+var SurveySizes = model.SurveySizeValues
+
+func ParseSurveySize(value string) (SurveySize, error) {
+	return parseEnum("survey size", value, SurveySizes)
+}
@@ -0,0 +1,59 @@
+package spacetrader
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrorCode is one of the numbered codes the API puts on its errors. Only the ones we do
+// something about are named here, the full list is in the API docs.
+type ErrorCode int
+
+const (
+	ErrCodeCooldown           ErrorCode = 4000
+	ErrCodeInsufficientFuel   ErrorCode = 4203
+	ErrCodeAlreadyThere       ErrorCode = 4204
+	ErrCodeInTransit          ErrorCode = 4214
+	ErrCodeSurveyVerification ErrorCode = 4221
+	ErrCodeSurveyExpired      ErrorCode = 4222
+	ErrCodeSurveyExhausted    ErrorCode = 4225
+	ErrCodeCargoFull          ErrorCode = 4228
+	ErrCodeNotInOrbit         ErrorCode = 4236
+	ErrCodeNotDocked          ErrorCode = 4244
+	ErrCodeNotEnoughCredits   ErrorCode = 4600
+	ErrCodeTradeNotSold       ErrorCode = 4602
+	ErrCodeTradeUnitLimit     ErrorCode = 4604
+)
+
+// HasCode reports whether err came from the API with one of codes
+func HasCode(err error, codes ...ErrorCode) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	for _, code := range codes {
+		if ErrorCode(apiErr.Code) == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CooldownOf pulls the ship's cooldown out of a cooldown conflict, so the caller knows how long to wait
+func CooldownOf(err error) (Cooldown, bool) {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || ErrorCode(apiErr.Code) != ErrCodeCooldown {
+		return Cooldown{}, false
+	}
+
+	var data struct {
+		Cooldown Cooldown `json:"cooldown"`
+	}
+	if err := json.Unmarshal(apiErr.Data, &data); err != nil || data.Cooldown.Expiration == "" {
+		return Cooldown{}, false
+	}
+
+	return data.Cooldown, true
+}
@@ -0,0 +1,1165 @@
+// Package fake is an in-memory stand-in for the SpaceTraders API. It keeps its own
+// little universe and moves ships around it the way the real game does, so the
+// spacetrader package and the server can be run without ever touching the network.
+//
+//	universe := fake.New()
+//	server := httptest.NewServer(universe)
+//	spacetrader.BaseURL = server.URL
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"example.com/spacetrader"
+)
+
+// Token is the bearer token the seeded universe expects
+const Token = "fake-token"
+
+// Error codes the real API uses for the mistakes the fake knows how to catch
+const (
+	codeUnauthorized     = 4100
+	codeNotFound         = 404
+	codeInsufficientFuel = 4203
+	codeInTransit        = 4214
+	codeAlreadyThere     = 4204
+	codeNotInOrbit       = 4236
+	codeNotDocked        = 4244
+	codeNotEnoughCredits = 4600
+	codeCooldown         = 4000
+	codeBadRequest       = 400
+	codeSurveyInvalid    = 4221
+	codeSurveyExpired    = 4222
+	codeSurveyExhausted  = 4225
+	codeCargoFull        = 4228
+	codeNotEnoughCargo   = 4219
+	codeTradeNotSold     = 4602
+	codeTradeUnitLimit   = 4604
+)
+
+// Every jump uses up a unit of antimatter, the fake charges a flat price for it
+const antimatterPrice = 5000
+
+// A survey is good for this many extractions before the deposit runs dry
+const surveyExtractions = 10
+
+// Server is the fake API. Everything in it can be poked at directly to set up a scenario,
+// just hold off while requests are in flight.
+type Server struct {
+	mu sync.Mutex
+
+	// Token has to be sent as the bearer token, leave it empty to let anyone in
+	Token string
+	// Now is the fake's clock, swap it out to fast forward through travel times
+	Now func() time.Time
+
+	Agent     spacetrader.Agent
+	Ships     map[string]*spacetrader.Ship
+	Systems   map[string]*spacetrader.System
+	Waypoints map[string]*spacetrader.Waypoint
+	Contracts []spacetrader.Contract
+	// Markets are keyed by waypoint symbol, trade goods only show up while a ship is there
+	Markets map[string]*spacetrader.Market
+	// JumpGates lists where each gate leads, keyed by the gate's waypoint symbol
+	JumpGates map[string][]string
+	// Deposits is what each asteroid gives up when mined, keyed by waypoint symbol
+	Deposits map[string][]spacetrader.TradeSymbol
+	// Surveys that are still good, keyed by signature, with how many extractions each has left
+	Surveys map[string]*fakeSurvey
+	// ExtractCooldown is how long a ship rests after mining or surveying, shorten it to speed a scenario up
+	ExtractCooldown time.Duration
+
+	// extractions counts every dig, it picks which deposit comes up next, surveyed numbers the surveys
+	extractions int
+	surveyed    int
+
+	mux *http.ServeMux
+}
+
+// New builds a fake with a small three system universe, a command ship docked at
+// headquarters, a probe parked at the asteroid field and one open contract
+func New() *Server {
+	s := &Server{
+		Token:     Token,
+		Now:       time.Now,
+		Ships:     map[string]*spacetrader.Ship{},
+		Systems:   map[string]*spacetrader.System{},
+		Waypoints: map[string]*spacetrader.Waypoint{},
+		Markets:   map[string]*spacetrader.Market{},
+		JumpGates: map[string][]string{},
+		Deposits:  map[string][]spacetrader.TradeSymbol{},
+		Surveys:   map[string]*fakeSurvey{},
+
+		ExtractCooldown: 70 * time.Second,
+	}
+
+	s.seed()
+	s.routes()
+
+	return s
+}
+
+func (s *Server) routes() {
+	s.mux = http.NewServeMux()
+
+	s.mux.HandleFunc("GET /my/agent", s.getAgent)
+	s.mux.HandleFunc("GET /my/ships", s.getShips)
+	s.mux.HandleFunc("GET /my/ships/{ship}", s.getShip)
+	s.mux.HandleFunc("POST /my/ships/{ship}/orbit", s.orbitShip)
+	s.mux.HandleFunc("POST /my/ships/{ship}/dock", s.dockShip)
+	s.mux.HandleFunc("POST /my/ships/{ship}/navigate", s.navigateShip)
+	s.mux.HandleFunc("PATCH /my/ships/{ship}/nav", s.setFlightMode)
+	s.mux.HandleFunc("POST /my/ships/{ship}/refuel", s.refuelShip)
+	s.mux.HandleFunc("POST /my/ships/{ship}/jump", s.jumpShip)
+	s.mux.HandleFunc("POST /my/ships/{ship}/warp", s.warpShip)
+	s.mux.HandleFunc("POST /my/ships/{ship}/survey", s.surveyWaypoint)
+	s.mux.HandleFunc("POST /my/ships/{ship}/extract", s.extractResources)
+	s.mux.HandleFunc("POST /my/ships/{ship}/extract/survey", s.extractResources)
+	s.mux.HandleFunc("POST /my/ships/{ship}/jettison", s.jettisonCargo)
+	s.mux.HandleFunc("POST /my/ships/{ship}/sell", s.sellCargo)
+	s.mux.HandleFunc("POST /my/ships/{ship}/purchase", s.purchaseCargo)
+	s.mux.HandleFunc("GET /my/contracts", s.getContracts)
+	s.mux.HandleFunc("POST /my/contracts/{contract}/accept", s.acceptContract)
+	s.mux.HandleFunc("POST /my/contracts/{contract}/deliver", s.deliverContract)
+	s.mux.HandleFunc("POST /my/contracts/{contract}/fulfill", s.fulfillContract)
+	s.mux.HandleFunc("GET /systems", s.getSystems)
+	s.mux.HandleFunc("GET /systems/{system}", s.getSystem)
+	s.mux.HandleFunc("GET /systems/{system}/waypoints", s.getWaypoints)
+	s.mux.HandleFunc("GET /systems/{system}/waypoints/{waypoint}", s.getWaypoint)
+	s.mux.HandleFunc("GET /systems/{system}/waypoints/{waypoint}/market", s.getMarket)
+	s.mux.HandleFunc("GET /systems/{system}/waypoints/{waypoint}/jump-gate", s.getJumpGate)
+
+	// Anything the fake doesn't cover gets the same kind of error body the API would send
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, codeNotFound, fmt.Sprintf("%s %s is not implemented by the fake", r.Method, r.URL.Path))
+	})
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.Token != "" && r.Header.Get("Authorization") != "Bearer "+s.Token {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "Missing or invalid bearer token")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Ships land on their own time, so catch everything up before answering
+	for _, ship := range s.Ships {
+		s.settle(ship)
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// Ship hands back a copy of a ship's current state, handy for checking what a request did
+func (s *Server) Ship(symbol string) (spacetrader.Ship, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ship, ok := s.Ships[symbol]
+	if !ok {
+		return spacetrader.Ship{}, false
+	}
+	s.settle(ship)
+
+	return *ship, true
+}
+
+// settle drops a ship into orbit once its arrival time has passed, and counts down its cooldown
+func (s *Server) settle(ship *spacetrader.Ship) {
+	if expiration, err := time.Parse(time.RFC3339Nano, ship.Cooldown.Expiration); err == nil {
+		ship.Cooldown.RemainingSeconds = max(0, int(expiration.Sub(s.Now()).Seconds()))
+	}
+
+	if ship.Nav.Status != spacetrader.ShipNavStatusInTransit {
+		return
+	}
+
+	arrival, err := time.Parse(time.RFC3339Nano, ship.Nav.Route.Arrival)
+	if err == nil && !s.Now().Before(arrival) {
+		ship.Nav.Status = spacetrader.ShipNavStatusInOrbit
+	}
+}
+
+func (s *Server) getAgent(w http.ResponseWriter, r *http.Request) {
+	s.Agent.ShipCount = len(s.Ships)
+	writeData(w, http.StatusOK, s.Agent, nil)
+}
+
+func (s *Server) getShips(w http.ResponseWriter, r *http.Request) {
+	symbols := []string{}
+	for symbol := range s.Ships {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	ships := []spacetrader.Ship{}
+	for _, symbol := range symbols {
+		ships = append(ships, *s.Ships[symbol])
+	}
+
+	page, meta, ok := paginate(w, r, ships)
+	if !ok {
+		return
+	}
+
+	writeData(w, http.StatusOK, page, &meta)
+}
+
+func (s *Server) getShip(w http.ResponseWriter, r *http.Request) {
+	ship, ok := s.findShip(w, r)
+	if !ok {
+		return
+	}
+
+	writeData(w, http.StatusOK, ship, nil)
+}
+
+func (s *Server) orbitShip(w http.ResponseWriter, r *http.Request) {
+	ship, ok := s.findShip(w, r)
+	if !ok {
+		return
+	}
+
+	if ship.Nav.Status == spacetrader.ShipNavStatusInTransit {
+		writeError(w, http.StatusBadRequest, codeInTransit, fmt.Sprintf("Ship %s is currently in transit", ship.Symbol))
+		return
+	}
+
+	ship.Nav.Status = spacetrader.ShipNavStatusInOrbit
+	writeData(w, http.StatusOK, map[string]any{"nav": ship.Nav}, nil)
+}
+
+func (s *Server) dockShip(w http.ResponseWriter, r *http.Request) {
+	ship, ok := s.findShip(w, r)
+	if !ok {
+		return
+	}
+
+	if ship.Nav.Status == spacetrader.ShipNavStatusInTransit {
+		writeError(w, http.StatusBadRequest, codeInTransit, fmt.Sprintf("Ship %s is currently in transit", ship.Symbol))
+		return
+	}
+
+	ship.Nav.Status = spacetrader.ShipNavStatusDocked
+	writeData(w, http.StatusOK, map[string]any{"nav": ship.Nav}, nil)
+}
+
+func (s *Server) navigateShip(w http.ResponseWriter, r *http.Request) {
+	ship, ok := s.findShip(w, r)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		WaypointSymbol string `json:"waypointSymbol"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.WaypointSymbol == "" {
+		writeError(w, http.StatusUnprocessableEntity, codeBadRequest, "waypointSymbol is required")
+		return
+	}
+
+	if ship.Nav.Status != spacetrader.ShipNavStatusInOrbit {
+		writeError(w, http.StatusBadRequest, codeNotInOrbit, fmt.Sprintf("Ship %s must be in orbit to navigate", ship.Symbol))
+		return
+	}
+
+	destination, ok := s.Waypoints[request.WaypointSymbol]
+	if !ok || spacetrader.SystemSymbol(destination.Symbol) != ship.Nav.SystemSymbol {
+		writeError(w, http.StatusNotFound, codeNotFound, fmt.Sprintf("Waypoint %s is not in system %s", request.WaypointSymbol, ship.Nav.SystemSymbol))
+		return
+	}
+
+	if destination.Symbol == ship.Nav.WaypointSymbol {
+		writeError(w, http.StatusBadRequest, codeAlreadyThere, fmt.Sprintf("Ship %s is already at %s", ship.Symbol, destination.Symbol))
+		return
+	}
+
+	origin := s.Waypoints[ship.Nav.WaypointSymbol]
+	distance := spacetrader.Distance(origin.PosX, origin.PosY, destination.PosX, destination.PosY)
+	mode := ship.Nav.FlightMode
+	if mode == "" {
+		mode = spacetrader.FlightModeCruise
+	}
+
+	// Ships without a fuel tank (probes and the like) travel for free
+	fuel := 0
+	if ship.Fuel.Capacity > 0 {
+		fuel = spacetrader.FuelCost(mode, distance)
+	}
+	if fuel > ship.Fuel.Current {
+		writeError(w, http.StatusBadRequest, codeInsufficientFuel, fmt.Sprintf("Ship %s needs %d fuel but only has %d", ship.Symbol, fuel, ship.Fuel.Current))
+		return
+	}
+
+	departure := s.Now().UTC()
+	arrival := departure.Add(spacetrader.TravelTime(mode, ship.Engine.Speed, distance))
+
+	ship.Fuel.Current -= fuel
+	ship.Nav.Status = spacetrader.ShipNavStatusInTransit
+	ship.Nav.FlightMode = mode
+	ship.Nav.WaypointSymbol = destination.Symbol
+	ship.Nav.Route = spacetrader.ShipRoute{
+		Origin:        routePoint(origin),
+		Destination:   routePoint(destination),
+		DepartureTime: departure.Format(time.RFC3339Nano),
+		Arrival:       arrival.Format(time.RFC3339Nano),
+	}
+
+	writeData(w, http.StatusOK, map[string]any{"nav": ship.Nav, "fuel": ship.Fuel}, nil)
+}
+
+func (s *Server) setFlightMode(w http.ResponseWriter, r *http.Request) {
+	ship, ok := s.findShip(w, r)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		FlightMode spacetrader.FlightMode `json:"flightMode"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || !request.FlightMode.Valid() {
+		writeError(w, http.StatusUnprocessableEntity, codeBadRequest, "flightMode must be one of CRUISE, BURN, DRIFT or STEALTH")
+		return
+	}
+
+	ship.Nav.FlightMode = request.FlightMode
+	writeData(w, http.StatusOK, ship.Nav, nil)
+}
+
+func (s *Server) refuelShip(w http.ResponseWriter, r *http.Request) {
+	ship, ok := s.findShip(w, r)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Units int `json:"units"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Units < 0 {
+			writeError(w, http.StatusUnprocessableEntity, codeBadRequest, "units must be a positive number")
+			return
+		}
+	}
+
+	if ship.Nav.Status != spacetrader.ShipNavStatusDocked {
+		writeError(w, http.StatusBadRequest, codeNotDocked, fmt.Sprintf("Ship %s must be docked to refuel", ship.Symbol))
+		return
+	}
+
+	market, ok := s.Markets[ship.Nav.WaypointSymbol]
+	fuel, sold := spacetrader.MarketTradeGood{}, false
+	if ok {
+		fuel, sold = market.TradeGood(spacetrader.TradeFuel)
+	}
+	if !sold {
+		writeError(w, http.StatusBadRequest, codeNotFound, fmt.Sprintf("No fuel is sold at %s", ship.Nav.WaypointSymbol))
+		return
+	}
+
+	units := request.Units
+	if units == 0 {
+		units = ship.Fuel.Capacity - ship.Fuel.Current
+	}
+	units = min(units, ship.Fuel.Capacity-ship.Fuel.Current)
+
+	// Fuel is bought 100 units to the market unit, and part of one still costs the whole thing
+	price := (units + 99) / 100 * fuel.PurchasePrice
+	if int64(price) > s.Agent.Credits {
+		writeError(w, http.StatusBadRequest, codeNotEnoughCredits, fmt.Sprintf("Fuel costs %d credits but the agent only has %d", price, s.Agent.Credits))
+		return
+	}
+
+	s.Agent.Credits -= int64(price)
+	ship.Fuel.Current += units
+	transaction := spacetrader.MarketTransaction{
+		WaypointSymbol: ship.Nav.WaypointSymbol,
+		ShipSymbol:     ship.Symbol,
+		TradeSymbol:    spacetrader.TradeFuel,
+		Type:           "PURCHASE",
+		Units:          units,
+		PricePerUnit:   fuel.PurchasePrice,
+		TotalPrice:     price,
+		Timestamp:      s.Now().UTC().Format(time.RFC3339Nano),
+	}
+	market.Transactions = append(market.Transactions, transaction)
+
+	writeData(w, http.StatusOK, map[string]any{"agent": s.Agent, "fuel": ship.Fuel, "transaction": transaction}, nil)
+}
+
+func (s *Server) jumpShip(w http.ResponseWriter, r *http.Request) {
+	ship, ok := s.findShip(w, r)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		WaypointSymbol string `json:"waypointSymbol"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.WaypointSymbol == "" {
+		writeError(w, http.StatusUnprocessableEntity, codeBadRequest, "waypointSymbol is required")
+		return
+	}
+
+	if ship.Nav.Status != spacetrader.ShipNavStatusInOrbit {
+		writeError(w, http.StatusBadRequest, codeNotInOrbit, fmt.Sprintf("Ship %s must be in orbit to jump", ship.Symbol))
+		return
+	}
+
+	if ship.Cooldown.RemainingSeconds > 0 {
+		writeErrorData(w, http.StatusConflict, codeCooldown, fmt.Sprintf("Ship %s is on cooldown for another %d seconds", ship.Symbol, ship.Cooldown.RemainingSeconds), map[string]any{"cooldown": ship.Cooldown})
+		return
+	}
+
+	origin := s.Waypoints[ship.Nav.WaypointSymbol]
+	if origin.Type != spacetrader.WaypointTypeJumpGate || origin.IsUnderConstruction {
+		writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("There's no working jump gate at %s", origin.Symbol))
+		return
+	}
+
+	destination, ok := s.Waypoints[request.WaypointSymbol]
+	if !ok || !slices.Contains(s.JumpGates[origin.Symbol], destination.Symbol) {
+		writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("The gate at %s doesn't connect to %s", origin.Symbol, request.WaypointSymbol))
+		return
+	}
+
+	if destination.IsUnderConstruction {
+		writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("The gate at %s is still being built", destination.Symbol))
+		return
+	}
+
+	if s.Agent.Credits < antimatterPrice {
+		writeError(w, http.StatusBadRequest, codeNotEnoughCredits, fmt.Sprintf("Antimatter costs %d credits but the agent only has %d", antimatterPrice, s.Agent.Credits))
+		return
+	}
+
+	from := s.Systems[spacetrader.SystemSymbol(origin.Symbol)]
+	to := s.Systems[spacetrader.SystemSymbol(destination.Symbol)]
+	cooldown := spacetrader.JumpCooldown(spacetrader.Distance(from.PosX, from.PosY, to.PosX, to.PosY))
+	now := s.Now().UTC()
+
+	// Jumps are instant, the ship comes out in orbit around the gate at the other end
+	s.Agent.Credits -= antimatterPrice
+	ship.Nav.Status = spacetrader.ShipNavStatusInOrbit
+	ship.Nav.SystemSymbol = to.Symbol
+	ship.Nav.WaypointSymbol = destination.Symbol
+	ship.Nav.Route = spacetrader.ShipRoute{
+		Origin:        routePoint(origin),
+		Destination:   routePoint(destination),
+		DepartureTime: now.Format(time.RFC3339Nano),
+		Arrival:       now.Format(time.RFC3339Nano),
+	}
+	ship.Cooldown = spacetrader.Cooldown{
+		ShipSymbol:       ship.Symbol,
+		TotalSeconds:     int(cooldown.Seconds()),
+		RemainingSeconds: int(cooldown.Seconds()),
+		Expiration:       now.Add(cooldown).Format(time.RFC3339Nano),
+	}
+	transaction := spacetrader.MarketTransaction{
+		WaypointSymbol: origin.Symbol,
+		ShipSymbol:     ship.Symbol,
+		TradeSymbol:    spacetrader.TradeAntimatter,
+		Type:           "PURCHASE",
+		Units:          1,
+		PricePerUnit:   antimatterPrice,
+		TotalPrice:     antimatterPrice,
+		Timestamp:      now.Format(time.RFC3339Nano),
+	}
+
+	writeData(w, http.StatusOK, map[string]any{"nav": ship.Nav, "cooldown": ship.Cooldown, "transaction": transaction, "agent": s.Agent}, nil)
+}
+
+func (s *Server) warpShip(w http.ResponseWriter, r *http.Request) {
+	ship, ok := s.findShip(w, r)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		WaypointSymbol string `json:"waypointSymbol"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.WaypointSymbol == "" {
+		writeError(w, http.StatusUnprocessableEntity, codeBadRequest, "waypointSymbol is required")
+		return
+	}
+
+	if ship.Nav.Status != spacetrader.ShipNavStatusInOrbit {
+		writeError(w, http.StatusBadRequest, codeNotInOrbit, fmt.Sprintf("Ship %s must be in orbit to warp", ship.Symbol))
+		return
+	}
+
+	if !spacetrader.CanWarp(*ship) {
+		writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("Ship %s doesn't have a warp drive", ship.Symbol))
+		return
+	}
+
+	destination, ok := s.Waypoints[request.WaypointSymbol]
+	if !ok {
+		writeError(w, http.StatusNotFound, codeNotFound, fmt.Sprintf("Waypoint %s not found", request.WaypointSymbol))
+		return
+	}
+
+	if spacetrader.SystemSymbol(destination.Symbol) == ship.Nav.SystemSymbol {
+		writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("Waypoint %s is in the same system, navigate there instead", destination.Symbol))
+		return
+	}
+
+	origin := s.Waypoints[ship.Nav.WaypointSymbol]
+	from := s.Systems[ship.Nav.SystemSymbol]
+	to := s.Systems[spacetrader.SystemSymbol(destination.Symbol)]
+	distance := spacetrader.Distance(from.PosX, from.PosY, to.PosX, to.PosY)
+	mode := ship.Nav.FlightMode
+	if mode == "" {
+		mode = spacetrader.FlightModeCruise
+	}
+
+	fuel := 0
+	if ship.Fuel.Capacity > 0 {
+		fuel = spacetrader.FuelCost(mode, distance)
+	}
+	if fuel > ship.Fuel.Current {
+		writeError(w, http.StatusBadRequest, codeInsufficientFuel, fmt.Sprintf("Ship %s needs %d fuel but only has %d", ship.Symbol, fuel, ship.Fuel.Current))
+		return
+	}
+
+	departure := s.Now().UTC()
+	arrival := departure.Add(spacetrader.WarpTime(mode, ship.Engine.Speed, distance))
+
+	ship.Fuel.Current -= fuel
+	ship.Nav.Status = spacetrader.ShipNavStatusInTransit
+	ship.Nav.FlightMode = mode
+	ship.Nav.SystemSymbol = to.Symbol
+	ship.Nav.WaypointSymbol = destination.Symbol
+	ship.Nav.Route = spacetrader.ShipRoute{
+		Origin:        routePoint(origin),
+		Destination:   routePoint(destination),
+		DepartureTime: departure.Format(time.RFC3339Nano),
+		Arrival:       arrival.Format(time.RFC3339Nano),
+	}
+
+	writeData(w, http.StatusOK, map[string]any{"nav": ship.Nav, "fuel": ship.Fuel}, nil)
+}
+
+type fakeSurvey struct {
+	spacetrader.Survey
+	Remaining int
+}
+
+func (s *Server) surveyWaypoint(w http.ResponseWriter, r *http.Request) {
+	ship, ok := s.findShip(w, r)
+	if !ok {
+		return
+	}
+
+	if !spacetrader.CanSurvey(*ship) {
+		writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("Ship %s doesn't have a surveyor", ship.Symbol))
+		return
+	}
+
+	deposits, ok := s.mineable(w, ship)
+	if !ok {
+		return
+	}
+
+	now := s.Now().UTC()
+	surveys := []spacetrader.Survey{}
+	for i := range 2 {
+		s.surveyed++
+		survey := spacetrader.Survey{
+			Signature:  fmt.Sprintf("%s-%d", ship.Nav.WaypointSymbol, s.surveyed),
+			Symbol:     ship.Nav.WaypointSymbol,
+			Expiration: now.Add(15 * time.Minute).Format(time.RFC3339Nano),
+			Size:       "MODERATE",
+		}
+		// Each survey leans towards a different pair of deposits
+		for j := range 3 {
+			survey.Deposits = append(survey.Deposits, spacetrader.SurveyDeposit{Symbol: deposits[(i+j/2)%len(deposits)]})
+		}
+
+		s.Surveys[survey.Signature] = &fakeSurvey{Survey: survey, Remaining: surveyExtractions}
+		surveys = append(surveys, survey)
+	}
+
+	s.cool(ship)
+
+	writeData(w, http.StatusCreated, spacetrader.ShipSurvey{Cooldown: ship.Cooldown, Surveys: surveys}, nil)
+}
+
+func (s *Server) extractResources(w http.ResponseWriter, r *http.Request) {
+	ship, ok := s.findShip(w, r)
+	if !ok {
+		return
+	}
+
+	var survey *fakeSurvey
+	if r.ContentLength != 0 {
+		var request spacetrader.Survey
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, codeBadRequest, "the survey couldn't be read")
+			return
+		}
+		if request.Signature != "" {
+			survey = s.Surveys[request.Signature]
+			if survey == nil || survey.Symbol != ship.Nav.WaypointSymbol {
+				writeError(w, http.StatusBadRequest, codeSurveyInvalid, fmt.Sprintf("Survey %s isn't valid for %s", request.Signature, ship.Nav.WaypointSymbol))
+				return
+			}
+		}
+	}
+
+	if !spacetrader.CanMine(*ship) {
+		writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("Ship %s doesn't have a mining laser", ship.Symbol))
+		return
+	}
+
+	deposits, ok := s.mineable(w, ship)
+	if !ok {
+		return
+	}
+
+	if survey != nil {
+		if expiration, err := time.Parse(time.RFC3339Nano, survey.Expiration); err == nil && s.Now().After(expiration) {
+			delete(s.Surveys, survey.Signature)
+			writeError(w, http.StatusBadRequest, codeSurveyExpired, fmt.Sprintf("Survey %s has expired", survey.Signature))
+			return
+		}
+		if survey.Remaining <= 0 {
+			delete(s.Surveys, survey.Signature)
+			writeError(w, http.StatusBadRequest, codeSurveyExhausted, fmt.Sprintf("Survey %s has been mined out", survey.Signature))
+			return
+		}
+
+		deposits = []spacetrader.TradeSymbol{}
+		for _, deposit := range survey.Deposits {
+			deposits = append(deposits, deposit.Symbol)
+		}
+		survey.Remaining--
+	}
+
+	space := ship.Cargo.Capacity - ship.Cargo.Units
+	if space <= 0 {
+		writeError(w, http.StatusBadRequest, codeCargoFull, fmt.Sprintf("Ship %s has no room left in its hold", ship.Symbol))
+		return
+	}
+
+	// Every laser pitches in its strength, whatever doesn't fit in the hold is left behind
+	strength := 0
+	for _, mount := range ship.Mounts {
+		switch mount.Symbol {
+		case spacetrader.TradeMountMiningLaserI, spacetrader.TradeMountMiningLaserII, spacetrader.TradeMountMiningLaserIII:
+			strength += mount.Strength
+		}
+	}
+
+	yield := spacetrader.ExtractionYield{Symbol: deposits[s.extractions%len(deposits)], Units: min(max(1, strength), space)}
+	s.extractions++
+	addCargo(ship, yield.Symbol, yield.Units)
+	s.cool(ship)
+
+	extract := spacetrader.Extract{
+		Extraction: spacetrader.ShipExtraction{ShipSymbol: ship.Symbol, Yield: yield},
+		Cooldown:   ship.Cooldown,
+		Cargo:      ship.Cargo,
+	}
+	writeData(w, http.StatusCreated, extract, nil)
+}
+
+func (s *Server) jettisonCargo(w http.ResponseWriter, r *http.Request) {
+	ship, ok := s.findShip(w, r)
+	if !ok {
+		return
+	}
+
+	symbol, units, ok := cargoOrder(w, r)
+	if !ok {
+		return
+	}
+
+	if !removeCargo(ship, symbol, units) {
+		writeError(w, http.StatusBadRequest, codeNotEnoughCargo, fmt.Sprintf("Ship %s doesn't have %d units of %s", ship.Symbol, units, symbol))
+		return
+	}
+
+	writeData(w, http.StatusOK, map[string]any{"cargo": ship.Cargo}, nil)
+}
+
+func (s *Server) sellCargo(w http.ResponseWriter, r *http.Request) {
+	s.trade(w, r, "SELL")
+}
+
+func (s *Server) purchaseCargo(w http.ResponseWriter, r *http.Request) {
+	s.trade(w, r, "PURCHASE")
+}
+
+// trade buys or sells cargo at the market the ship is docked at, kind is PURCHASE or SELL
+func (s *Server) trade(w http.ResponseWriter, r *http.Request, kind spacetrader.MarketTransactionType) {
+	ship, ok := s.findShip(w, r)
+	if !ok {
+		return
+	}
+
+	symbol, units, ok := cargoOrder(w, r)
+	if !ok {
+		return
+	}
+
+	if ship.Nav.Status != spacetrader.ShipNavStatusDocked {
+		writeError(w, http.StatusBadRequest, codeNotDocked, fmt.Sprintf("Ship %s must be docked to trade", ship.Symbol))
+		return
+	}
+
+	market, ok := s.Markets[ship.Nav.WaypointSymbol]
+	good, traded := spacetrader.MarketTradeGood{}, false
+	if ok {
+		good, traded = market.TradeGood(symbol)
+	}
+	if !traded {
+		writeError(w, http.StatusBadRequest, codeTradeNotSold, fmt.Sprintf("%s isn't traded at %s", symbol, ship.Nav.WaypointSymbol))
+		return
+	}
+
+	if units > good.TradeVolume {
+		writeError(w, http.StatusBadRequest, codeTradeUnitLimit, fmt.Sprintf("%s trades at most %d units of %s at a time", market.Symbol, good.TradeVolume, symbol))
+		return
+	}
+
+	price := good.SellPrice
+	if kind == "PURCHASE" {
+		price = good.PurchasePrice
+		switch {
+		case int64(units*price) > s.Agent.Credits:
+			writeError(w, http.StatusBadRequest, codeNotEnoughCredits, fmt.Sprintf("%d %s costs %d credits but the agent only has %d", units, symbol, units*price, s.Agent.Credits))
+			return
+		case units > ship.Cargo.Capacity-ship.Cargo.Units:
+			writeError(w, http.StatusBadRequest, codeCargoFull, fmt.Sprintf("Ship %s doesn't have room for %d units", ship.Symbol, units))
+			return
+		}
+
+		addCargo(ship, symbol, units)
+		s.Agent.Credits -= int64(units * price)
+	} else {
+		if !removeCargo(ship, symbol, units) {
+			writeError(w, http.StatusBadRequest, codeNotEnoughCargo, fmt.Sprintf("Ship %s doesn't have %d units of %s", ship.Symbol, units, symbol))
+			return
+		}
+		s.Agent.Credits += int64(units * price)
+	}
+
+	transaction := spacetrader.MarketTransaction{
+		WaypointSymbol: market.Symbol,
+		ShipSymbol:     ship.Symbol,
+		TradeSymbol:    symbol,
+		Type:           kind,
+		Units:          units,
+		PricePerUnit:   price,
+		TotalPrice:     units * price,
+		Timestamp:      s.Now().UTC().Format(time.RFC3339Nano),
+	}
+	market.Transactions = append(market.Transactions, transaction)
+
+	writeData(w, http.StatusCreated, spacetrader.CargoTrade{Agent: s.Agent, Cargo: ship.Cargo, Transaction: transaction}, nil)
+}
+
+// mineable checks the ship is orbiting an asteroid and off cooldown, handing back what can be dug up there
+func (s *Server) mineable(w http.ResponseWriter, ship *spacetrader.Ship) ([]spacetrader.TradeSymbol, bool) {
+	if ship.Nav.Status != spacetrader.ShipNavStatusInOrbit {
+		writeError(w, http.StatusBadRequest, codeNotInOrbit, fmt.Sprintf("Ship %s must be in orbit to mine", ship.Symbol))
+		return nil, false
+	}
+
+	if ship.Cooldown.RemainingSeconds > 0 {
+		writeErrorData(w, http.StatusConflict, codeCooldown, fmt.Sprintf("Ship %s is on cooldown for another %d seconds", ship.Symbol, ship.Cooldown.RemainingSeconds), map[string]any{"cooldown": ship.Cooldown})
+		return nil, false
+	}
+
+	deposits := s.Deposits[ship.Nav.WaypointSymbol]
+	if len(deposits) == 0 {
+		writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("There's nothing to mine at %s", ship.Nav.WaypointSymbol))
+		return nil, false
+	}
+
+	return deposits, true
+}
+
+// cool starts the ship's reactor cooldown after mining or surveying
+func (s *Server) cool(ship *spacetrader.Ship) {
+	now := s.Now().UTC()
+	ship.Cooldown = spacetrader.Cooldown{
+		ShipSymbol:       ship.Symbol,
+		TotalSeconds:     int(s.ExtractCooldown.Seconds()),
+		RemainingSeconds: int(s.ExtractCooldown.Seconds()),
+		Expiration:       now.Add(s.ExtractCooldown).Format(time.RFC3339Nano),
+	}
+}
+
+// cargoOrder reads the good and units out of a jettison, sell or purchase request
+func cargoOrder(w http.ResponseWriter, r *http.Request) (spacetrader.TradeSymbol, int, bool) {
+	var request struct {
+		Symbol spacetrader.TradeSymbol `json:"symbol"`
+		Units  int                     `json:"units"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Symbol == "" || request.Units < 1 {
+		writeError(w, http.StatusUnprocessableEntity, codeBadRequest, "symbol and a positive number of units are required")
+		return "", 0, false
+	}
+
+	return request.Symbol, request.Units, true
+}
+
+func addCargo(ship *spacetrader.Ship, symbol spacetrader.TradeSymbol, units int) {
+	ship.Cargo.Units += units
+	for i := range ship.Cargo.Inventory {
+		if ship.Cargo.Inventory[i].Symbol == symbol {
+			ship.Cargo.Inventory[i].Units += units
+			return
+		}
+	}
+
+	ship.Cargo.Inventory = append(ship.Cargo.Inventory, spacetrader.Cargo{Symbol: symbol, Name: string(symbol), Units: units})
+}
+
+// removeCargo takes units of a good out of the hold, false if there isn't that much in it
+func removeCargo(ship *spacetrader.Ship, symbol spacetrader.TradeSymbol, units int) bool {
+	for i := range ship.Cargo.Inventory {
+		if ship.Cargo.Inventory[i].Symbol != symbol {
+			continue
+		}
+		if ship.Cargo.Inventory[i].Units < units {
+			return false
+		}
+
+		ship.Cargo.Units -= units
+		ship.Cargo.Inventory[i].Units -= units
+		if ship.Cargo.Inventory[i].Units == 0 {
+			ship.Cargo.Inventory = slices.Delete(ship.Cargo.Inventory, i, i+1)
+		}
+		return true
+	}
+
+	return false
+}
+
+func (s *Server) getContracts(w http.ResponseWriter, r *http.Request) {
+	page, meta, ok := paginate(w, r, s.Contracts)
+	if !ok {
+		return
+	}
+
+	writeData(w, http.StatusOK, page, &meta)
+}
+
+func (s *Server) acceptContract(w http.ResponseWriter, r *http.Request) {
+	contract, ok := s.findContract(w, r)
+	if !ok {
+		return
+	}
+
+	if contract.Accepted {
+		writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("Contract %s has already been accepted", contract.Identifier))
+		return
+	}
+
+	contract.Accepted = true
+	s.Agent.Credits += int64(contract.Terms.Payment.OnAccepted)
+
+	writeData(w, http.StatusOK, spacetrader.ContractUpdate{Agent: s.Agent, Contract: *contract}, nil)
+}
+
+func (s *Server) deliverContract(w http.ResponseWriter, r *http.Request) {
+	contract, ok := s.findContract(w, r)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		ShipSymbol  string                  `json:"shipSymbol"`
+		TradeSymbol spacetrader.TradeSymbol `json:"tradeSymbol"`
+		Units       int                     `json:"units"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Units < 1 {
+		writeError(w, http.StatusUnprocessableEntity, codeBadRequest, "shipSymbol, tradeSymbol and a positive number of units are required")
+		return
+	}
+
+	ship, ok := s.Ships[request.ShipSymbol]
+	if !ok {
+		writeError(w, http.StatusNotFound, codeNotFound, fmt.Sprintf("Ship %s not found", request.ShipSymbol))
+		return
+	}
+
+	if !contract.Accepted || contract.Fulfilled {
+		writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("Contract %s isn't open for deliveries", contract.Identifier))
+		return
+	}
+
+	if ship.Nav.Status != spacetrader.ShipNavStatusDocked {
+		writeError(w, http.StatusBadRequest, codeNotDocked, fmt.Sprintf("Ship %s must be docked to deliver", ship.Symbol))
+		return
+	}
+
+	for i := range contract.Terms.Deliver {
+		delivery := &contract.Terms.Deliver[i]
+		if delivery.TradeSymbol != request.TradeSymbol || delivery.DestinationSymbol != ship.Nav.WaypointSymbol {
+			continue
+		}
+
+		if request.Units > delivery.UnitsRequired-delivery.UnitsFulfilled {
+			writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("Contract %s only needs %d more %s", contract.Identifier, delivery.UnitsRequired-delivery.UnitsFulfilled, delivery.TradeSymbol))
+			return
+		}
+
+		if !removeCargo(ship, request.TradeSymbol, request.Units) {
+			writeError(w, http.StatusBadRequest, codeNotEnoughCargo, fmt.Sprintf("Ship %s doesn't have %d units of %s", ship.Symbol, request.Units, request.TradeSymbol))
+			return
+		}
+
+		delivery.UnitsFulfilled += request.Units
+		writeData(w, http.StatusOK, spacetrader.ContractDeliveryUpdate{Contract: *contract, Cargo: ship.Cargo}, nil)
+		return
+	}
+
+	writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("Contract %s doesn't want %s delivered to %s", contract.Identifier, request.TradeSymbol, ship.Nav.WaypointSymbol))
+}
+
+func (s *Server) fulfillContract(w http.ResponseWriter, r *http.Request) {
+	contract, ok := s.findContract(w, r)
+	if !ok {
+		return
+	}
+
+	if !contract.Accepted || contract.Fulfilled {
+		writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("Contract %s isn't open", contract.Identifier))
+		return
+	}
+
+	for _, delivery := range contract.Terms.Deliver {
+		if delivery.UnitsFulfilled < delivery.UnitsRequired {
+			writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Sprintf("Contract %s still needs %d %s", contract.Identifier, delivery.UnitsRequired-delivery.UnitsFulfilled, delivery.TradeSymbol))
+			return
+		}
+	}
+
+	contract.Fulfilled = true
+	s.Agent.Credits += int64(contract.Terms.Payment.OnFulfilled)
+
+	writeData(w, http.StatusOK, spacetrader.ContractUpdate{Agent: s.Agent, Contract: *contract}, nil)
+}
+
+func (s *Server) getSystems(w http.ResponseWriter, r *http.Request) {
+	symbols := []string{}
+	for symbol := range s.Systems {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	systems := []spacetrader.System{}
+	for _, symbol := range symbols {
+		systems = append(systems, *s.Systems[symbol])
+	}
+
+	page, meta, ok := paginate(w, r, systems)
+	if !ok {
+		return
+	}
+
+	writeData(w, http.StatusOK, page, &meta)
+}
+
+func (s *Server) getSystem(w http.ResponseWriter, r *http.Request) {
+	system, ok := s.Systems[r.PathValue("system")]
+	if !ok {
+		writeError(w, http.StatusNotFound, codeNotFound, fmt.Sprintf("System %s not found", r.PathValue("system")))
+		return
+	}
+
+	writeData(w, http.StatusOK, system, nil)
+}
+
+func (s *Server) getWaypoints(w http.ResponseWriter, r *http.Request) {
+	system, ok := s.Systems[r.PathValue("system")]
+	if !ok {
+		writeError(w, http.StatusNotFound, codeNotFound, fmt.Sprintf("System %s not found", r.PathValue("system")))
+		return
+	}
+
+	kind := spacetrader.WaypointType(r.URL.Query().Get("type"))
+	traits := r.URL.Query()["traits"]
+
+	waypoints := []spacetrader.Waypoint{}
+	for _, listed := range system.Waypoints {
+		waypoint := s.Waypoints[listed.Symbol]
+		if kind != "" && waypoint.Type != kind {
+			continue
+		}
+
+		// Waypoints have to carry every trait asked for
+		matches := true
+		for _, trait := range traits {
+			if !waypoint.HasTrait(spacetrader.WaypointTraitSymbol(trait)) {
+				matches = false
+			}
+		}
+		if matches {
+			waypoints = append(waypoints, *waypoint)
+		}
+	}
+
+	page, meta, ok := paginate(w, r, waypoints)
+	if !ok {
+		return
+	}
+
+	writeData(w, http.StatusOK, page, &meta)
+}
+
+func (s *Server) getWaypoint(w http.ResponseWriter, r *http.Request) {
+	waypoint, ok := s.Waypoints[r.PathValue("waypoint")]
+	if !ok || spacetrader.SystemSymbol(waypoint.Symbol) != r.PathValue("system") {
+		writeError(w, http.StatusNotFound, codeNotFound, fmt.Sprintf("Waypoint %s not found", r.PathValue("waypoint")))
+		return
+	}
+
+	writeData(w, http.StatusOK, waypoint, nil)
+}
+
+func (s *Server) getMarket(w http.ResponseWriter, r *http.Request) {
+	market, ok := s.Markets[r.PathValue("waypoint")]
+	if !ok || spacetrader.SystemSymbol(market.Symbol) != r.PathValue("system") {
+		writeError(w, http.StatusNotFound, codeNotFound, fmt.Sprintf("Market %s not found", r.PathValue("waypoint")))
+		return
+	}
+
+	// Prices are only posted to agents with a ship at the market
+	visible := *market
+	visible.TradeGoods = nil
+	visible.Transactions = nil
+	for _, ship := range s.Ships {
+		if ship.Nav.WaypointSymbol == market.Symbol && ship.Nav.Status != spacetrader.ShipNavStatusInTransit {
+			visible.TradeGoods = market.TradeGoods
+			visible.Transactions = market.Transactions
+			break
+		}
+	}
+
+	writeData(w, http.StatusOK, visible, nil)
+}
+
+func (s *Server) getJumpGate(w http.ResponseWriter, r *http.Request) {
+	waypoint, ok := s.Waypoints[r.PathValue("waypoint")]
+	if !ok || spacetrader.SystemSymbol(waypoint.Symbol) != r.PathValue("system") || waypoint.Type != spacetrader.WaypointTypeJumpGate {
+		writeError(w, http.StatusNotFound, codeNotFound, fmt.Sprintf("Jump gate %s not found", r.PathValue("waypoint")))
+		return
+	}
+
+	connections := slices.Clone(s.JumpGates[waypoint.Symbol])
+	if connections == nil {
+		connections = []string{}
+	}
+
+	writeData(w, http.StatusOK, spacetrader.JumpGate{Symbol: waypoint.Symbol, Connections: connections}, nil)
+}
+
+// findShip looks up the ship named in the path, answering with a 404 when we don't own it
+func (s *Server) findShip(w http.ResponseWriter, r *http.Request) (*spacetrader.Ship, bool) {
+	ship, ok := s.Ships[r.PathValue("ship")]
+	if !ok {
+		writeError(w, http.StatusNotFound, codeNotFound, fmt.Sprintf("Ship %s not found", r.PathValue("ship")))
+		return nil, false
+	}
+
+	return ship, true
+}
+
+// findContract looks up the contract named in the path
+func (s *Server) findContract(w http.ResponseWriter, r *http.Request) (*spacetrader.Contract, bool) {
+	for i := range s.Contracts {
+		if s.Contracts[i].Identifier == r.PathValue("contract") {
+			return &s.Contracts[i], true
+		}
+	}
+
+	writeError(w, http.StatusNotFound, codeNotFound, fmt.Sprintf("Contract %s not found", r.PathValue("contract")))
+	return nil, false
+}
+
+// paginate cuts a list down to the requested page the same way the API does, 10 to a page and never more than 20
+func paginate[T any](w http.ResponseWriter, r *http.Request, items []T) ([]T, spacetrader.Meta, bool) {
+	meta := spacetrader.Meta{Total: len(items), Page: 1, Limit: 10}
+
+	if page := r.URL.Query().Get("page"); page != "" {
+		number, err := strconv.Atoi(page)
+		if err != nil || number < 1 {
+			writeError(w, http.StatusBadRequest, codeBadRequest, "page must be a positive number")
+			return nil, meta, false
+		}
+		meta.Page = number
+	}
+
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		number, err := strconv.Atoi(limit)
+		if err != nil || number < 1 || number > 20 {
+			writeError(w, http.StatusBadRequest, codeBadRequest, "limit must be between 1 and 20")
+			return nil, meta, false
+		}
+		meta.Limit = number
+	}
+
+	start := min(len(items), (meta.Page-1)*meta.Limit)
+	end := min(len(items), start+meta.Limit)
+
+	return slices.Clone(items[start:end]), meta, true
+}
+
+func writeData(w http.ResponseWriter, status int, data any, meta *spacetrader.Meta) {
+	body := map[string]any{"data": data}
+	if meta != nil {
+		body["meta"] = meta
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
+
+// writeErrorData is writeError with the extra details some errors carry, a cooldown conflict says how long is left
+func writeErrorData(w http.ResponseWriter, status int, code int, message string, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{
+			"code":    code,
+			"message": message,
+			"data":    data,
+		},
+	})
+}
+
+func routePoint(waypoint *spacetrader.Waypoint) spacetrader.ShipDestination {
+	return spacetrader.ShipDestination{
+		Symbol:       waypoint.Symbol,
+		Type:         waypoint.Type,
+		SystemSymbol: spacetrader.SystemSymbol(waypoint.Symbol),
+		PosX:         waypoint.PosX,
+		PosY:         waypoint.PosY,
+	}
+}
@@ -0,0 +1,165 @@
+package fake_test
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"example.com/spacetrader"
+	"example.com/spacetrader/fake"
+)
+
+// start points the spacetrader package at a fresh fake universe whose clock only moves when
+// the test moves it
+func start(t *testing.T) (*fake.Server, *time.Time) {
+	t.Helper()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	universe := fake.New()
+	universe.Now = func() time.Time { return now }
+
+	server := httptest.NewServer(universe)
+	baseURL, logger := spacetrader.BaseURL, spacetrader.Logger
+	spacetrader.BaseURL = server.URL
+	spacetrader.Logger = log.New(io.Discard, "", 0)
+	t.Cleanup(func() {
+		server.Close()
+		spacetrader.BaseURL, spacetrader.Logger = baseURL, logger
+	})
+
+	return universe, &now
+}
+
+func TestOrbitAndDock(t *testing.T) {
+	universe, _ := start(t)
+
+	if _, err := spacetrader.LaunchToOrbit(fake.Token, "FAKE-AGENT-1"); err != nil {
+		t.Fatalf("LaunchToOrbit: %v", err)
+	}
+	if ship, _ := universe.Ship("FAKE-AGENT-1"); ship.Nav.Status != spacetrader.ShipNavStatusInOrbit {
+		t.Errorf("ship is %s after going to orbit, want IN_ORBIT", ship.Nav.Status)
+	}
+
+	if _, err := spacetrader.DockShip(fake.Token, "FAKE-AGENT-1"); err != nil {
+		t.Fatalf("DockShip: %v", err)
+	}
+	if ship, _ := universe.Ship("FAKE-AGENT-1"); ship.Nav.Status != spacetrader.ShipNavStatusDocked {
+		t.Errorf("ship is %s after docking, want DOCKED", ship.Nav.Status)
+	}
+}
+
+func TestNavigate(t *testing.T) {
+	_, now := start(t)
+
+	if _, err := spacetrader.LaunchToOrbit(fake.Token, "FAKE-AGENT-1"); err != nil {
+		t.Fatalf("LaunchToOrbit: %v", err)
+	}
+	if _, err := spacetrader.NavigateShip(fake.Token, "FAKE-AGENT-1", "X1-TEST-B7"); err != nil {
+		t.Fatalf("NavigateShip: %v", err)
+	}
+
+	// The asteroids are 50 out, cruising there at speed 30 takes 50 fuel and 50*25/30+15 seconds
+	ship, err := spacetrader.GetShip(fake.Token, "FAKE-AGENT-1")
+	if err != nil {
+		t.Fatalf("GetShip: %v", err)
+	}
+	if ship.Nav.Status != spacetrader.ShipNavStatusInTransit || ship.Nav.Route.Destination.Symbol != "X1-TEST-B7" {
+		t.Errorf("ship is %s to %s, want IN_TRANSIT to X1-TEST-B7", ship.Nav.Status, ship.Nav.Route.Destination.Symbol)
+	}
+	if ship.Fuel.Current != 350 {
+		t.Errorf("ship has %d fuel left, want 350", ship.Fuel.Current)
+	}
+	arrival, err := time.Parse(time.RFC3339Nano, ship.Nav.Route.Arrival)
+	if err != nil {
+		t.Fatalf("arrival %q: %v", ship.Nav.Route.Arrival, err)
+	}
+	if want := now.Add(57 * time.Second); !arrival.Equal(want) {
+		t.Errorf("ship arrives at %s, want %s", arrival, want)
+	}
+
+	// Nothing can be done until it gets there, and then it's left in orbit
+	var refused *spacetrader.APIError
+	if _, err := spacetrader.DockShip(fake.Token, "FAKE-AGENT-1"); !errors.As(err, &refused) || refused.Code != 4214 {
+		t.Errorf("docking in transit got %v, want a 4214 in transit error", err)
+	}
+
+	*now = arrival
+	ship, err = spacetrader.GetShip(fake.Token, "FAKE-AGENT-1")
+	if err != nil {
+		t.Fatalf("GetShip: %v", err)
+	}
+	if ship.Nav.Status != spacetrader.ShipNavStatusInOrbit || ship.Nav.WaypointSymbol != "X1-TEST-B7" {
+		t.Errorf("ship is %s at %s, want IN_ORBIT at X1-TEST-B7", ship.Nav.Status, ship.Nav.WaypointSymbol)
+	}
+}
+
+func TestRefuel(t *testing.T) {
+	universe, _ := start(t)
+	universe.Ships["FAKE-AGENT-1"].Fuel.Current = 150
+
+	refuel, err := spacetrader.RefuelShip(fake.Token, "FAKE-AGENT-1", 0)
+	if err != nil {
+		t.Fatalf("RefuelShip: %v", err)
+	}
+
+	// 250 units is two and a half market units, and the half still costs a whole one
+	if refuel.Fuel.Current != 400 {
+		t.Errorf("tank holds %d after filling up, want 400", refuel.Fuel.Current)
+	}
+	if refuel.Transaction.Units != 250 || refuel.Transaction.TotalPrice != 3*72 {
+		t.Errorf("bought %d fuel for %d, want 250 for %d", refuel.Transaction.Units, refuel.Transaction.TotalPrice, 3*72)
+	}
+	if refuel.Agent.Credits != 175000-3*72 {
+		t.Errorf("agent has %d credits, want %d", refuel.Agent.Credits, 175000-3*72)
+	}
+}
+
+func TestExtractAndDeliver(t *testing.T) {
+	universe, _ := start(t)
+	universe.Ships["FAKE-AGENT-1"].Nav.WaypointSymbol = "X1-TEST-B7"
+	universe.Ships["FAKE-AGENT-1"].Nav.Status = spacetrader.ShipNavStatusInOrbit
+
+	extract, err := spacetrader.ExtractResources(fake.Token, "FAKE-AGENT-1", nil)
+	if err != nil {
+		t.Fatalf("ExtractResources: %v", err)
+	}
+
+	// A Mining Laser II digs up 5 units at a time, iron first
+	yield := extract.Extraction.Yield
+	if yield.Symbol != spacetrader.TradeIronOre || yield.Units != 5 {
+		t.Errorf("dug up %d %s, want 5 IRON_ORE", yield.Units, yield.Symbol)
+	}
+	if extract.Cargo.Units != 5 {
+		t.Errorf("hold has %d units, want 5", extract.Cargo.Units)
+	}
+	if extract.Cooldown.RemainingSeconds <= 0 {
+		t.Errorf("laser has %d seconds of cooldown, want some", extract.Cooldown.RemainingSeconds)
+	}
+
+	// Headquarters wants iron ore for the open contract
+	universe.Ships["FAKE-AGENT-1"].Nav.WaypointSymbol = "X1-TEST-A1"
+	universe.Ships["FAKE-AGENT-1"].Nav.Status = spacetrader.ShipNavStatusDocked
+
+	if _, err := spacetrader.AcceptContract(fake.Token, "fake-contract-1"); err != nil {
+		t.Fatalf("AcceptContract: %v", err)
+	}
+	update, err := spacetrader.DeliverContract(fake.Token, "fake-contract-1", "FAKE-AGENT-1", spacetrader.TradeIronOre, 5)
+	if err != nil {
+		t.Fatalf("DeliverContract: %v", err)
+	}
+	if delivered := update.Contract.Terms.Deliver[0].UnitsFulfilled; delivered != 5 {
+		t.Errorf("contract has %d units delivered, want 5", delivered)
+	}
+	if update.Cargo.Units != 0 {
+		t.Errorf("hold has %d units left, want 0", update.Cargo.Units)
+	}
+
+	// There's nothing left to hand over
+	var refused *spacetrader.APIError
+	if _, err := spacetrader.DeliverContract(fake.Token, "fake-contract-1", "FAKE-AGENT-1", spacetrader.TradeIronOre, 5); !errors.As(err, &refused) || refused.Code != 4219 {
+		t.Errorf("delivering an empty hold got %v, want a 4219 not enough cargo error", err)
+	}
+}
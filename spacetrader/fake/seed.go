@@ -0,0 +1,175 @@
+package fake
+
+import (
+	"time"
+
+	"example.com/spacetrader"
+)
+
+func good(symbol spacetrader.TradeSymbol) spacetrader.MarketGood {
+	return spacetrader.MarketGood{Symbol: symbol, Name: string(symbol)}
+}
+
+func tradeGood(symbol spacetrader.TradeSymbol, kind spacetrader.MarketTradeGoodType, supply spacetrader.MarketTradeGoodSupply, purchase int, sell int) spacetrader.MarketTradeGood {
+	return spacetrader.MarketTradeGood{Symbol: symbol, Type: kind, TradeVolume: 100, Supply: supply, Activity: "STRONG", PurchasePrice: purchase, SellPrice: sell}
+}
+
+func trait(symbol spacetrader.WaypointTraitSymbol, name string) spacetrader.Trait {
+	return spacetrader.Trait{Symbol: symbol, Name: name, Description: name}
+}
+
+// seed fills the universe with three systems and a fleet to play with. Headquarters' gate is
+// still being built, so getting out of X1-TEST means warping next door and jumping from there.
+func (s *Server) seed() {
+	waypoints := []spacetrader.Waypoint{
+		{Symbol: "X1-TEST-A1", Type: spacetrader.WaypointTypePlanet, PosX: 0, PosY: 0, Traits: []spacetrader.Trait{
+			trait(spacetrader.WaypointTraitMarketplace, "Marketplace"),
+			trait(spacetrader.WaypointTraitShipyard, "Shipyard"),
+			trait(spacetrader.WaypointTraitTemperate, "Temperate"),
+		}},
+		{Symbol: "X1-TEST-A2", Type: spacetrader.WaypointTypeMoon, PosX: 3, PosY: 4, Traits: []spacetrader.Trait{
+			trait(spacetrader.WaypointTraitBarren, "Barren"),
+		}},
+		{Symbol: "X1-TEST-B7", Type: spacetrader.WaypointTypeAsteroidField, PosX: 40, PosY: -30, Traits: []spacetrader.Trait{
+			trait(spacetrader.WaypointTraitCommonMetalDeposits, "Common Metal Deposits"),
+			trait(spacetrader.WaypointTraitMineralDeposits, "Mineral Deposits"),
+		}},
+		{Symbol: "X1-TEST-C3", Type: spacetrader.WaypointTypeGasGiant, PosX: -120, PosY: 90, Traits: []spacetrader.Trait{
+			trait(spacetrader.WaypointTraitStrongMagnetosphere, "Strong Magnetosphere"),
+		}},
+		{Symbol: "X1-TEST-D4", Type: spacetrader.WaypointTypeOrbitalStation, PosX: 200, PosY: 10, Traits: []spacetrader.Trait{
+			trait(spacetrader.WaypointTraitMarketplace, "Marketplace"),
+			trait(spacetrader.WaypointTraitTradingHub, "Trading Hub"),
+		}},
+		{Symbol: "X1-TEST-I9", Type: spacetrader.WaypointTypeJumpGate, PosX: -300, PosY: -250, IsUnderConstruction: true},
+		{Symbol: "X1-NEAR-A1", Type: spacetrader.WaypointTypePlanet, PosX: 5, PosY: 5, Traits: []spacetrader.Trait{
+			trait(spacetrader.WaypointTraitMarketplace, "Marketplace"),
+		}},
+		{Symbol: "X1-NEAR-I1", Type: spacetrader.WaypointTypeJumpGate, PosX: 60, PosY: 20},
+		{Symbol: "X1-FAR-B2", Type: spacetrader.WaypointTypePlanet, PosX: -40, PosY: 70, Traits: []spacetrader.Trait{
+			trait(spacetrader.WaypointTraitFrozen, "Frozen"),
+		}},
+		{Symbol: "X1-FAR-I4", Type: spacetrader.WaypointTypeJumpGate, PosX: 10, PosY: -90},
+	}
+
+	s.Systems["X1-TEST"] = &spacetrader.System{Symbol: "X1-TEST", SectorSymbol: "X1", Type: "YELLOW_STAR", PosX: 0, PosY: 0}
+	s.Systems["X1-NEAR"] = &spacetrader.System{Symbol: "X1-NEAR", SectorSymbol: "X1", Type: "RED_STAR", PosX: 240, PosY: -180}
+	s.Systems["X1-FAR"] = &spacetrader.System{Symbol: "X1-FAR", SectorSymbol: "X1", Type: "BLUE_STAR", PosX: 2600, PosY: -1900}
+	for _, waypoint := range waypoints {
+		waypoint := waypoint
+		s.Waypoints[waypoint.Symbol] = &waypoint
+		system := s.Systems[spacetrader.SystemSymbol(waypoint.Symbol)]
+		system.Waypoints = append(system.Waypoints, spacetrader.Waypoint{Symbol: waypoint.Symbol, Type: waypoint.Type, PosX: waypoint.PosX, PosY: waypoint.PosY})
+	}
+
+	s.JumpGates["X1-TEST-I9"] = []string{"X1-NEAR-I1"}
+	s.JumpGates["X1-NEAR-I1"] = []string{"X1-TEST-I9", "X1-FAR-I4"}
+	s.JumpGates["X1-FAR-I4"] = []string{"X1-NEAR-I1"}
+
+	// Only the ores sell anywhere, the rest is for the mining loop to throw away
+	s.Deposits["X1-TEST-B7"] = []spacetrader.TradeSymbol{spacetrader.TradeIronOre, spacetrader.TradeQuartzSand, spacetrader.TradeCopperOre, spacetrader.TradeIceWater, spacetrader.TradeIronOre}
+
+	// Fuel is sold at headquarters, the trading hub and next door, so there's always somewhere to fill up
+	s.Markets["X1-TEST-A1"] = &spacetrader.Market{
+		Symbol:   "X1-TEST-A1",
+		Imports:  []spacetrader.MarketGood{good(spacetrader.TradeIronOre), good(spacetrader.TradeCopperOre)},
+		Exports:  []spacetrader.MarketGood{good(spacetrader.TradeFood)},
+		Exchange: []spacetrader.MarketGood{good(spacetrader.TradeFuel)},
+		TradeGoods: []spacetrader.MarketTradeGood{
+			tradeGood(spacetrader.TradeIronOre, "IMPORT", "SCARCE", 62, 58),
+			tradeGood(spacetrader.TradeCopperOre, "IMPORT", "LIMITED", 71, 66),
+			tradeGood(spacetrader.TradeFood, "EXPORT", "ABUNDANT", 30, 26),
+			tradeGood(spacetrader.TradeFuel, "EXCHANGE", "MODERATE", 72, 68),
+		},
+	}
+	s.Markets["X1-TEST-D4"] = &spacetrader.Market{
+		Symbol:   "X1-TEST-D4",
+		Imports:  []spacetrader.MarketGood{good(spacetrader.TradeFood)},
+		Exports:  []spacetrader.MarketGood{good(spacetrader.TradeFuel)},
+		Exchange: []spacetrader.MarketGood{good(spacetrader.TradeIronOre)},
+		TradeGoods: []spacetrader.MarketTradeGood{
+			tradeGood(spacetrader.TradeFood, "IMPORT", "SCARCE", 48, 44),
+			tradeGood(spacetrader.TradeFuel, "EXPORT", "HIGH", 58, 54),
+			tradeGood(spacetrader.TradeIronOre, "EXCHANGE", "MODERATE", 45, 41),
+		},
+	}
+	s.Markets["X1-NEAR-A1"] = &spacetrader.Market{
+		Symbol:   "X1-NEAR-A1",
+		Exchange: []spacetrader.MarketGood{good(spacetrader.TradeFuel)},
+		TradeGoods: []spacetrader.MarketTradeGood{
+			tradeGood(spacetrader.TradeFuel, "EXCHANGE", "MODERATE", 80, 75),
+		},
+	}
+
+	s.Agent = spacetrader.Agent{
+		AccountId:       "fake-account",
+		Symbol:          "FAKE-AGENT",
+		Headquarters:    "X1-TEST-A1",
+		Credits:         175000,
+		StartingFaction: "COSMIC",
+	}
+
+	headquarters := routePoint(s.Waypoints["X1-TEST-A1"])
+	asteroids := routePoint(s.Waypoints["X1-TEST-B7"])
+	s.Ships["FAKE-AGENT-1"] = &spacetrader.Ship{
+		Symbol:       "FAKE-AGENT-1",
+		Registration: spacetrader.ShipRegistration{Name: "FAKE-AGENT-1", FactionSymbol: "COSMIC", Role: spacetrader.ShipRoleCommand},
+		Nav: spacetrader.ShipNav{
+			Status:         spacetrader.ShipNavStatusDocked,
+			FlightMode:     spacetrader.FlightModeCruise,
+			SystemSymbol:   "X1-TEST",
+			WaypointSymbol: headquarters.Symbol,
+			Route:          spacetrader.ShipRoute{Origin: headquarters, Destination: headquarters},
+		},
+		Crew:    spacetrader.ShipCrew{Current: 57, Required: 57, Capacity: 80, Rotation: "STRICT", Morale: 100},
+		Frame:   spacetrader.ShipFrame{Symbol: spacetrader.TradeFrameFrigate, Name: "Frigate", Condition: 1, Integrity: 1, ModuleSlots: 8, MountingPoints: 5, FuelCapacity: 400, Requirements: spacetrader.ShipRequirements{Power: 8, Crew: 25}},
+		Reactor: spacetrader.ShipReactor{Symbol: spacetrader.TradeReactorFissionI, Name: "Fission Reactor I", Condition: 1, Integrity: 1, PowerOutput: 31, Requirements: spacetrader.ShipRequirements{Crew: 8}},
+		Engine:  spacetrader.ShipEngine{Symbol: spacetrader.TradeEngineIonDriveII, Name: "Ion Drive II", Condition: 1, Integrity: 1, Speed: 30, Requirements: spacetrader.ShipRequirements{Power: 6, Crew: 8}},
+		Modules: []spacetrader.ShipModule{
+			{Symbol: spacetrader.TradeModuleCargoHoldII, Name: "Expanded Cargo Hold", Capacity: 40, Requirements: spacetrader.ShipRequirements{Power: 2, Crew: 2, Slots: 2}},
+			{Symbol: spacetrader.TradeModuleCrewQuartersI, Name: "Crew Quarters", Capacity: 40, Requirements: spacetrader.ShipRequirements{Power: 1, Crew: 2, Slots: 1}},
+			{Symbol: spacetrader.TradeModuleWarpDriveI, Name: "Warp Drive I", Range: 2000, Requirements: spacetrader.ShipRequirements{Power: 3, Crew: 2, Slots: 1}},
+		},
+		Mounts: []spacetrader.ShipMount{
+			{Symbol: spacetrader.TradeMountSensorArrayII, Name: "Sensor Array II", Strength: 4, Requirements: spacetrader.ShipRequirements{Power: 2, Crew: 2}},
+			{Symbol: spacetrader.TradeMountMiningLaserII, Name: "Mining Laser II", Strength: 5, Requirements: spacetrader.ShipRequirements{Power: 2, Crew: 2}},
+			{Symbol: spacetrader.TradeMountSurveyorI, Name: "Surveyor I", Strength: 1, Requirements: spacetrader.ShipRequirements{Power: 1, Crew: 2}},
+		},
+		Cargo: spacetrader.ShipCargo{Capacity: 40, Units: 0, Inventory: []spacetrader.Cargo{}},
+		Fuel:  spacetrader.ShipFuel{Current: 400, Capacity: 400},
+	}
+	s.Ships["FAKE-AGENT-2"] = &spacetrader.Ship{
+		Symbol:       "FAKE-AGENT-2",
+		Registration: spacetrader.ShipRegistration{Name: "FAKE-AGENT-2", FactionSymbol: "COSMIC", Role: spacetrader.ShipRoleSatellite},
+		Nav: spacetrader.ShipNav{
+			Status:         spacetrader.ShipNavStatusInOrbit,
+			FlightMode:     spacetrader.FlightModeCruise,
+			SystemSymbol:   "X1-TEST",
+			WaypointSymbol: asteroids.Symbol,
+			Route:          spacetrader.ShipRoute{Origin: asteroids, Destination: asteroids},
+		},
+		Frame:   spacetrader.ShipFrame{Symbol: spacetrader.TradeFrameProbe, Name: "Probe", Condition: 1, Integrity: 1},
+		Reactor: spacetrader.ShipReactor{Symbol: spacetrader.TradeReactorSolarI, Name: "Solar Reactor I", Condition: 1, Integrity: 1, PowerOutput: 3},
+		Engine:  spacetrader.ShipEngine{Symbol: spacetrader.TradeEngineImpulseDriveI, Name: "Impulse Drive I", Condition: 1, Integrity: 1, Speed: 9, Requirements: spacetrader.ShipRequirements{Power: 1}},
+		Modules: []spacetrader.ShipModule{},
+		Mounts:  []spacetrader.ShipMount{},
+		Cargo:   spacetrader.ShipCargo{Inventory: []spacetrader.Cargo{}},
+	}
+
+	s.Contracts = []spacetrader.Contract{
+		{
+			Identifier:    "fake-contract-1",
+			FactionSymbol: "COSMIC",
+			Type:          "PROCUREMENT",
+			Terms: spacetrader.ContractTerms{
+				Deadline: time.Now().Add(7 * 24 * time.Hour).UTC().Format(time.RFC3339Nano),
+				Payment:  spacetrader.ContractPayment{OnAccepted: 2000, OnFulfilled: 12000},
+				Deliver: []spacetrader.ContractDelivery{
+					{TradeSymbol: spacetrader.TradeIronOre, DestinationSymbol: "X1-TEST-A1", UnitsRequired: 60},
+				},
+			},
+			Expiration:       time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339Nano),
+			DeadlineToAccept: time.Now().Add(24 * time.Hour).UTC().Format(time.RFC3339Nano),
+		},
+	}
+}
@@ -0,0 +1,458 @@
+package fleet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"example.com/spacetrader"
+	"example.com/spacetrader/history"
+	"example.com/spacetrader/mining"
+	"example.com/spacetrader/queue"
+	"example.com/spacetrader/trade"
+)
+
+// Every behaviour loops until its context is cancelled, an error it returns gets it restarted
+// unless it's errFinished or marked queue.Permanent
+
+// errFinished ends a behaviour for good, without it counting as a failure
+var errFinished = errors.New("finished")
+
+// idle leaves the ship be
+func (s *Scheduler) idle(ctx context.Context, w *worker) error {
+	s.report(w, "Waiting for orders")
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+// mine hands the ship to the miner, working the asteroid nearest to it
+func (s *Scheduler) mine(ctx context.Context, w *worker) error {
+	ship, err := s.api.GetShip(w.status.ShipSymbol)
+	if err != nil {
+		return err
+	}
+
+	system, err := s.api.GetSystem(ship.Nav.SystemSymbol)
+	if err != nil {
+		return err
+	}
+
+	here := position(system.Waypoints, ship.Nav.WaypointSymbol)
+	asteroid, nearest := "", math.Inf(1)
+	for _, waypoint := range system.Waypoints {
+		if !spacetrader.Minable(waypoint.Type) {
+			continue
+		}
+		if distance := spacetrader.Distance(here.PosX, here.PosY, waypoint.PosX, waypoint.PosY); distance < nearest {
+			asteroid, nearest = waypoint.Symbol, distance
+		}
+	}
+	if asteroid == "" {
+		return fmt.Errorf("there's nothing to mine in %s", system.Symbol)
+	}
+
+	err = s.miner.Mine(ctx, ship.Symbol, asteroid)
+
+	// Stopped from the ship page rather than failed, the ship's done until it's given something else
+	if errors.Is(err, mining.ErrStopped) {
+		return errFinished
+	}
+
+	return err
+}
+
+// trade runs the best buy-here-sell-there deal on record, over and over
+func (s *Scheduler) trade(ctx context.Context, w *worker) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		ship, waypoints, fuelStations, err := s.surroundings(w.status.ShipSymbol)
+		if err != nil {
+			return err
+		}
+
+		// Whatever's left from an interrupted run gets sold before buying anything else
+		if ship.Cargo.Units > 0 {
+			sold, err := s.unload(ctx, w, ship, waypoints, fuelStations)
+			if err != nil {
+				return err
+			}
+			if sold {
+				continue
+			}
+		}
+
+		agent, err := s.api.ShowAgent()
+		if err != nil {
+			return err
+		}
+
+		opportunities := trade.Find(ship, waypoints, s.prices, agent.Credits, s.Now())
+		if len(opportunities) == 0 {
+			s.report(w, "No profitable runs on record, waiting for fresher prices")
+			if err := s.sleep(ctx, s.Idle); err != nil {
+				return err
+			}
+			continue
+		}
+		best := opportunities[0]
+
+		s.report(w, "Flying to %s to buy %d %s", best.Buy.Waypoint, best.Units, best.Good)
+		if err := s.visit(ctx, ship, waypoints, fuelStations, best.Buy.Waypoint); err != nil {
+			return err
+		}
+
+		s.report(w, "Buying %d %s at %s", best.Units, best.Good, best.Buy.Waypoint)
+		bought, err := s.buy(ship.Symbol, best.Good, best.Units)
+		if err != nil {
+			return err
+		}
+		if bought == 0 {
+			continue
+		}
+
+		if ship, err = s.api.GetShip(ship.Symbol); err != nil {
+			return err
+		}
+
+		s.report(w, "Flying to %s to sell %d %s", best.Sell.Waypoint, bought, best.Good)
+		if err := s.visit(ctx, ship, waypoints, fuelStations, best.Sell.Waypoint); err != nil {
+			return err
+		}
+
+		s.report(w, "Selling %d %s at %s", bought, best.Good, best.Sell.Waypoint)
+		if _, err := s.sell(ship.Symbol, best.Good, bought); err != nil {
+			return err
+		}
+	}
+}
+
+// unload takes the hold to whichever market on record pays best for the most valuable thing
+// in it and sells all it can there. It's false if nowhere on record buys any of it.
+func (s *Scheduler) unload(ctx context.Context, w *worker, ship spacetrader.Ship, waypoints []spacetrader.Waypoint, fuelStations map[string]int) (bool, error) {
+	var best history.Observation
+	bestValue := 0
+	for _, waypoint := range waypoints {
+		for _, cargo := range ship.Cargo.Inventory {
+			observation, ok := s.prices.Latest(waypoint.Symbol, cargo.Symbol)
+			if ok && cargo.Units*observation.SellPrice > bestValue {
+				best, bestValue = observation, cargo.Units*observation.SellPrice
+			}
+		}
+	}
+	if bestValue == 0 {
+		return false, nil
+	}
+
+	s.report(w, "Flying to %s to unload the hold", best.Waypoint)
+	if err := s.visit(ctx, ship, waypoints, fuelStations, best.Waypoint); err != nil {
+		return false, err
+	}
+
+	market, err := s.api.GetMarket(ship.Nav.SystemSymbol, best.Waypoint)
+	if err != nil {
+		return false, err
+	}
+
+	sold := 0
+	for _, cargo := range ship.Cargo.Inventory {
+		if _, ok := market.TradeGood(cargo.Symbol); !ok {
+			continue
+		}
+
+		s.report(w, "Selling %d %s at %s", cargo.Units, cargo.Symbol, best.Waypoint)
+		units, err := s.sell(ship.Symbol, cargo.Symbol, cargo.Units)
+		if err != nil {
+			return false, err
+		}
+		sold += units
+	}
+
+	return sold > 0, nil
+}
+
+// probeMarkets keeps going back to whichever market in the system has gone longest without
+// a visit, so there are always fresh prices to trade on
+func (s *Scheduler) probeMarkets(ctx context.Context, w *worker) error {
+	for {
+		ship, waypoints, fuelStations, err := s.surroundings(w.status.ShipSymbol)
+		if err != nil {
+			return err
+		}
+
+		here := position(waypoints, ship.Nav.WaypointSymbol)
+		stalest, stalestAge, stalestDistance := "", -1.0, 0.0
+		for _, waypoint := range waypoints {
+			if !waypoint.HasTrait(spacetrader.WaypointTraitMarketplace) {
+				continue
+			}
+
+			// Never seen at all beats anything, then the oldest prices, then the nearest
+			age := math.Inf(1)
+			if seen, ok := s.prices.Age(waypoint.Symbol, s.Now()); ok {
+				age = seen.Seconds()
+			}
+			distance := spacetrader.Distance(here.PosX, here.PosY, waypoint.PosX, waypoint.PosY)
+			if age > stalestAge || (age == stalestAge && distance < stalestDistance) {
+				stalest, stalestAge, stalestDistance = waypoint.Symbol, age, distance
+			}
+		}
+		if stalest == "" {
+			return fmt.Errorf("there are no markets in %s", ship.Nav.SystemSymbol)
+		}
+
+		if wait := s.Refresh.Seconds() - stalestAge; wait > 0 {
+			s.report(w, "Every market is up to date, %s is next", stalest)
+			if err := s.sleep(ctx, time.Duration(wait*float64(time.Second))); err != nil {
+				return err
+			}
+			continue
+		}
+
+		s.report(w, "Flying to %s for its prices", stalest)
+		if err := s.visit(ctx, ship, waypoints, fuelStations, stalest); err != nil {
+			return err
+		}
+
+		// Fetching the market is all it takes, the history records whatever comes back
+		if _, err := s.api.GetMarket(ship.Nav.SystemSymbol, stalest); err != nil {
+			return err
+		}
+	}
+}
+
+// haulContracts works through contracts one at a time: it takes on the most profitable one it
+// can finish, buys what it needs at the cheapest market on record, delivers it and gets paid
+func (s *Scheduler) haulContracts(ctx context.Context, w *worker) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		ship, waypoints, fuelStations, err := s.surroundings(w.status.ShipSymbol)
+		if err != nil {
+			return err
+		}
+
+		contract, ok, err := s.pickContract(w, ship, waypoints)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			s.report(w, "No contract worth taking, waiting for one")
+			if err := s.sleep(ctx, s.Idle); err != nil {
+				return err
+			}
+			continue
+		}
+
+		delivery, owed := spacetrader.ContractDelivery{}, 0
+		for _, candidate := range contract.Terms.Deliver {
+			if left := candidate.UnitsRequired - candidate.UnitsFulfilled; left > 0 {
+				delivery, owed = candidate, left
+				break
+			}
+		}
+
+		// Everything's been delivered, collect
+		if owed == 0 {
+			s.report(w, "Fulfilling contract %s", contract.Identifier)
+			if _, err := s.api.FulfillContract(contract.Identifier); err != nil {
+				return fmt.Errorf("fulfilling %s: %w", contract.Identifier, err)
+			}
+			continue
+		}
+
+		held := 0
+		for _, cargo := range ship.Cargo.Inventory {
+			if cargo.Symbol == delivery.TradeSymbol {
+				held = cargo.Units
+			}
+		}
+
+		if held == 0 {
+			space := ship.Cargo.Capacity - ship.Cargo.Units
+			if space == 0 {
+				return fmt.Errorf("%s's hold is full of other cargo", ship.Symbol)
+			}
+
+			estimate := trade.EvaluateContract(contract, ship, waypoints, s.prices, s.Now())
+			source := history.Observation{}
+			for _, estimated := range estimate.Deliveries {
+				if estimated.TradeSymbol == delivery.TradeSymbol && estimated.DestinationSymbol == delivery.DestinationSymbol && estimated.Found {
+					source = estimated.Source
+				}
+			}
+			if source.Waypoint == "" {
+				return fmt.Errorf("no market on record sells %s", delivery.TradeSymbol)
+			}
+
+			s.report(w, "Flying to %s to buy %s for contract %s", source.Waypoint, delivery.TradeSymbol, contract.Identifier)
+			if err := s.visit(ctx, ship, waypoints, fuelStations, source.Waypoint); err != nil {
+				return err
+			}
+
+			s.report(w, "Buying %d %s at %s", min(owed, space), delivery.TradeSymbol, source.Waypoint)
+			if held, err = s.buy(ship.Symbol, delivery.TradeSymbol, min(owed, space)); err != nil {
+				return err
+			}
+			if held == 0 {
+				return fmt.Errorf("couldn't afford any %s", delivery.TradeSymbol)
+			}
+
+			if ship, err = s.api.GetShip(ship.Symbol); err != nil {
+				return err
+			}
+		}
+
+		s.report(w, "Flying to %s to deliver %d %s", delivery.DestinationSymbol, min(held, owed), delivery.TradeSymbol)
+		if err := s.visit(ctx, ship, waypoints, fuelStations, delivery.DestinationSymbol); err != nil {
+			return err
+		}
+
+		if _, err := s.api.DeliverContract(contract.Identifier, ship.Symbol, delivery.TradeSymbol, min(held, owed)); err != nil {
+			return fmt.Errorf("delivering %s: %w", delivery.TradeSymbol, err)
+		}
+	}
+}
+
+// pickContract is the accepted contract still being worked on, or failing that the most
+// profitable open one the ship could finish in time, which gets accepted. Expired ones are passed
+// over, and an accepted one the ship can't finish stops the hauler for good.
+func (s *Scheduler) pickContract(w *worker, ship spacetrader.Ship, waypoints []spacetrader.Waypoint) (spacetrader.Contract, bool, error) {
+	contracts, err := s.api.GetContracts()
+	if err != nil {
+		return spacetrader.Contract{}, false, err
+	}
+
+	var best spacetrader.Contract
+	bestProfit := 0
+	for _, contract := range contracts {
+		if contract.Fulfilled {
+			continue
+		}
+		// Deliveries past the deadline just fail, so an expired contract is as good as gone
+		if deadline, err := time.Parse(time.RFC3339Nano, contract.Terms.Deadline); err == nil && !s.Now().Before(deadline) {
+			continue
+		}
+		if contract.Accepted {
+			// One that can't be done, like a delivery to another system, would only fail over
+			// and over, so it's left for someone to look at
+			if estimate := trade.EvaluateContract(contract, ship, waypoints, s.prices, s.Now()); !estimate.Feasible() {
+				return spacetrader.Contract{}, false, queue.Permanent(fmt.Errorf("contract %s can't be finished: %s", contract.Identifier, strings.Join(estimate.Problems, ", ")))
+			}
+			return contract, true, nil
+		}
+
+		estimate := trade.EvaluateContract(contract, ship, waypoints, s.prices, s.Now())
+		if estimate.Feasible() && estimate.Profit > bestProfit {
+			best, bestProfit = contract, estimate.Profit
+		}
+	}
+	if bestProfit == 0 {
+		return spacetrader.Contract{}, false, nil
+	}
+
+	s.report(w, "Accepting contract %s, worth about %d credits", best.Identifier, bestProfit)
+	accepted, err := s.api.AcceptContract(best.Identifier)
+	if err != nil {
+		return spacetrader.Contract{}, false, fmt.Errorf("accepting %s: %w", best.Identifier, err)
+	}
+
+	return accepted.Contract, true, nil
+}
+
+// surroundings looks up the ship along with the map of its system and where fuel's sold in it
+func (s *Scheduler) surroundings(shipSymbol string) (spacetrader.Ship, []spacetrader.Waypoint, map[string]int, error) {
+	ship, err := s.api.GetShip(shipSymbol)
+	if err != nil {
+		return spacetrader.Ship{}, nil, nil, err
+	}
+
+	// The system listing leaves traits out, the full waypoints are needed to find the markets
+	waypoints, err := spacetrader.AllWaypoints(s.api, ship.Nav.SystemSymbol, spacetrader.WaypointQuery{})
+	if err != nil {
+		return spacetrader.Ship{}, nil, nil, err
+	}
+
+	fuelStations, err := spacetrader.FindFuelStations(s.api, waypoints)
+	if err != nil {
+		return spacetrader.Ship{}, nil, nil, err
+	}
+
+	return ship, waypoints, fuelStations, nil
+}
+
+// visit flies the ship to a waypoint and docks there
+func (s *Scheduler) visit(ctx context.Context, ship spacetrader.Ship, waypoints []spacetrader.Waypoint, fuelStations map[string]int, destination string) error {
+	if err := s.pilot.Fly(ctx, ship, waypoints, fuelStations, destination); err != nil {
+		return err
+	}
+
+	// The autopilot docks on arrival, but a ship that was already there may be sitting in orbit
+	_, err := s.api.DockShip(ship.Symbol)
+
+	return err
+}
+
+// buy purchases up to units of a good at the market the ship is docked at, a trade volume at
+// a time, stopping early when the credits run out. It says how many it got.
+func (s *Scheduler) buy(shipSymbol string, good spacetrader.TradeSymbol, units int) (int, error) {
+	return s.exchange(shipSymbol, good, units, s.api.PurchaseCargo)
+}
+
+// sell is buy the other way round, stopping early if the market won't take any more
+func (s *Scheduler) sell(shipSymbol string, good spacetrader.TradeSymbol, units int) (int, error) {
+	return s.exchange(shipSymbol, good, units, s.api.SellCargo)
+}
+
+func (s *Scheduler) exchange(shipSymbol string, good spacetrader.TradeSymbol, units int, trade func(string, spacetrader.TradeSymbol, int) (spacetrader.CargoTrade, error)) (int, error) {
+	ship, err := s.api.GetShip(shipSymbol)
+	if err != nil {
+		return 0, err
+	}
+
+	market, err := s.api.GetMarket(ship.Nav.SystemSymbol, ship.Nav.WaypointSymbol)
+	if err != nil {
+		return 0, err
+	}
+
+	listed, ok := market.TradeGood(good)
+	if !ok {
+		return 0, fmt.Errorf("%s doesn't trade %s", market.Symbol, good)
+	}
+
+	done := 0
+	for done < units {
+		lot := min(units-done, max(1, listed.TradeVolume))
+
+		_, err := trade(shipSymbol, good, lot)
+		if spacetrader.HasCode(err, spacetrader.ErrCodeNotEnoughCredits, spacetrader.ErrCodeTradeNotSold, spacetrader.ErrCodeCargoFull) {
+			break
+		}
+		if err != nil {
+			return done, fmt.Errorf("trading %s at %s: %w", good, market.Symbol, err)
+		}
+
+		done += lot
+	}
+
+	return done, nil
+}
+
+// position finds a waypoint in the system listing, the zero waypoint sits at the star
+func position(waypoints []spacetrader.Waypoint, waypointSymbol string) spacetrader.Waypoint {
+	for _, waypoint := range waypoints {
+		if waypoint.Symbol == waypointSymbol {
+			return waypoint
+		}
+	}
+
+	return spacetrader.Waypoint{}
+}
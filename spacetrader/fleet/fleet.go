@@ -0,0 +1,322 @@
+// Package fleet puts the whole fleet to work. Every ship is given a behaviour (mining, trading,
+// keeping market prices fresh, hauling for contracts or sitting idle) and runs it on its own
+// goroutine. Behaviours run as tasks in a queue, so they're picked back up after a restart, and
+// one that panics or gives up on an error is started again after a pause that grows each time
+// it happens, so one bad ship can't hammer the API.
+//
+// All the ships share one API, and through it the spacetrader package's rate limiter, so a
+// busy fleet queues up for requests rather than getting itself throttled:
+//
+//	scheduler := fleet.New(api, pilot, miner, prices, tasks)
+//	scheduler.Assign("SHIP-1", fleet.BehaviorMine)
+//	scheduler.Assign("SHIP-2", fleet.BehaviorProbeMarkets)
+package fleet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"example.com/spacetrader"
+	"example.com/spacetrader/autopilot"
+	"example.com/spacetrader/history"
+	"example.com/spacetrader/mining"
+	"example.com/spacetrader/queue"
+)
+
+type Behavior string
+
+const (
+	BehaviorIdle           Behavior = "idle"
+	BehaviorMine           Behavior = "mine"
+	BehaviorTrade          Behavior = "trade"
+	BehaviorProbeMarkets   Behavior = "probe-markets"
+	BehaviorContractHauler Behavior = "contract-hauler"
+)
+
+// Behaviors lists every behaviour in the order they're offered
+var Behaviors = []Behavior{BehaviorIdle, BehaviorMine, BehaviorTrade, BehaviorProbeMarkets, BehaviorContractHauler}
+
+func (b Behavior) Valid() bool {
+	for _, behavior := range Behaviors {
+		if b == behavior {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Suits reports whether a ship has what it takes for a behaviour, and if not why not
+func (b Behavior) Suits(ship spacetrader.Ship) error {
+	switch b {
+	case BehaviorMine:
+		if !spacetrader.CanMine(ship) {
+			return fmt.Errorf("%s doesn't have a mining laser", ship.Symbol)
+		}
+	case BehaviorTrade, BehaviorContractHauler:
+		if ship.Cargo.Capacity == 0 {
+			return fmt.Errorf("%s has no cargo hold", ship.Symbol)
+		}
+	}
+
+	return nil
+}
+
+type State string
+
+const (
+	StateRunning    State = "RUNNING"
+	StateRestarting State = "RESTARTING"
+	StateStopped    State = "STOPPED"
+)
+
+// Status is how a ship's behaviour is getting on
+type Status struct {
+	ShipSymbol string
+	Behavior   Behavior
+	State      State
+	Detail     string
+	// Restarts counts how many times in a row the behaviour has had to be started again, Error is why it last stopped
+	Restarts int
+	Error    string
+	Started  time.Time
+	Updated  time.Time
+}
+
+// TaskKind is what the scheduler's tasks are queued as
+const TaskKind = "fleet"
+
+// assignment is a ship's task payload
+type assignment struct {
+	ShipSymbol string   `json:"shipSymbol"`
+	Behavior   Behavior `json:"behavior"`
+}
+
+// worker is one ship's behaviour at work
+type worker struct {
+	status Status
+}
+
+// Scheduler runs every ship's behaviour as a task in the queue, so the fleet goes back to work
+// after a restart and a behaviour that fails is retried with the queue's backoff
+type Scheduler struct {
+	Now func() time.Time
+	// Idle is how long a behaviour with nothing to do waits before looking again
+	Idle time.Duration
+	// Refresh is how old a market's prices get before a probe goes back to see them again
+	Refresh time.Duration
+
+	api    spacetrader.API
+	pilot  *autopilot.Pilot
+	miner  *mining.Miner
+	prices *history.Store
+	tasks  *queue.Queue
+
+	// assigning keeps two reassignments of the same ship from racing each other
+	assigning sync.Mutex
+	mu        sync.Mutex
+	workers   map[string]*worker
+}
+
+// New sets up a scheduler and hands its behaviours to tasks to run
+func New(api spacetrader.API, pilot *autopilot.Pilot, miner *mining.Miner, prices *history.Store, tasks *queue.Queue) *Scheduler {
+	s := &Scheduler{
+		Now:     time.Now,
+		Idle:    time.Minute,
+		Refresh: 15 * time.Minute,
+		api:     api,
+		pilot:   pilot,
+		miner:   miner,
+		prices:  prices,
+		tasks:   tasks,
+		workers: map[string]*worker{},
+	}
+	tasks.Handle(TaskKind, s.work)
+
+	return s
+}
+
+// Assign sets a ship to work on behavior, stopping whatever it was doing first. The ship takes
+// over from the autopilot, so any journey it's on gets cancelled.
+func (s *Scheduler) Assign(shipSymbol string, behavior Behavior) error {
+	if !behavior.Valid() {
+		return fmt.Errorf("%q isn't a behaviour", behavior)
+	}
+
+	ship, err := s.api.GetShip(shipSymbol)
+	if err != nil {
+		return err
+	}
+
+	if err := behavior.Suits(ship); err != nil {
+		return err
+	}
+
+	s.assigning.Lock()
+	defer s.assigning.Unlock()
+
+	if _, err := s.tasks.Cancel(context.Background(), key(shipSymbol)); err != nil {
+		return err
+	}
+	// Mining started from the ship page is a task of the miner's own, left alone it keeps the
+	// ship looking busy to whatever the ship is given next
+	if err := s.miner.Stop(shipSymbol); err != nil && !errors.Is(err, mining.ErrNotMining) {
+		return err
+	}
+	s.pilot.Cancel(shipSymbol)
+
+	task, err := queue.NewTask(TaskKind, key(shipSymbol), assignment{ShipSymbol: shipSymbol, Behavior: behavior})
+	if err != nil {
+		return err
+	}
+	_, _, err = s.tasks.Enqueue(task)
+
+	return err
+}
+
+// Status is how a ship's behaviour is doing, put together from its task and what it last said
+func (s *Scheduler) Status(shipSymbol string) (Status, bool) {
+	task, ok := s.tasks.Task(key(shipSymbol))
+	if !ok {
+		return Status{}, false
+	}
+
+	var assigned assignment
+	if err := task.Decode(&assigned); err != nil {
+		return Status{}, false
+	}
+
+	status := Status{
+		ShipSymbol: shipSymbol,
+		Behavior:   assigned.Behavior,
+		State:      StateRunning,
+		Restarts:   task.Attempts,
+		Error:      task.Error,
+		Started:    task.Created,
+		Updated:    task.Updated,
+	}
+
+	s.mu.Lock()
+	if w, ok := s.workers[shipSymbol]; ok && w.status.Behavior == assigned.Behavior && w.status.Updated.After(status.Updated) {
+		status.Detail, status.Updated = w.status.Detail, w.status.Updated
+	}
+	s.mu.Unlock()
+
+	switch {
+	case task.State.Finished():
+		status.State, status.Detail = StateStopped, ""
+	case task.State == queue.StatePending && task.Attempts > 0:
+		status.State = StateRestarting
+		status.Detail = fmt.Sprintf("Restarting at %s", task.ScheduledAt.Local().Format(time.TimeOnly))
+	case task.State == queue.StatePending:
+		status.Detail = "Starting"
+	}
+
+	// The miner keeps its own account of what it's doing, which beats anything said here
+	if status.Behavior == BehaviorMine && task.State == queue.StateRunning {
+		if mined, ok := s.miner.Status(shipSymbol); ok && mined.Step.Running() {
+			status.Detail = fmt.Sprintf("%s: %s", mined.Step, mined.Detail)
+		}
+	}
+
+	return status, true
+}
+
+// Statuses lists every ship that's been given a behaviour, by ship
+func (s *Scheduler) Statuses() []Status {
+	shipSymbols := []string{}
+	seen := map[string]bool{}
+	for _, task := range s.tasks.Tasks() {
+		var assigned assignment
+		if task.Kind != TaskKind || task.Decode(&assigned) != nil || seen[assigned.ShipSymbol] {
+			continue
+		}
+		seen[assigned.ShipSymbol] = true
+		shipSymbols = append(shipSymbols, assigned.ShipSymbol)
+	}
+	sort.Strings(shipSymbols)
+
+	statuses := []Status{}
+	for _, shipSymbol := range shipSymbols {
+		if status, ok := s.Status(shipSymbol); ok {
+			statuses = append(statuses, status)
+		}
+	}
+
+	return statuses
+}
+
+// work is the queue's handler, it runs a ship's behaviour until it's stopped or fails
+func (s *Scheduler) work(ctx context.Context, task queue.Task) error {
+	var assigned assignment
+	if err := task.Decode(&assigned); err != nil {
+		return err
+	}
+
+	now := s.Now()
+	w := &worker{status: Status{ShipSymbol: assigned.ShipSymbol, Behavior: assigned.Behavior, Started: now, Updated: now}}
+
+	s.mu.Lock()
+	s.workers[assigned.ShipSymbol] = w
+	s.mu.Unlock()
+
+	err := s.attempt(ctx, w)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if errors.Is(err, errFinished) {
+		return nil
+	}
+	if err == nil {
+		err = fmt.Errorf("%s stopped by itself", assigned.Behavior)
+	}
+
+	return err
+}
+
+// attempt runs the behaviour once
+func (s *Scheduler) attempt(ctx context.Context, w *worker) error {
+	switch w.status.Behavior {
+	case BehaviorMine:
+		return s.mine(ctx, w)
+	case BehaviorTrade:
+		return s.trade(ctx, w)
+	case BehaviorProbeMarkets:
+		return s.probeMarkets(ctx, w)
+	case BehaviorContractHauler:
+		return s.haulContracts(ctx, w)
+	}
+
+	return s.idle(ctx, w)
+}
+
+// report says what the worker is up to
+func (s *Scheduler) report(w *worker, format string, a ...any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.status.Detail = fmt.Sprintf(format, a...)
+	w.status.Updated = s.Now()
+}
+
+// sleep waits for d, or until the worker is stopped
+func (s *Scheduler) sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// key is the ship's task key, a ship only ever has the one behaviour
+func key(shipSymbol string) string {
+	return TaskKind + ":" + shipSymbol
+}
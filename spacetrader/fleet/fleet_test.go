@@ -0,0 +1,514 @@
+package fleet_test
+
+import (
+	"errors"
+	"io"
+	"log"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"example.com/spacetrader"
+	"example.com/spacetrader/autopilot"
+	"example.com/spacetrader/fleet"
+	"example.com/spacetrader/history"
+	"example.com/spacetrader/mining"
+	"example.com/spacetrader/queue"
+)
+
+// X1-H is headquarters with a market selling iron ore, an asteroid field and a depot contracts
+// deliver to
+var harbourMap = []spacetrader.Waypoint{
+	{Symbol: "X1-H-HQ", SystemSymbol: "X1-H", Type: spacetrader.WaypointTypePlanet, PosX: 0, PosY: 0, Traits: []spacetrader.Trait{{Symbol: spacetrader.WaypointTraitMarketplace}}},
+	{Symbol: "X1-H-AST", SystemSymbol: "X1-H", Type: spacetrader.WaypointTypeAsteroidField, PosX: 20, PosY: 0},
+	{Symbol: "X1-H-DEPOT", SystemSymbol: "X1-H", Type: spacetrader.WaypointTypeOrbitalStation, PosX: 0, PosY: 30},
+}
+
+var headquarters = spacetrader.Market{
+	Symbol:   "X1-H-HQ",
+	Exports:  []spacetrader.MarketGood{{Symbol: spacetrader.TradeIronOre}},
+	Exchange: []spacetrader.MarketGood{{Symbol: spacetrader.TradeFuel}},
+	TradeGoods: []spacetrader.MarketTradeGood{
+		{Symbol: spacetrader.TradeIronOre, PurchasePrice: 20, SellPrice: 15, TradeVolume: 10},
+		{Symbol: spacetrader.TradeFuel, PurchasePrice: 2, SellPrice: 1, TradeVolume: 100},
+	},
+}
+
+// harbour plays the API for a small fleet: MINER over the asteroids, HAULER docked at
+// headquarters and PROBE, which has neither a laser nor a hold. Ships get wherever they're sent
+// straight away, but MINER's reactor is still cooling down for another hour, so a miner never
+// gets as far as digging.
+type harbour struct {
+	spacetrader.API
+
+	mu        sync.Mutex
+	ships     map[string]*spacetrader.Ship
+	contracts []spacetrader.Contract
+	bought    map[spacetrader.TradeSymbol]int
+	delivered map[string]int
+	accepted  []string
+	fulfilled []string
+}
+
+func newHarbour(contracts ...spacetrader.Contract) *harbour {
+	h := &harbour{
+		contracts: contracts,
+		bought:    map[spacetrader.TradeSymbol]int{},
+		delivered: map[string]int{},
+		ships: map[string]*spacetrader.Ship{
+			"MINER": {
+				Symbol: "MINER",
+				Nav:    spacetrader.ShipNav{SystemSymbol: "X1-H", WaypointSymbol: "X1-H-AST", Status: spacetrader.ShipNavStatusInOrbit, FlightMode: spacetrader.FlightModeCruise},
+				Cargo:  spacetrader.ShipCargo{Capacity: 30, Inventory: []spacetrader.Cargo{}},
+				Mounts: []spacetrader.ShipMount{{Symbol: spacetrader.TradeMountMiningLaserI, Strength: 10}},
+				Cooldown: spacetrader.Cooldown{
+					ShipSymbol: "MINER", TotalSeconds: 3600, RemainingSeconds: 3600,
+					Expiration: time.Now().Add(time.Hour).Format(time.RFC3339Nano),
+				},
+			},
+			"HAULER": {
+				Symbol: "HAULER",
+				Nav:    spacetrader.ShipNav{SystemSymbol: "X1-H", WaypointSymbol: "X1-H-HQ", Status: spacetrader.ShipNavStatusDocked, FlightMode: spacetrader.FlightModeCruise},
+				Cargo:  spacetrader.ShipCargo{Capacity: 40, Inventory: []spacetrader.Cargo{}},
+			},
+			"PROBE": {
+				Symbol: "PROBE",
+				Nav:    spacetrader.ShipNav{SystemSymbol: "X1-H", WaypointSymbol: "X1-H-HQ", Status: spacetrader.ShipNavStatusDocked, FlightMode: spacetrader.FlightModeCruise},
+			},
+		},
+	}
+	for _, ship := range h.ships {
+		ship.Engine = spacetrader.ShipEngine{Speed: 30}
+		ship.Fuel = spacetrader.ShipFuel{Current: 400, Capacity: 400}
+	}
+
+	return h
+}
+
+func (h *harbour) GetShip(shipSymbol string) (spacetrader.Ship, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ship, ok := h.ships[shipSymbol]
+	if !ok {
+		return spacetrader.Ship{}, errors.New(shipSymbol + " isn't in the fleet")
+	}
+	copied := *ship
+	copied.Cargo.Inventory = slices.Clone(ship.Cargo.Inventory)
+	return copied, nil
+}
+
+func (h *harbour) GetSystem(systemSymbol string) (spacetrader.System, error) {
+	return spacetrader.System{Symbol: systemSymbol, Waypoints: harbourMap}, nil
+}
+
+func (h *harbour) GetWaypoints(systemSymbol string, query spacetrader.WaypointQuery) ([]spacetrader.Waypoint, spacetrader.Meta, error) {
+	return harbourMap, spacetrader.Meta{Total: len(harbourMap)}, nil
+}
+
+func (h *harbour) GetWaypoint(systemSymbol string, waypointSymbol string) (spacetrader.Waypoint, error) {
+	for _, waypoint := range harbourMap {
+		if waypoint.Symbol == waypointSymbol {
+			return waypoint, nil
+		}
+	}
+	return spacetrader.Waypoint{}, errors.New(waypointSymbol + " isn't in X1-H")
+}
+
+func (h *harbour) GetMarket(systemSymbol string, waypointSymbol string) (spacetrader.Market, error) {
+	if waypointSymbol != headquarters.Symbol {
+		return spacetrader.Market{}, errors.New("no market at " + waypointSymbol)
+	}
+	return headquarters, nil
+}
+
+func (h *harbour) LaunchToOrbit(shipSymbol string) (bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.ships[shipSymbol].Nav.Status = spacetrader.ShipNavStatusInOrbit
+	return true, nil
+}
+
+func (h *harbour) DockShip(shipSymbol string) (bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.ships[shipSymbol].Nav.Status = spacetrader.ShipNavStatusDocked
+	return true, nil
+}
+
+func (h *harbour) SetFlightMode(shipSymbol string, mode spacetrader.FlightMode) (spacetrader.ShipNav, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.ships[shipSymbol].Nav.FlightMode = mode
+	return h.ships[shipSymbol].Nav, nil
+}
+
+func (h *harbour) NavigateShip(shipSymbol string, waypointSymbol string) (bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ship := h.ships[shipSymbol]
+	ship.Nav.WaypointSymbol, ship.Nav.Status = waypointSymbol, spacetrader.ShipNavStatusInOrbit
+	return true, nil
+}
+
+func (h *harbour) RefuelShip(shipSymbol string, units int) (spacetrader.ShipRefuel, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ship := h.ships[shipSymbol]
+	ship.Fuel.Current = ship.Fuel.Capacity
+	return spacetrader.ShipRefuel{Fuel: ship.Fuel}, nil
+}
+
+func (h *harbour) PurchaseCargo(shipSymbol string, tradeSymbol spacetrader.TradeSymbol, units int) (spacetrader.CargoTrade, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ship := h.ships[shipSymbol]
+	if ship.Nav.WaypointSymbol != headquarters.Symbol || ship.Nav.Status != spacetrader.ShipNavStatusDocked {
+		return spacetrader.CargoTrade{}, errors.New("not docked at a market")
+	}
+
+	h.bought[tradeSymbol] += units
+	ship.Cargo.Units += units
+	for i := range ship.Cargo.Inventory {
+		if ship.Cargo.Inventory[i].Symbol == tradeSymbol {
+			ship.Cargo.Inventory[i].Units += units
+			return spacetrader.CargoTrade{Cargo: ship.Cargo}, nil
+		}
+	}
+	ship.Cargo.Inventory = append(ship.Cargo.Inventory, spacetrader.Cargo{Symbol: tradeSymbol, Units: units})
+	return spacetrader.CargoTrade{Cargo: ship.Cargo}, nil
+}
+
+func (h *harbour) GetContracts() ([]spacetrader.Contract, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	contracts := []spacetrader.Contract{}
+	for _, contract := range h.contracts {
+		contract.Terms.Deliver = slices.Clone(contract.Terms.Deliver)
+		contracts = append(contracts, contract)
+	}
+	return contracts, nil
+}
+
+// contract finds one of the harbour's contracts, h.mu must be held
+func (h *harbour) contract(contractId string) *spacetrader.Contract {
+	for i := range h.contracts {
+		if h.contracts[i].Identifier == contractId {
+			return &h.contracts[i]
+		}
+	}
+	return nil
+}
+
+func (h *harbour) AcceptContract(contractId string) (spacetrader.ContractUpdate, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	contract := h.contract(contractId)
+	contract.Accepted = true
+	h.accepted = append(h.accepted, contractId)
+	return spacetrader.ContractUpdate{Contract: *contract}, nil
+}
+
+func (h *harbour) DeliverContract(contractId string, shipSymbol string, tradeSymbol spacetrader.TradeSymbol, units int) (spacetrader.ContractDeliveryUpdate, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ship, contract := h.ships[shipSymbol], h.contract(contractId)
+	for i, delivery := range contract.Terms.Deliver {
+		if delivery.TradeSymbol == tradeSymbol && delivery.DestinationSymbol == ship.Nav.WaypointSymbol {
+			contract.Terms.Deliver[i].UnitsFulfilled += units
+			h.delivered[ship.Nav.WaypointSymbol] += units
+		}
+	}
+	for i := range ship.Cargo.Inventory {
+		if ship.Cargo.Inventory[i].Symbol == tradeSymbol {
+			ship.Cargo.Inventory[i].Units -= units
+			ship.Cargo.Units -= units
+		}
+	}
+	return spacetrader.ContractDeliveryUpdate{Contract: *contract, Cargo: ship.Cargo}, nil
+}
+
+func (h *harbour) FulfillContract(contractId string) (spacetrader.ContractUpdate, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	contract := h.contract(contractId)
+	contract.Fulfilled = true
+	h.fulfilled = append(h.fulfilled, contractId)
+	return spacetrader.ContractUpdate{Contract: *contract}, nil
+}
+
+// crew is a scheduler and the miner it hands mining to
+type crew struct {
+	scheduler *fleet.Scheduler
+	miner     *mining.Miner
+}
+
+// muster sets a crew to work out of h, with headquarters' prices already on record
+func muster(t *testing.T, h *harbour) crew {
+	t.Helper()
+
+	logger := spacetrader.Logger
+	spacetrader.Logger = log.New(io.Discard, "", 0)
+	t.Cleanup(func() { spacetrader.Logger = logger })
+
+	dir := t.TempDir()
+	pilot, err := autopilot.New(h, filepath.Join(dir, "autopilot.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pilot.Slack, pilot.Poll = 0, time.Millisecond
+	t.Cleanup(pilot.Stop)
+
+	prices, err := history.Open(filepath.Join(dir, "market-history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { prices.Close() })
+	if err := prices.Record(headquarters, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	miner := mining.New(h, pilot, prices)
+	miner.Slack, miner.Poll = 0, time.Millisecond
+	t.Cleanup(miner.Shutdown)
+
+	// Cleanups run last first, so as in the server the queue stops handing out work before the
+	// miner winds down
+	tasks, err := queue.Open(filepath.Join(dir, "tasks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tasks.Backoff = time.Millisecond
+	t.Cleanup(tasks.Shutdown)
+
+	scheduler := fleet.New(h, pilot, miner, prices, tasks)
+	tasks.Start()
+
+	return crew{scheduler: scheduler, miner: miner}
+}
+
+// await polls the ship's behaviour until it satisfies done, and hands back its status
+func await(t *testing.T, scheduler *fleet.Scheduler, shipSymbol string, done func(fleet.Status) bool) fleet.Status {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		status, ok := scheduler.Status(shipSymbol)
+		if ok && done(status) {
+			return status
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("gave up waiting on %s, it's %+v", shipSymbol, status)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// detailed is waiting for the ship to say something starting with detail
+func detailed(detail string) func(fleet.Status) bool {
+	return func(status fleet.Status) bool { return strings.HasPrefix(status.Detail, detail) }
+}
+
+func TestAssign(t *testing.T) {
+	cases := []struct {
+		name       string
+		shipSymbol string
+		behavior   fleet.Behavior
+		want       string
+		detail     string
+	}{
+		{"not a behaviour", "HAULER", "dance", `"dance" isn't a behaviour`, ""},
+		{"not a ship in the fleet", "GHOST", fleet.BehaviorIdle, "GHOST isn't in the fleet", ""},
+		{"mining needs a laser", "HAULER", fleet.BehaviorMine, "HAULER doesn't have a mining laser", ""},
+		{"trading needs a hold", "PROBE", fleet.BehaviorTrade, "PROBE has no cargo hold", ""},
+		{"hauling needs a hold", "PROBE", fleet.BehaviorContractHauler, "PROBE has no cargo hold", ""},
+		{"anyone can sit idle", "PROBE", fleet.BehaviorIdle, "", "Waiting for orders"},
+		{"a hauler with no contracts waits for one", "HAULER", fleet.BehaviorContractHauler, "", "No contract worth taking, waiting for one"},
+		{"a miner goes to work", "MINER", fleet.BehaviorMine, "", "COOLING_DOWN: Cooling down until"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			crew := muster(t, newHarbour())
+
+			err := crew.scheduler.Assign(c.shipSymbol, c.behavior)
+			if c.want != "" {
+				if err == nil || err.Error() != c.want {
+					t.Errorf("got %v, want %s", err, c.want)
+				}
+				if _, ok := crew.scheduler.Status(c.shipSymbol); ok {
+					t.Errorf("%s was given a behaviour anyway", c.shipSymbol)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			status := await(t, crew.scheduler, c.shipSymbol, detailed(c.detail))
+			if status.Behavior != c.behavior || status.State != fleet.StateRunning {
+				t.Errorf("%s is %s on %s, want RUNNING on %s", c.shipSymbol, status.State, status.Behavior, c.behavior)
+			}
+		})
+	}
+}
+
+func TestAssignTakesOverShipPageMining(t *testing.T) {
+	cases := []struct {
+		behavior fleet.Behavior
+		detail   string
+	}{
+		{fleet.BehaviorIdle, "Waiting for orders"},
+		{fleet.BehaviorMine, "COOLING_DOWN: Cooling down until"},
+	}
+	for _, c := range cases {
+		t.Run(string(c.behavior), func(t *testing.T) {
+			crew := muster(t, newHarbour())
+
+			if err := crew.miner.Start("MINER", ""); err != nil {
+				t.Fatal(err)
+			}
+			for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+				if status, _ := crew.miner.Status("MINER"); status.Step == mining.StepCoolingDown {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatal("ship page mining never got going")
+				}
+			}
+
+			if err := crew.scheduler.Assign("MINER", c.behavior); err != nil {
+				t.Fatal(err)
+			}
+			await(t, crew.scheduler, "MINER", detailed(c.detail))
+
+			if mined, _ := crew.miner.Status("MINER"); mined.Step.Running() != (c.behavior == fleet.BehaviorMine) {
+				t.Errorf("miner is %s after the ship was set to %s", mined.Step, c.behavior)
+			}
+		})
+	}
+}
+
+func TestStoppingFleetMiningFinishesIt(t *testing.T) {
+	crew := muster(t, newHarbour())
+
+	if err := crew.scheduler.Assign("MINER", fleet.BehaviorMine); err != nil {
+		t.Fatal(err)
+	}
+	await(t, crew.scheduler, "MINER", detailed("COOLING_DOWN: Cooling down until"))
+
+	// As the ship page does
+	if err := crew.miner.Stop("MINER"); err != nil {
+		t.Fatal(err)
+	}
+
+	status := await(t, crew.scheduler, "MINER", func(status fleet.Status) bool { return status.State != fleet.StateRunning })
+	if status.State != fleet.StateStopped || status.Error != "" || status.Restarts != 0 {
+		t.Errorf("mining ended %s after %d restarts with error %q, want STOPPED without a failure", status.State, status.Restarts, status.Error)
+	}
+}
+
+// ironContract wants 30 iron ore at the depot, change makes it whatever a case needs
+func ironContract(change func(*spacetrader.Contract)) spacetrader.Contract {
+	now := time.Now()
+	contract := spacetrader.Contract{
+		Identifier:       "contract-1",
+		Type:             "PROCUREMENT",
+		DeadlineToAccept: now.Add(time.Hour).Format(time.RFC3339Nano),
+		Terms: spacetrader.ContractTerms{
+			Deadline: now.Add(24 * time.Hour).Format(time.RFC3339Nano),
+			Payment:  spacetrader.ContractPayment{OnAccepted: 1000, OnFulfilled: 20000},
+			Deliver: []spacetrader.ContractDelivery{
+				{TradeSymbol: spacetrader.TradeIronOre, DestinationSymbol: "X1-H-DEPOT", UnitsRequired: 30},
+			},
+		},
+	}
+	if change != nil {
+		change(&contract)
+	}
+
+	return contract
+}
+
+func TestHaulContracts(t *testing.T) {
+	waiting := "No contract worth taking, waiting for one"
+
+	cases := []struct {
+		name     string
+		contract spacetrader.Contract
+		// state and detail are where the hauler's left once it's done what it can
+		state     fleet.State
+		detail    string
+		err       string
+		accepted  []string
+		bought    int
+		delivered int
+		fulfilled []string
+	}{
+		{"an open contract is taken on and seen through", ironContract(nil),
+			fleet.StateRunning, waiting, "", []string{"contract-1"}, 30, 30, []string{"contract-1"}},
+		{"an accepted one is carried on with", ironContract(func(c *spacetrader.Contract) {
+			c.Accepted = true
+			c.Terms.Deliver[0].UnitsFulfilled = 20
+		}), fleet.StateRunning, waiting, "", nil, 10, 10, []string{"contract-1"}},
+		{"a delivered one is collected on", ironContract(func(c *spacetrader.Contract) {
+			c.Accepted = true
+			c.Terms.Deliver[0].UnitsFulfilled = 30
+		}), fleet.StateRunning, waiting, "", nil, 0, 0, []string{"contract-1"}},
+		{"an expired one is passed over", ironContract(func(c *spacetrader.Contract) {
+			c.Accepted = true
+			c.Terms.Deadline = time.Now().Add(-time.Minute).Format(time.RFC3339Nano)
+		}), fleet.StateRunning, waiting, "", nil, 0, 0, nil},
+		{"one that doesn't pay is left alone", ironContract(func(c *spacetrader.Contract) {
+			c.Terms.Payment = spacetrader.ContractPayment{OnFulfilled: 100}
+		}), fleet.StateRunning, waiting, "", nil, 0, 0, nil},
+		{"an accepted one it can't finish stops it for good", ironContract(func(c *spacetrader.Contract) {
+			c.Accepted = true
+			c.Terms.Deliver[0].DestinationSymbol = "X1-B-A1"
+		}), fleet.StateStopped, "", "contract contract-1 can't be finished: X1-B-A1 isn't on the map", nil, 0, 0, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h := newHarbour(c.contract)
+			crew := muster(t, h)
+
+			if err := crew.scheduler.Assign("HAULER", fleet.BehaviorContractHauler); err != nil {
+				t.Fatal(err)
+			}
+			status := await(t, crew.scheduler, "HAULER", func(status fleet.Status) bool {
+				return status.State == c.state && status.Detail == c.detail
+			})
+
+			if status.Error != c.err {
+				t.Errorf("hauler's error is %q, want %q", status.Error, c.err)
+			}
+			if c.err != "" && status.Restarts != 1 {
+				t.Errorf("hauler was tried %d times, want once", status.Restarts)
+			}
+
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			if !slices.Equal(h.accepted, c.accepted) || !slices.Equal(h.fulfilled, c.fulfilled) {
+				t.Errorf("accepted %v and fulfilled %v, want %v and %v", h.accepted, h.fulfilled, c.accepted, c.fulfilled)
+			}
+			if h.bought[spacetrader.TradeIronOre] != c.bought || h.delivered["X1-H-DEPOT"] != c.delivered {
+				t.Errorf("bought %d iron ore and delivered %d, want %d and %d", h.bought[spacetrader.TradeIronOre], h.delivered["X1-H-DEPOT"], c.bought, c.delivered)
+			}
+			if hauler := h.ships["HAULER"]; hauler.Cargo.Units != 0 {
+				t.Errorf("hauler still has %d units aboard", hauler.Cargo.Units)
+			}
+		})
+	}
+}
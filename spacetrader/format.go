@@ -0,0 +1,32 @@
+package spacetrader
+
+import (
+	"fmt"
+	"io"
+)
+
+// FormatAgent writes a plain text summary of the agent, handy for poking at the API from a terminal
+func FormatAgent(w io.Writer, agent Agent) error {
+	_, err := fmt.Fprintf(w, "AccountId: %s\nSymbol: %s\nCredits: %d\nShip Count: %d\n", agent.AccountId, agent.Symbol, agent.Credits, agent.ShipCount)
+
+	return err
+}
+
+// FormatWaypoints writes each waypoint out in its own block with ===== around it
+func FormatWaypoints(w io.Writer, waypoints []Waypoint) error {
+	for _, waypoint := range waypoints {
+		traits := ""
+		for _, wTrait := range waypoint.Traits {
+			traits = fmt.Sprintf("%s%s, ", traits, wTrait.Name)
+		}
+
+		_, err := fmt.Fprintf(w, "=======================================\nSymbol: %s\nType: %s\nX: %d\nY: %d\nTraits: %s\n=======================================\n",
+			waypoint.Symbol, waypoint.Type, waypoint.PosX, waypoint.PosY, traits)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,365 @@
+package spacetrader
+
+import (
+	"container/heap"
+	"fmt"
+	"slices"
+	"sort"
+
+	"example.com/spacetrader/model"
+)
+
+type JumpGate = model.JumpGate
+
+func GetJumpGate(token string, systemSymbol string, waypointSymbol string) (JumpGate, error) {
+	gate, err := model.GetJumpGate(NewClient(token), systemSymbol, waypointSymbol)
+
+	if err != nil {
+		return JumpGate{}, err
+	}
+
+	return gate, nil
+}
+
+// ShipJump is what comes back from jumping, the jump is instant but leaves the ship on cooldown
+type ShipJump = model.ShipJump
+
+// JumpShip sends a ship in orbit around a jump gate through to one of the gates it connects to
+func JumpShip(token string, shipSymbol string, waypointSymbol string) (ShipJump, error) {
+	destination := model.JumpShipRequest{WaypointSymbol: waypointSymbol}
+	jump, err := model.JumpShip(NewClient(token), shipSymbol, destination)
+
+	if err != nil {
+		return ShipJump{}, err
+	}
+
+	return jump, nil
+}
+
+// WarpShip flies a ship with a warp drive to a waypoint in another system, burning fuel like navigating does
+func WarpShip(token string, shipSymbol string, waypointSymbol string) (ShipTransit, error) {
+	destination := model.WarpShipRequest{WaypointSymbol: waypointSymbol}
+	warp, err := model.WarpShip(NewClient(token), shipSymbol, destination)
+
+	if err != nil {
+		return ShipTransit{}, err
+	}
+
+	return warp, nil
+}
+
+// CanWarp reports whether the ship has a warp drive fitted
+func CanWarp(ship Ship) bool {
+	for _, module := range ship.Modules {
+		switch module.Symbol {
+		case TradeModuleWarpDriveI, TradeModuleWarpDriveII, TradeModuleWarpDriveIII:
+			return true
+		}
+	}
+
+	return false
+}
+
+// Galaxy is the map voyages get planned over: the systems we know about, their waypoints and
+// which jump gates link up. Waypoint positions are inside their own system, system positions
+// are where they sit in the galaxy.
+type Galaxy struct {
+	Systems   map[string]System
+	Waypoints map[string]Waypoint
+	// Gates lists where each usable jump gate can send a ship, gates still being built are left out
+	Gates map[string][]string
+}
+
+func NewGalaxy(systems []System) Galaxy {
+	galaxy := Galaxy{
+		Systems:   map[string]System{},
+		Waypoints: map[string]Waypoint{},
+		Gates:     map[string][]string{},
+	}
+
+	for _, system := range systems {
+		galaxy.Systems[system.Symbol] = system
+		for _, waypoint := range system.Waypoints {
+			galaxy.Waypoints[waypoint.Symbol] = waypoint
+		}
+	}
+
+	return galaxy
+}
+
+// Connect adds a jump gate's connections, both ways since gates always link up in pairs
+func (g Galaxy) Connect(gate JumpGate) {
+	for _, connection := range gate.Connections {
+		if !slices.Contains(g.Gates[gate.Symbol], connection) {
+			g.Gates[gate.Symbol] = append(g.Gates[gate.Symbol], connection)
+		}
+		if !slices.Contains(g.Gates[connection], gate.Symbol) {
+			g.Gates[connection] = append(g.Gates[connection], gate.Symbol)
+		}
+	}
+}
+
+// AddSystem puts a system on the map, along with wherever its finished jump gates lead
+func (g Galaxy) AddSystem(api SystemsAPI, system System) error {
+	g.Systems[system.Symbol] = system
+
+	for _, listed := range system.Waypoints {
+		g.Waypoints[listed.Symbol] = listed
+
+		if listed.Type != WaypointTypeJumpGate {
+			continue
+		}
+
+		// The system listing doesn't say whether a gate works yet
+		waypoint, err := api.GetWaypoint(system.Symbol, listed.Symbol)
+
+		if err != nil {
+			return err
+		}
+
+		g.Waypoints[listed.Symbol] = waypoint
+
+		if waypoint.IsUnderConstruction {
+			continue
+		}
+
+		gate, err := api.GetJumpGate(system.Symbol, listed.Symbol)
+
+		if err != nil {
+			return err
+		}
+
+		g.Connect(gate)
+	}
+
+	return nil
+}
+
+// BuildGalaxy maps out every one of systems
+func BuildGalaxy(api SystemsAPI, systems []System) (Galaxy, error) {
+	galaxy := NewGalaxy(nil)
+
+	for _, system := range systems {
+		if err := galaxy.AddSystem(api, system); err != nil {
+			return Galaxy{}, err
+		}
+	}
+
+	return galaxy, nil
+}
+
+// ExploreGalaxy maps out the systems reachable by jump gate from one system, nearest first, until
+// it has limit of them. The target system is always on the map so there's somewhere to warp to.
+// Nothing makes sure the map joins the two up though: a target whose gates only connect beyond
+// the limit, and that's out of warp range, comes up with no route even though there is one.
+func ExploreGalaxy(api SystemsAPI, fromSystem string, toSystem string, limit int) (Galaxy, error) {
+	galaxy := NewGalaxy(nil)
+
+	queue := []string{toSystem, fromSystem}
+	seen := map[string]bool{toSystem: true, fromSystem: true}
+
+	for len(queue) > 0 && len(galaxy.Systems) < limit {
+		systemSymbol := queue[0]
+		queue = queue[1:]
+
+		system, err := api.GetSystem(systemSymbol)
+
+		if err != nil {
+			return Galaxy{}, err
+		}
+
+		if err := galaxy.AddSystem(api, system); err != nil {
+			return Galaxy{}, err
+		}
+
+		for _, waypoint := range system.Waypoints {
+			for _, connection := range galaxy.Gates[waypoint.Symbol] {
+				if next := SystemSymbol(connection); !seen[next] {
+					seen[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+	}
+
+	return galaxy, nil
+}
+
+// PlanVoyage finds the quickest way for ship to reach destination, which can be a waypoint or
+// a whole system. Inside a system it navigates, refuelling at any of fuelStations (waypoint
+// symbol to price per market unit) along the way. Between systems it jumps through gates
+// and, with a warp drive fitted, warps. Jumps count their cooldown as time spent. Warp times
+// and jump cooldowns are estimates (see WarpTime and JumpCooldown), so a voyage's Duration is too.
+func (g Galaxy) PlanVoyage(ship Ship, fuelStations map[string]int, destination string) (Route, error) {
+	origin := ship.Nav.WaypointSymbol
+	if ship.Nav.Status == ShipNavStatusInTransit {
+		origin = ship.Nav.Route.Destination.Symbol
+	}
+
+	if _, ok := g.Waypoints[origin]; !ok {
+		return Route{}, fmt.Errorf("%s isn't on the map", origin)
+	}
+
+	// Getting anywhere in a system counts when that's all that was asked for
+	_, toSystem := g.Systems[destination]
+	arrived := func(symbol string) bool {
+		if toSystem {
+			return SystemSymbol(symbol) == destination
+		}
+		return symbol == destination
+	}
+
+	if !toSystem {
+		if _, ok := g.Waypoints[destination]; !ok {
+			return Route{}, fmt.Errorf("%s isn't on the map", destination)
+		}
+	}
+
+	if arrived(origin) {
+		return Route{Hops: []RouteHop{}}, nil
+	}
+
+	// Only the start, the end, gates and anywhere we can refuel are worth stopping at
+	stops := []string{origin}
+	for symbol := range g.Waypoints {
+		_, station := fuelStations[symbol]
+		_, gate := g.Gates[symbol]
+		if symbol != origin && (station || gate || arrived(symbol)) {
+			stops = append(stops, symbol)
+		}
+	}
+	sort.Strings(stops[1:])
+
+	warp := CanWarp(ship)
+	capacity := ship.Fuel.Capacity
+
+	// Labels pop off quickest first, but a slower way to a stop is still worth keeping if it's
+	// cheaper or has more fuel left than every quicker one, the fuel might be what gets the ship
+	// the rest of the way. Only a way that's no better on either count gets dropped.
+	settled := map[string][]*routeLabel{}
+	dominated := func(at string, fuel int, cost int) bool {
+		for _, quicker := range settled[at] {
+			if quicker.fuel >= fuel && quicker.cost <= cost {
+				return true
+			}
+		}
+		return false
+	}
+	queue := &routeQueue{}
+	heap.Push(queue, &routeLabel{at: origin, fuel: ship.Fuel.Current})
+
+	for queue.Len() > 0 {
+		label := heap.Pop(queue).(*routeLabel)
+
+		if arrived(label.at) {
+			return label.route(), nil
+		}
+
+		if dominated(label.at, label.fuel, label.cost) {
+			continue
+		}
+		settled[label.at] = append(settled[label.at], label)
+
+		// Work out what's in the tank when we leave, filling up if we're at a station
+		departures := []struct{ fuel, refuel, cost int }{{label.fuel, 0, 0}}
+		if price, ok := fuelStations[label.at]; ok && capacity > 0 && label.fuel < capacity {
+			refuel := capacity - label.fuel
+			marketUnits := (refuel + fuelPerMarketUnit - 1) / fuelPerMarketUnit
+			departures = append(departures, struct{ fuel, refuel, cost int }{capacity, refuel, marketUnits * price})
+		}
+
+		push := func(hop RouteHop, fuelLeft int) {
+			if dominated(hop.To, fuelLeft, label.cost+hop.Cost) {
+				return
+			}
+			heap.Push(queue, &routeLabel{
+				at:       hop.To,
+				fuel:     fuelLeft,
+				duration: label.duration + hop.Duration + hop.Cooldown,
+				cost:     label.cost + hop.Cost,
+				previous: label,
+				hop:      hop,
+			})
+		}
+
+		// Jumping doesn't touch the tank, but the gate we're at has to be finished
+		gates := g.Gates[label.at]
+		if g.Waypoints[label.at].IsUnderConstruction {
+			gates = nil
+		}
+		for _, next := range gates {
+			// A gate on the far side that's still being built can't catch us, and we can't
+			// plan past systems we haven't mapped
+			_, mapped := g.Systems[SystemSymbol(next)]
+			if !mapped || g.Waypoints[next].IsUnderConstruction {
+				continue
+			}
+			howFar := g.systemDistance(label.at, next)
+			push(RouteHop{
+				Action:     HopJump,
+				From:       label.at,
+				To:         next,
+				Distance:   howFar,
+				Antimatter: 1,
+				Cooldown:   JumpCooldown(howFar),
+			}, label.fuel)
+		}
+
+		from := g.Waypoints[label.at]
+		for _, next := range stops {
+			if next == label.at {
+				continue
+			}
+
+			action, howFar := HopNavigate, 0.0
+			if SystemSymbol(next) == SystemSymbol(label.at) {
+				to := g.Waypoints[next]
+				howFar = Distance(from.PosX, from.PosY, to.PosX, to.PosY)
+			} else if warp {
+				action, howFar = HopWarp, g.systemDistance(label.at, next)
+			} else {
+				continue
+			}
+
+			for _, departure := range departures {
+				for _, mode := range plannedFlightModes {
+					fuel := FuelCost(mode, howFar)
+					// Ships without a tank (probes and the like) don't burn any fuel
+					if capacity == 0 {
+						fuel = 0
+					}
+					if fuel > departure.fuel {
+						continue
+					}
+
+					duration := TravelTime(mode, ship.Engine.Speed, howFar)
+					if action == HopWarp {
+						duration = WarpTime(mode, ship.Engine.Speed, howFar)
+					}
+
+					push(RouteHop{
+						Action:   action,
+						From:     label.at,
+						To:       next,
+						Mode:     mode,
+						Distance: howFar,
+						Fuel:     fuel,
+						Duration: duration,
+						Refuel:   departure.refuel,
+						Cost:     departure.cost,
+					}, departure.fuel-fuel)
+				}
+			}
+		}
+	}
+
+	return Route{}, fmt.Errorf("no route from %s to %s with %d/%d fuel", origin, destination, ship.Fuel.Current, capacity)
+}
+
+// systemDistance is how far apart the systems two waypoints are in
+func (g Galaxy) systemDistance(fromWaypoint string, toWaypoint string) float64 {
+	from := g.Systems[SystemSymbol(fromWaypoint)]
+	to := g.Systems[SystemSymbol(toWaypoint)]
+
+	return Distance(from.PosX, from.PosY, to.PosX, to.PosY)
+}
@@ -0,0 +1,88 @@
+package spacetrader_test
+
+import (
+	"testing"
+
+	"example.com/spacetrader"
+)
+
+// gateGalaxy is two systems joined by a gate, with nowhere to buy fuel in either of them
+func gateGalaxy() spacetrader.Galaxy {
+	galaxy := spacetrader.NewGalaxy([]spacetrader.System{
+		{Symbol: "X1-A", PosX: 0, PosY: 0, Waypoints: []spacetrader.Waypoint{
+			{Symbol: "X1-A-HOME", Type: spacetrader.WaypointTypePlanet, PosX: 0, PosY: 0},
+			{Symbol: "X1-A-GATE", Type: spacetrader.WaypointTypeJumpGate, PosX: 10, PosY: 0},
+		}},
+		{Symbol: "X1-B", PosX: 500, PosY: 0, Waypoints: []spacetrader.Waypoint{
+			{Symbol: "X1-B-GATE", Type: spacetrader.WaypointTypeJumpGate, PosX: 0, PosY: 0},
+			{Symbol: "X1-B-MOON", Type: spacetrader.WaypointTypeMoon, PosX: 100, PosY: 0},
+		}},
+	})
+	galaxy.Connect(spacetrader.JumpGate{Symbol: "X1-A-GATE", Connections: []string{"X1-B-GATE"}})
+
+	return galaxy
+}
+
+func shipAt(waypointSymbol string, fuel int, capacity int) spacetrader.Ship {
+	return spacetrader.Ship{
+		Symbol: "SHIP-1",
+		Nav: spacetrader.ShipNav{
+			SystemSymbol:   spacetrader.SystemSymbol(waypointSymbol),
+			WaypointSymbol: waypointSymbol,
+			Status:         spacetrader.ShipNavStatusInOrbit,
+		},
+		Fuel:   spacetrader.ShipFuel{Current: fuel, Capacity: capacity},
+		Engine: spacetrader.ShipEngine{Speed: 30},
+	}
+}
+
+// Burning to the gate gets there first but with an empty tank, the voyage only works by
+// cruising there and keeping enough back to drift on the far side
+func TestPlanVoyageKeepsSlowerArrivalsWithMoreFuel(t *testing.T) {
+	route, err := gateGalaxy().PlanVoyage(shipAt("X1-A-HOME", 20, 20), map[string]int{}, "X1-B-MOON")
+	if err != nil {
+		t.Fatalf("PlanVoyage: %v", err)
+	}
+
+	want := []struct {
+		action spacetrader.HopAction
+		to     string
+		mode   spacetrader.FlightMode
+	}{
+		{spacetrader.HopNavigate, "X1-A-GATE", spacetrader.FlightModeCruise},
+		{spacetrader.HopJump, "X1-B-GATE", ""},
+		{spacetrader.HopNavigate, "X1-B-MOON", spacetrader.FlightModeDrift},
+	}
+	if len(route.Hops) != len(want) {
+		t.Fatalf("got %d hops, want %d: %+v", len(route.Hops), len(want), route.Hops)
+	}
+	for i, hop := range route.Hops {
+		if hop.Action != want[i].action || hop.To != want[i].to || hop.Mode != want[i].mode {
+			t.Errorf("hop %d is %s to %s in %s, want %s to %s in %s", i, hop.Action, hop.To, hop.Mode, want[i].action, want[i].to, want[i].mode)
+		}
+	}
+	if route.Antimatter != 1 {
+		t.Errorf("route uses %d antimatter, want 1", route.Antimatter)
+	}
+}
+
+func TestPlanVoyageToASystem(t *testing.T) {
+	route, err := gateGalaxy().PlanVoyage(shipAt("X1-A-HOME", 20, 20), map[string]int{}, "X1-B")
+	if err != nil {
+		t.Fatalf("PlanVoyage: %v", err)
+	}
+
+	// Any waypoint in the system will do, and the gate's the first one there
+	if len(route.Hops) != 2 || route.Hops[1].To != "X1-B-GATE" {
+		t.Errorf("got %+v, want a flight to the gate and a jump", route.Hops)
+	}
+}
+
+func TestPlanVoyageWithoutAGateIsUnreachable(t *testing.T) {
+	galaxy := gateGalaxy()
+	galaxy.Gates = map[string][]string{}
+
+	if route, err := galaxy.PlanVoyage(shipAt("X1-A-HOME", 20, 20), map[string]int{}, "X1-B-MOON"); err == nil {
+		t.Errorf("got %+v, want no route without a gate or a warp drive", route.Hops)
+	}
+}
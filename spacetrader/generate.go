@@ -0,0 +1,15 @@
+package spacetrader
+
+// The model package is generated from the vendored OpenAPI spec, and the API types here are
+// aliases of it. To pick up API changes drop the upstream SpaceTraders.json into openapi/,
+// carry the x-go-name and x-go-type annotations over and run go generate.
+//
+//go:generate go run ./internal/openapigen -spec openapi/SpaceTraders.json -out model
+
+// Do lets the generated model endpoints go through the client, so they get the same
+// auth, rate limiting, retries and logging as everything else:
+//
+//	ship, err := model.GetMyShip(client, "SHIP-1")
+func (c *Client) Do(method string, path string, body any, out any) error {
+	return request(c.Token, method, path, body, out)
+}
@@ -0,0 +1,164 @@
+package spacetrader
+
+import (
+	"fmt"
+	"slices"
+
+	"example.com/spacetrader/model"
+)
+
+// Cooldown is how long a ship has to rest its reactor after refining, siphoning and the like
+type Cooldown = model.Cooldown
+
+// What refining, siphoning, surveying and extracting send back are all generated models
+type (
+	// Yield is an amount of some good that was produced or consumed by the refinery
+	Yield = model.Yield
+
+	// Refinement is what comes back after running the refinery
+	Refinement = model.Refinement
+
+	SiphonYield = model.SiphonYield
+	ShipSiphon  = model.Siphon
+
+	// Siphon is what comes back after siphoning gas out of a gas giant
+	Siphon = model.SiphonResourcesResult
+
+	SurveyDeposit = model.SurveyDeposit
+
+	// Survey is a map of what's under an asteroid. Extracting with one in hand leans the yield
+	// towards its deposits, until it expires or the asteroid is mined out.
+	Survey = model.Survey
+
+	// ShipSurvey is what comes back after surveying
+	ShipSurvey = model.ShipSurvey
+
+	ExtractionYield = model.ExtractionYield
+	ShipExtraction  = model.Extraction
+
+	// Extract is what comes back after mining an asteroid
+	Extract = model.Extract
+)
+
+// What each refinery module knows how to make
+var refineryProducts = map[TradeSymbol][]TradeSymbol{
+	TradeModuleOreRefineryI:   {TradeIron, TradeCopper, TradeSilver, TradeGold, TradeAluminum, TradePlatinum, TradeUranite, TradeMeritium},
+	TradeModuleMicroRefineryI: {TradeIron, TradeCopper, TradeSilver, TradeGold, TradeAluminum, TradePlatinum, TradeUranite, TradeMeritium},
+	TradeModuleFuelRefineryI:  {TradeFuel},
+}
+
+// RefineryProducts lists every good the ship's refinery modules can make, empty if it has none
+func RefineryProducts(ship Ship) []TradeSymbol {
+	products := []TradeSymbol{}
+	for _, module := range ship.Modules {
+		for _, product := range refineryProducts[module.Symbol] {
+			if !slices.Contains(products, product) {
+				products = append(products, product)
+			}
+		}
+	}
+
+	return products
+}
+
+// CanSiphon reports whether the ship has a gas siphon mounted
+func CanSiphon(ship Ship) bool {
+	for _, mount := range ship.Mounts {
+		switch mount.Symbol {
+		case TradeMountGasSiphonI, TradeMountGasSiphonII, TradeMountGasSiphonIII:
+			return true
+		}
+	}
+
+	return false
+}
+
+// RefineCargo turns raw goods in the hold into produce, the ship needs a refinery that can make it
+func RefineCargo(token string, shipSymbol string, produce TradeSymbol) (Refinement, error) {
+	if !slices.Contains(refineryProducts[TradeModuleOreRefineryI], produce) && !slices.Contains(refineryProducts[TradeModuleFuelRefineryI], produce) {
+		return Refinement{}, fmt.Errorf("%s can't be refined", produce)
+	}
+
+	order := model.ShipRefineRequest{Produce: model.ShipRefineRequestProduce(produce)}
+	refinement, err := model.ShipRefine(NewClient(token), shipSymbol, order)
+
+	if err != nil {
+		return Refinement{}, err
+	}
+
+	return refinement, nil
+}
+
+// CanMine reports whether the ship has a mining laser mounted
+func CanMine(ship Ship) bool {
+	for _, mount := range ship.Mounts {
+		switch mount.Symbol {
+		case TradeMountMiningLaserI, TradeMountMiningLaserII, TradeMountMiningLaserIII:
+			return true
+		}
+	}
+
+	return false
+}
+
+// Minable is true for the kinds of waypoint a mining laser works on
+func Minable(kind WaypointType) bool {
+	switch kind {
+	case WaypointTypeAsteroid, WaypointTypeAsteroidField, WaypointTypeEngineeredAsteroid:
+		return true
+	}
+
+	return false
+}
+
+// CanSurvey reports whether the ship has a surveyor mounted
+func CanSurvey(ship Ship) bool {
+	for _, mount := range ship.Mounts {
+		switch mount.Symbol {
+		case TradeMountSurveyorI, TradeMountSurveyorII, TradeMountSurveyorIII:
+			return true
+		}
+	}
+
+	return false
+}
+
+// CreateSurvey maps out the deposits at the waypoint the ship is orbiting
+func CreateSurvey(token string, shipSymbol string) (ShipSurvey, error) {
+	survey, err := model.CreateSurvey(NewClient(token), shipSymbol)
+
+	if err != nil {
+		return ShipSurvey{}, err
+	}
+
+	return survey, nil
+}
+
+// ExtractResources mines the asteroid the ship is orbiting. Pass a survey of it to aim for its
+// deposits, or nil to take whatever comes up.
+func ExtractResources(token string, shipSymbol string, survey *Survey) (Extract, error) {
+	var extract Extract
+	var err error
+	if survey != nil {
+		extract, err = model.ExtractResourcesWithSurvey(NewClient(token), shipSymbol, *survey)
+	} else {
+		extract, err = model.ExtractResources(NewClient(token), shipSymbol)
+	}
+
+	if err != nil {
+		return Extract{}, err
+	}
+
+	return extract, nil
+}
+
+// SiphonResources pulls gas out of the gas giant the ship is orbiting
+func SiphonResources(token string, shipSymbol string) (Siphon, error) {
+	siphon, err := model.SiphonResources(NewClient(token), shipSymbol)
+
+	if err != nil {
+		return Siphon{}, err
+	}
+
+	return siphon, nil
+}
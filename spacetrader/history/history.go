@@ -0,0 +1,282 @@
+// Package history remembers every market price we've seen. Markets only show their prices
+// while one of our ships is there, so without it anything not currently visited is a blank.
+//
+// Observations are appended to a JSON Lines file as they come in and the whole lot is kept in
+// memory for querying, there's no database to run:
+//
+//	prices, err := history.Open("market-history.jsonl")
+//	api := history.Record(spacetrader.NewClient(token), prices)
+//	latest, ok := prices.Latest("X1-TEST-A1", spacetrader.TradeFuel)
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"example.com/spacetrader"
+)
+
+// Observation is a trade good's prices at a market at one moment
+type Observation struct {
+	Waypoint string    `json:"waypoint"`
+	Time     time.Time `json:"time"`
+	spacetrader.MarketTradeGood
+}
+
+// Trend is how a good's prices moved between the first and last observations in a window
+type Trend struct {
+	First Observation
+	Last  Observation
+	// PurchaseChange and SellChange are in credits, Percent follows the purchase price
+	PurchaseChange int
+	SellChange     int
+	Percent        float64
+}
+
+// Direction sums the trend up as an arrow
+func (t Trend) Direction() string {
+	switch {
+	case t.PurchaseChange > 0:
+		return "▲"
+	case t.PurchaseChange < 0:
+		return "▼"
+	}
+
+	return "▬"
+}
+
+type key struct {
+	waypoint string
+	good     spacetrader.TradeSymbol
+}
+
+// Store is the price history, safe to share between goroutines
+type Store struct {
+	mu           sync.Mutex
+	file         *os.File
+	observations map[key][]Observation
+}
+
+// Open loads the history saved at path, creating the file if it isn't there yet. A line
+// cut short by a crash halfway through writing it is skipped rather than refusing to start.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Store{file: file, observations: map[key][]Observation{}}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var observation Observation
+		if err := json.Unmarshal(scanner.Bytes(), &observation); err != nil {
+			continue
+		}
+		s.add(observation)
+	}
+	if err := scanner.Err(); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("history: could not read %s: %w", path, err)
+	}
+
+	// Finish off a torn last line so the next observation doesn't get glued on to it
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, info.Size()-1); err == nil && last[0] != '\n' {
+			file.Write([]byte{'\n'})
+		}
+	}
+
+	// Files get written in order, but sorting once keeps a hand edited one honest
+	for k := range s.observations {
+		sort.SliceStable(s.observations[k], func(i, j int) bool {
+			return s.observations[k][i].Time.Before(s.observations[k][j].Time)
+		})
+	}
+
+	return s, nil
+}
+
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+// add files an observation in memory, s.mu must be held (or the store not shared yet)
+func (s *Store) add(observation Observation) {
+	k := key{observation.Waypoint, observation.Symbol}
+	s.observations[k] = append(s.observations[k], observation)
+}
+
+// Record saves every trade good in market as seen at that time. Markets seen without a ship
+// there don't have prices, so there's nothing to record.
+func (s *Store) Record(market spacetrader.Market, at time.Time) error {
+	if len(market.TradeGoods) == 0 {
+		return nil
+	}
+
+	lines := []byte{}
+	observations := []Observation{}
+	for _, good := range market.TradeGoods {
+		observation := Observation{Waypoint: market.Symbol, Time: at.UTC(), MarketTradeGood: good}
+		encoded, err := json.Marshal(observation)
+		if err != nil {
+			return err
+		}
+		lines = append(append(lines, encoded...), '\n')
+		observations = append(observations, observation)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// One write per market so a whole visit lands or none of it does, near enough
+	if _, err := s.file.Write(lines); err != nil {
+		return err
+	}
+
+	for _, observation := range observations {
+		s.add(observation)
+	}
+
+	return nil
+}
+
+// Latest is the most recent observation of a good at a market
+func (s *Store) Latest(waypointSymbol string, good spacetrader.TradeSymbol) (Observation, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	observations := s.observations[key{waypointSymbol, good}]
+	if len(observations) == 0 {
+		return Observation{}, false
+	}
+
+	return observations[len(observations)-1], true
+}
+
+// LatestMarket is the most recent observation of everything traded at a market, by trade symbol
+func (s *Store) LatestMarket(waypointSymbol string) []Observation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	latest := []Observation{}
+	for k, observations := range s.observations {
+		if k.waypoint == waypointSymbol && len(observations) > 0 {
+			latest = append(latest, observations[len(observations)-1])
+		}
+	}
+	sort.Slice(latest, func(i, j int) bool { return latest[i].Symbol < latest[j].Symbol })
+
+	return latest
+}
+
+// History is every observation of a good at a market since a time, oldest first
+func (s *Store) History(waypointSymbol string, good spacetrader.TradeSymbol, since time.Time) []Observation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	observations := s.observations[key{waypointSymbol, good}]
+	start := sort.Search(len(observations), func(i int) bool { return !observations[i].Time.Before(since) })
+
+	return append([]Observation{}, observations[start:]...)
+}
+
+// Trend compares the first and last observations of a good since a time. It needs at least
+// two observations to say anything.
+func (s *Store) Trend(waypointSymbol string, good spacetrader.TradeSymbol, since time.Time) (Trend, bool) {
+	observations := s.History(waypointSymbol, good, since)
+	if len(observations) < 2 {
+		return Trend{}, false
+	}
+
+	first, last := observations[0], observations[len(observations)-1]
+	trend := Trend{
+		First:          first,
+		Last:           last,
+		PurchaseChange: last.PurchasePrice - first.PurchasePrice,
+		SellChange:     last.SellPrice - first.SellPrice,
+	}
+	if first.PurchasePrice > 0 {
+		trend.Percent = float64(trend.PurchaseChange) / float64(first.PurchasePrice) * 100
+	}
+
+	return trend, true
+}
+
+// Age is how long ago a market's prices were last seen
+func (s *Store) Age(waypointSymbol string, now time.Time) (time.Duration, bool) {
+	latest := s.LatestMarket(waypointSymbol)
+	if len(latest) == 0 {
+		return 0, false
+	}
+
+	newest := latest[0].Time
+	for _, observation := range latest[1:] {
+		if observation.Time.After(newest) {
+			newest = observation.Time
+		}
+	}
+
+	return now.Sub(newest), true
+}
+
+// Waypoints lists every market with prices on record
+func (s *Store) Waypoints() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := map[string]bool{}
+	waypoints := []string{}
+	for k := range s.observations {
+		if !seen[k.waypoint] {
+			seen[k.waypoint] = true
+			waypoints = append(waypoints, k.waypoint)
+		}
+	}
+	sort.Strings(waypoints)
+
+	return waypoints
+}
+
+// RecordingAPI writes down every market it fetches. Put it underneath any cache so only fresh
+// fetches get recorded, not the same answer over and over.
+type RecordingAPI struct {
+	spacetrader.API
+	Store *Store
+	// Now stamps each observation
+	Now func() time.Time
+}
+
+func Record(api spacetrader.API, store *Store) *RecordingAPI {
+	return &RecordingAPI{API: api, Store: store, Now: time.Now}
+}
+
+func (r *RecordingAPI) GetMarket(systemSymbol string, waypointSymbol string) (spacetrader.Market, error) {
+	market, err := r.API.GetMarket(systemSymbol, waypointSymbol)
+
+	if err != nil {
+		return market, err
+	}
+
+	// Losing one observation isn't worth failing the page over
+	if err := r.Store.Record(market, r.Now()); err != nil {
+		spacetrader.Logger.Printf("history: could not record %s: %v", waypointSymbol, err)
+	}
+
+	return market, nil
+}
@@ -0,0 +1,427 @@
+// Command openapigen turns the SpaceTraders OpenAPI spec into Go models and endpoint functions.
+// It only understands the parts of OpenAPI 3 the SpaceTraders spec actually uses: objects,
+// arrays, string enums, $refs to components and JSON request/response bodies.
+//
+// Two extensions let the spec keep the wire format as upstream has it while the Go side keeps
+// the names the rest of the client already uses. x-go-name renames a property's field, or names
+// the type an inline object or enum gets pulled out as. x-go-type types a property as another
+// model type rather than declaring one of its own, and on a component makes it an alias of one.
+//
+//	go run ./internal/openapigen -spec openapi/SpaceTraders.json -out model
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type Spec struct {
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+type Operation struct {
+	OperationId string       `json:"operationId"`
+	Summary     string       `json:"summary"`
+	Parameters  []*Parameter `json:"parameters"`
+	RequestBody *struct {
+		Content map[string]struct {
+			Schema *Schema `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]struct {
+		Content map[string]struct {
+			Schema *Schema `json:"schema"`
+		} `json:"content"`
+	} `json:"responses"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+type Schema struct {
+	Ref         string             `json:"$ref"`
+	Type        string             `json:"type"`
+	Format      string             `json:"format"`
+	Description string             `json:"description"`
+	Enum        []string           `json:"enum"`
+	Properties  map[string]*Schema `json:"properties"`
+	Required    []string           `json:"required"`
+	Items       *Schema            `json:"items"`
+	GoName      string             `json:"x-go-name"`
+	GoType      string             `json:"x-go-type"`
+}
+
+// generator collects every named type as it's discovered so inline objects and enums
+// get pulled out into their own declarations
+type generator struct {
+	decls    []string
+	declared map[string]bool
+	usesURL  bool
+}
+
+func main() {
+	specPath := flag.String("spec", "openapi/SpaceTraders.json", "OpenAPI spec to generate from")
+	out := flag.String("out", "model", "directory to write the generated package to")
+	flag.Parse()
+
+	if err := generate(*specPath, *out); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate writes models.go and endpoints.go for the spec at specPath into out
+func generate(specPath string, out string) error {
+	encoded, err := os.ReadFile(specPath)
+	if err != nil {
+		return err
+	}
+
+	var spec Spec
+	if err := json.Unmarshal(encoded, &spec); err != nil {
+		return fmt.Errorf("could not read %s: %w", specPath, err)
+	}
+
+	g := &generator{declared: map[string]bool{}}
+
+	names := make([]string, 0, len(spec.Components.Schemas))
+	for name := range spec.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		g.declare(name, spec.Components.Schemas[name])
+	}
+
+	endpoints := g.endpoints(spec)
+
+	models := fmt.Sprintf("%s\n// Package model is generated from the SpaceTraders OpenAPI spec. Only methods.go is written by hand, go generate overwrites the rest.\npackage model\n\n%s", header, strings.Join(g.decls, ""))
+	if err := write(filepath.Join(out, "models.go"), models); err != nil {
+		return err
+	}
+
+	return write(filepath.Join(out, "endpoints.go"), endpoints)
+}
+
+const header = "// Code generated by openapigen from the SpaceTraders OpenAPI spec. DO NOT EDIT.\n"
+
+func write(path string, source string) error {
+	formatted, err := format.Source([]byte(source))
+	if err != nil {
+		return fmt.Errorf("generated %s doesn't compile: %w", path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, formatted, 0o644)
+}
+
+// declare writes out a named type for schema, unless one already exists. Its slot is taken
+// up front so a type always comes before any inline types it pulls out.
+func (g *generator) declare(name string, schema *Schema) {
+	if g.declared[name] {
+		return
+	}
+	g.declared[name] = true
+
+	slot := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	var out bytes.Buffer
+	comment(&out, name, schema.Description)
+
+	switch {
+	case schema.GoType != "":
+		fmt.Fprintf(&out, "type %s = %s\n\n", name, schema.GoType)
+	case len(schema.Enum) > 0:
+		enum(&out, name, schema)
+	case schema.Type == "object" && len(schema.Properties) > 0:
+		g.object(&out, name, schema)
+	default:
+		fmt.Fprintf(&out, "type %s %s\n\n", name, g.goType(name, schema))
+	}
+
+	g.decls[slot] = out.String()
+}
+
+func enum(out *bytes.Buffer, name string, schema *Schema) {
+	fmt.Fprintf(out, "type %s string\n\nconst (\n", name)
+	for _, value := range schema.Enum {
+		fmt.Fprintf(out, "\t%s%s %s = %q\n", name, goName(value), name, value)
+	}
+	fmt.Fprintf(out, ")\n\nvar %sValues = []%s{\n", name, name)
+	for _, value := range schema.Enum {
+		fmt.Fprintf(out, "\t%s%s,\n", name, goName(value))
+	}
+	fmt.Fprintf(out, "}\n\n")
+	fmt.Fprintf(out, "func (v %s) Valid() bool {\n\tfor _, known := range %sValues {\n\t\tif v == known {\n\t\t\treturn true\n\t\t}\n\t}\n\n\treturn false\n}\n\n", name, name)
+}
+
+func (g *generator) object(out *bytes.Buffer, name string, schema *Schema) {
+	required := map[string]bool{}
+	for _, field := range schema.Required {
+		required[field] = true
+	}
+
+	fields := make([]string, 0, len(schema.Properties))
+	for field := range schema.Properties {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	fmt.Fprintf(out, "type %s struct {\n", name)
+	for _, field := range fields {
+		property := schema.Properties[field]
+		tag := field
+		if !required[field] {
+			tag += ",omitempty"
+		}
+		if property.Description != "" {
+			fmt.Fprintf(out, "\t// %s\n", oneLine(property.Description))
+		}
+		fieldName := goName(field)
+		if property.GoName != "" {
+			fieldName = property.GoName
+		}
+		fmt.Fprintf(out, "\t%s %s `json:%q`\n", fieldName, g.goType(name+goName(field), property), tag)
+	}
+	fmt.Fprintf(out, "}\n\n")
+}
+
+// goType names the Go type for schema, declaring it as context if it has to be pulled out
+func (g *generator) goType(context string, schema *Schema) string {
+	switch {
+	case schema == nil:
+		return "any"
+	case schema.GoType != "":
+		return schema.GoType
+	case schema.Ref != "":
+		return schema.Ref[strings.LastIndex(schema.Ref, "/")+1:]
+	case len(schema.Enum) > 0, schema.Type == "object" && len(schema.Properties) > 0:
+		if schema.GoName != "" {
+			context = schema.GoName
+		}
+		g.declare(context, schema)
+		return context
+	case schema.Type == "array":
+		return "[]" + g.goType(context+"Item", schema.Items)
+	case schema.Type == "object":
+		return "map[string]any"
+	case schema.Type == "integer" && schema.Format == "int64":
+		return "int64"
+	case schema.Type == "integer":
+		return "int"
+	case schema.Type == "number":
+		return "float64"
+	case schema.Type == "boolean":
+		return "bool"
+	case schema.Type == "string":
+		return "string"
+	}
+
+	return "any"
+}
+
+func (g *generator) endpoints(spec Spec) string {
+	var out bytes.Buffer
+
+	fmt.Fprintf(&out, "// Doer sends one request to the API and decodes the whole response body, data and meta, into out\n")
+	fmt.Fprintf(&out, "type Doer interface {\n\tDo(method string, path string, body any, out any) error\n}\n\n")
+
+	paths := make([]string, 0, len(spec.Paths))
+	for path := range spec.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		methods := make([]string, 0, len(spec.Paths[path]))
+		for method := range spec.Paths[path] {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
+		for _, method := range methods {
+			g.endpoint(&out, strings.ToUpper(method), path, spec.Paths[path][method])
+		}
+	}
+
+	imports := "\"fmt\"\n\t\"net/http\""
+	if g.usesURL {
+		imports += "\n\t\"net/url\""
+	}
+
+	return fmt.Sprintf("%s\npackage model\n\nimport (\n\t%s\n)\n\n%s", header, imports, out.String())
+}
+
+func (g *generator) endpoint(out *bytes.Buffer, method string, path string, op *Operation) {
+	name := goName(op.OperationId)
+
+	args := []string{"d Doer"}
+	hasQuery := false
+	for _, param := range op.Parameters {
+		switch param.In {
+		case "path":
+			g.usesURL = true
+			args = append(args, fmt.Sprintf("%s string", lowerFirst(goName(param.Name))))
+		case "query":
+			g.usesURL = true
+			hasQuery = true
+		}
+	}
+
+	body := "nil"
+	if op.RequestBody != nil {
+		if content, ok := op.RequestBody.Content["application/json"]; ok {
+			args = append(args, fmt.Sprintf("body %s", g.goType(name+"Request", content.Schema)))
+			body = "body"
+		}
+	}
+	if hasQuery {
+		args = append(args, "query url.Values")
+	}
+
+	// The data and meta the response is wrapped in
+	data, meta := "any", false
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		if content, ok := op.Responses[code].Content["application/json"]; ok && content.Schema != nil {
+			data = g.goType(name+"Result", content.Schema.Properties["data"])
+			_, meta = content.Schema.Properties["meta"]
+		}
+		break
+	}
+
+	// Turn /my/ships/{shipSymbol}/dock into fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/dock")
+	var pathExpr []string
+	literal := ""
+	for _, part := range strings.Split(strings.TrimPrefix(path, "/"), "/") {
+		if strings.HasPrefix(part, "{") {
+			pathExpr = append(pathExpr, fmt.Sprintf("%q", literal+"/"), fmt.Sprintf("url.PathEscape(%s)", lowerFirst(goName(strings.Trim(part, "{}")))))
+			literal = ""
+		} else {
+			literal += "/" + part
+		}
+	}
+	if literal != "" {
+		pathExpr = append(pathExpr, fmt.Sprintf("%q", literal))
+	}
+
+	returns := fmt.Sprintf("(%s, error)", data)
+	if meta {
+		returns = fmt.Sprintf("(%s, Meta, error)", data)
+	}
+
+	fmt.Fprintf(out, "// %s is %s %s", name, method, path)
+	if op.Summary != "" {
+		fmt.Fprintf(out, ", %s", op.Summary)
+	}
+	fmt.Fprintf(out, "\nfunc %s(%s) %s {\n", name, strings.Join(args, ", "), returns)
+	if len(pathExpr) == 1 {
+		fmt.Fprintf(out, "\tpath := %s\n", pathExpr[0])
+	} else {
+		fmt.Fprintf(out, "\tpath := fmt.Sprint(%s)\n", strings.Join(pathExpr, ", "))
+	}
+	if hasQuery {
+		fmt.Fprintf(out, "\tif len(query) > 0 {\n\t\tpath = fmt.Sprint(path, \"?\", query.Encode())\n\t}\n")
+	}
+	fmt.Fprintf(out, "\n\tvar response struct {\n\t\tData %s `json:\"data\"`\n", data)
+	if meta {
+		fmt.Fprintf(out, "\t\tMeta Meta `json:\"meta\"`\n")
+	}
+	fmt.Fprintf(out, "\t}\n\terr := d.Do(%s, path, %s, &response)\n\n", httpMethod(method), body)
+	if meta {
+		fmt.Fprintf(out, "\treturn response.Data, response.Meta, err\n}\n\n")
+	} else {
+		fmt.Fprintf(out, "\treturn response.Data, err\n}\n\n")
+	}
+}
+
+func httpMethod(method string) string {
+	switch method {
+	case http.MethodGet:
+		return "http.MethodGet"
+	case http.MethodPost:
+		return "http.MethodPost"
+	case http.MethodPatch:
+		return "http.MethodPatch"
+	case http.MethodPut:
+		return "http.MethodPut"
+	case http.MethodDelete:
+		return "http.MethodDelete"
+	}
+
+	return fmt.Sprintf("%q", method)
+}
+
+func comment(out *bytes.Buffer, name string, description string) {
+	if description == "" {
+		return
+	}
+
+	fmt.Fprintf(out, "// %s: %s\n", name, oneLine(description))
+}
+
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// goName turns accountId, get-my-agent and ORE_REFINERY_I into AccountId, GetMyAgent and OreRefineryI
+func goName(name string) string {
+	var out strings.Builder
+	upper := true
+	shouting := strings.ToUpper(name) == name
+
+	for _, r := range name {
+		if r == '-' || r == '_' || r == ' ' || r == '.' {
+			upper = true
+			continue
+		}
+
+		switch {
+		case upper:
+			out.WriteRune(unicode.ToUpper(r))
+		case shouting:
+			out.WriteRune(unicode.ToLower(r))
+		default:
+			out.WriteRune(r)
+		}
+		upper = false
+	}
+
+	return out.String()
+}
+
+func lowerFirst(name string) string {
+	if name == "" {
+		return name
+	}
+
+	return strings.ToLower(name[:1]) + name[1:]
+}
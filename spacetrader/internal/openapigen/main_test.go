@@ -0,0 +1,32 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// The generated model has to match the vendored spec, so a hand edit to either one that
+// skipped go generate gets caught here
+func TestModelIsUpToDate(t *testing.T) {
+	out := t.TempDir()
+	if err := generate("../../openapi/SpaceTraders.json", out); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range []string{"models.go", "endpoints.go"} {
+		want, err := os.ReadFile(filepath.Join(out, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := os.ReadFile(filepath.Join("../../model", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(got) != string(want) {
+			t.Errorf("model/%s is out of date with openapi/SpaceTraders.json, run go generate", file)
+		}
+	}
+}
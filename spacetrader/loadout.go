@@ -0,0 +1,208 @@
+package spacetrader
+
+import (
+	"fmt"
+
+	"example.com/spacetrader/model"
+)
+
+type (
+	// ShipModificationTransaction is the shipyard's bill for installing or removing a part
+	ShipModificationTransaction = model.ShipModificationTransaction
+
+	// MountChange is what comes back after installing or removing a mount
+	MountChange = model.MountChange
+
+	// ModuleChange is what comes back after installing or removing a module
+	ModuleChange = model.ModuleChange
+)
+
+// The API doesn't tell us what a mount or module needs until it's installed, so
+// these mirror the game's published requirements for the parts that show up in cargo
+var MountRequirements = map[TradeSymbol]ShipRequirements{
+	TradeMountGasSiphonI:       {Power: 1, Crew: 0},
+	TradeMountGasSiphonII:      {Power: 2, Crew: 2},
+	TradeMountGasSiphonIII:     {Power: 3, Crew: 5},
+	TradeMountSurveyorI:        {Power: 1, Crew: 0},
+	TradeMountSurveyorII:       {Power: 2, Crew: 3},
+	TradeMountSurveyorIII:      {Power: 3, Crew: 5},
+	TradeMountSensorArrayI:     {Power: 1, Crew: 0},
+	TradeMountSensorArrayII:    {Power: 2, Crew: 2},
+	TradeMountSensorArrayIII:   {Power: 4, Crew: 5},
+	TradeMountMiningLaserI:     {Power: 1, Crew: 0},
+	TradeMountMiningLaserII:    {Power: 2, Crew: 2},
+	TradeMountMiningLaserIII:   {Power: 2, Crew: 5},
+	TradeMountLaserCannonI:     {Power: 2, Crew: 1},
+	TradeMountMissileLauncherI: {Power: 1, Crew: 2},
+	TradeMountTurretI:          {Power: 1, Crew: 1},
+}
+
+// ModuleRequirements is the same idea for modules, which also eat up module slots
+var ModuleRequirements = map[TradeSymbol]ShipRequirements{
+	TradeModuleMineralProcessorI: {Power: 1, Crew: 0, Slots: 2},
+	TradeModuleGasProcessorI:     {Power: 1, Crew: 0, Slots: 2},
+	TradeModuleCargoHoldI:        {Power: 1, Crew: 0, Slots: 1},
+	TradeModuleCargoHoldII:       {Power: 2, Crew: 2, Slots: 2},
+	TradeModuleCargoHoldIII:      {Power: 3, Crew: 5, Slots: 3},
+	TradeModuleCrewQuartersI:     {Power: 1, Crew: 2, Slots: 1},
+	TradeModuleEnvoyQuartersI:    {Power: 1, Crew: 2, Slots: 1},
+	TradeModulePassengerCabinI:   {Power: 1, Crew: 2, Slots: 1},
+	TradeModuleMicroRefineryI:    {Power: 1, Crew: 0, Slots: 1},
+	TradeModuleScienceLabI:       {Power: 1, Crew: 0, Slots: 2},
+	TradeModuleJumpDriveI:        {Power: 4, Crew: 0, Slots: 1},
+	TradeModuleJumpDriveII:       {Power: 6, Crew: 8, Slots: 2},
+	TradeModuleJumpDriveIII:      {Power: 8, Crew: 15, Slots: 3},
+	TradeModuleWarpDriveI:        {Power: 3, Crew: 0, Slots: 1},
+	TradeModuleWarpDriveII:       {Power: 5, Crew: 2, Slots: 2},
+	TradeModuleWarpDriveIII:      {Power: 7, Crew: 5, Slots: 3},
+	TradeModuleShieldGeneratorI:  {Power: 3, Crew: 0, Slots: 1},
+	TradeModuleShieldGeneratorII: {Power: 4, Crew: 2, Slots: 2},
+	TradeModuleOreRefineryI:      {Power: 1, Crew: 0, Slots: 1},
+	TradeModuleFuelRefineryI:     {Power: 1, Crew: 0, Slots: 1},
+}
+
+// powerUsed adds up how much of the reactor's output the ship is already drawing
+func powerUsed(ship Ship) int {
+	used := ship.Frame.Requirements.Power + ship.Engine.Requirements.Power
+	for _, mount := range ship.Mounts {
+		used += mount.Requirements.Power
+	}
+	for _, module := range ship.Modules {
+		used += module.Requirements.Power
+	}
+
+	return used
+}
+
+// checkRequirements makes sure the ship has the power and crew to run one more part
+func checkRequirements(ship Ship, symbol TradeSymbol, needs ShipRequirements) error {
+	if spare := ship.Reactor.PowerOutput - powerUsed(ship); needs.Power > spare {
+		return fmt.Errorf("%s needs %d power but %s only has %d to spare", symbol, needs.Power, ship.Symbol, spare)
+	}
+
+	if spare := ship.Crew.Capacity - ship.Crew.Required; needs.Crew > spare {
+		return fmt.Errorf("%s needs %d crew but %s only has room for %d more", symbol, needs.Crew, ship.Symbol, spare)
+	}
+
+	return nil
+}
+
+// CanInstallMount checks slots, power and crew locally so we don't waste a request on a mount that won't fit
+func CanInstallMount(ship Ship, symbol TradeSymbol) error {
+	if !symbol.IsMount() {
+		return fmt.Errorf("%s is not a mount", symbol)
+	}
+
+	needs, known := MountRequirements[symbol]
+	if !known {
+		return fmt.Errorf("requirements for %s are unknown", symbol)
+	}
+
+	if len(ship.Mounts) >= ship.Frame.MountingPoints {
+		return fmt.Errorf("%s has no free mounting points", ship.Symbol)
+	}
+
+	return checkRequirements(ship, symbol, needs)
+}
+
+// CanInstallModule checks slots, power and crew locally so we don't waste a request on a module that won't fit
+func CanInstallModule(ship Ship, symbol TradeSymbol) error {
+	if !symbol.IsModule() {
+		return fmt.Errorf("%s is not a module", symbol)
+	}
+
+	needs, known := ModuleRequirements[symbol]
+	if !known {
+		return fmt.Errorf("requirements for %s are unknown", symbol)
+	}
+
+	slotsUsed := 0
+	for _, module := range ship.Modules {
+		slotsUsed += module.Requirements.Slots
+	}
+	if spare := ship.Frame.ModuleSlots - slotsUsed; needs.Slots > spare {
+		return fmt.Errorf("%s needs %d module slots but %s only has %d free", symbol, needs.Slots, ship.Symbol, spare)
+	}
+
+	return checkRequirements(ship, symbol, needs)
+}
+
+func GetMounts(token string, shipSymbol string) ([]ShipMount, error) {
+	mounts, err := model.GetMounts(NewClient(token), shipSymbol)
+
+	if err != nil {
+		return []ShipMount{}, err
+	}
+
+	return mounts, nil
+}
+
+// InstallMount takes a mount out of the ship's cargo and bolts it on, the ship has to be docked at a shipyard
+func InstallMount(token string, shipSymbol string, mountSymbol TradeSymbol) (MountChange, error) {
+	if !mountSymbol.IsMount() {
+		return MountChange{}, fmt.Errorf("%s is not a mount", mountSymbol)
+	}
+
+	change, err := model.InstallMount(NewClient(token), shipSymbol, model.InstallMountRequest{Symbol: mountSymbol})
+
+	if err != nil {
+		return MountChange{}, err
+	}
+
+	return change, nil
+}
+
+// RemoveMount pulls a mount off the ship and drops it into cargo, the ship has to be docked at a shipyard
+func RemoveMount(token string, shipSymbol string, mountSymbol TradeSymbol) (MountChange, error) {
+	if !mountSymbol.IsMount() {
+		return MountChange{}, fmt.Errorf("%s is not a mount", mountSymbol)
+	}
+
+	change, err := model.RemoveMount(NewClient(token), shipSymbol, model.RemoveMountRequest{Symbol: mountSymbol})
+
+	if err != nil {
+		return MountChange{}, err
+	}
+
+	return change, nil
+}
+
+func GetModules(token string, shipSymbol string) ([]ShipModule, error) {
+	modules, err := model.GetShipModules(NewClient(token), shipSymbol)
+
+	if err != nil {
+		return []ShipModule{}, err
+	}
+
+	return modules, nil
+}
+
+// InstallModule takes a module out of the ship's cargo and fits it, the ship has to be docked at a shipyard
+func InstallModule(token string, shipSymbol string, moduleSymbol TradeSymbol) (ModuleChange, error) {
+	if !moduleSymbol.IsModule() {
+		return ModuleChange{}, fmt.Errorf("%s is not a module", moduleSymbol)
+	}
+
+	change, err := model.InstallShipModule(NewClient(token), shipSymbol, model.InstallShipModuleRequest{Symbol: moduleSymbol})
+
+	if err != nil {
+		return ModuleChange{}, err
+	}
+
+	return change, nil
+}
+
+// RemoveModule pulls a module out of the ship and drops it into cargo, the ship has to be docked at a shipyard
+func RemoveModule(token string, shipSymbol string, moduleSymbol TradeSymbol) (ModuleChange, error) {
+	if !moduleSymbol.IsModule() {
+		return ModuleChange{}, fmt.Errorf("%s is not a module", moduleSymbol)
+	}
+
+	change, err := model.RemoveShipModule(NewClient(token), shipSymbol, model.RemoveShipModuleRequest{Symbol: moduleSymbol})
+
+	if err != nil {
+		return ModuleChange{}, err
+	}
+
+	return change, nil
+}
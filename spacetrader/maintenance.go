@@ -0,0 +1,62 @@
+package spacetrader
+
+import (
+	"example.com/spacetrader/model"
+)
+
+type (
+	// MaintenanceTransaction is the shipyard's quote or bill for repairing or scrapping a ship.
+	// The spec has a scrap transaction of its own, it's an alias of the repair one.
+	MaintenanceTransaction = model.RepairTransaction
+
+	// ShipRepair is what comes back once a ship has been patched up
+	ShipRepair = model.ShipRepair
+
+	// ShipScrap is what comes back once a ship has been sold for parts
+	ShipScrap = model.ShipScrap
+)
+
+// GetRepairShip asks the shipyard what it would cost to fix the ship up, without spending anything
+func GetRepairShip(token string, shipSymbol string) (MaintenanceTransaction, error) {
+	// The quote comes wrapped up under transaction, same as the bill
+	quote, err := model.GetRepairShip(NewClient(token), shipSymbol)
+
+	if err != nil {
+		return MaintenanceTransaction{}, err
+	}
+
+	return quote.Transaction, nil
+}
+
+// RepairShip brings the frame, reactor and engine back to full condition, the ship has to be docked at a shipyard
+func RepairShip(token string, shipSymbol string) (ShipRepair, error) {
+	repair, err := model.RepairShip(NewClient(token), shipSymbol)
+
+	if err != nil {
+		return ShipRepair{}, err
+	}
+
+	return repair, nil
+}
+
+// GetScrapShip asks the shipyard what it would pay for the ship, without giving it up
+func GetScrapShip(token string, shipSymbol string) (MaintenanceTransaction, error) {
+	quote, err := model.GetScrapShip(NewClient(token), shipSymbol)
+
+	if err != nil {
+		return MaintenanceTransaction{}, err
+	}
+
+	return quote.Transaction, nil
+}
+
+// ScrapShip sells the ship off for parts. There's no undo, the ship is gone for good.
+func ScrapShip(token string, shipSymbol string) (ShipScrap, error) {
+	scrap, err := model.ScrapShip(NewClient(token), shipSymbol)
+
+	if err != nil {
+		return ShipScrap{}, err
+	}
+
+	return scrap, nil
+}
@@ -0,0 +1,62 @@
+package spacetrader
+
+import (
+	"example.com/spacetrader/model"
+)
+
+// Markets come straight from the generated models
+type (
+	Market            = model.Market
+	MarketGood        = model.TradeGood
+	MarketTransaction = model.MarketTransaction
+
+	// MarketTradeGood is a good's going rate. These only show up when one of our ships is at the market.
+	MarketTradeGood = model.MarketTradeGood
+)
+
+// What a market does with a good, how much of it there is and what happened to it are all
+// enums in the spec
+type (
+	MarketTradeGoodType     = model.MarketTradeGoodType
+	MarketTradeGoodSupply   = model.MarketTradeGoodSupply
+	MarketTradeGoodActivity = model.MarketTradeGoodActivity
+	MarketTransactionType   = model.MarketTransactionType
+)
+
+func GetMarket(token string, systemSymbol string, waypointSymbol string) (Market, error) {
+	market, err := model.GetMarket(NewClient(token), systemSymbol, waypointSymbol)
+
+	if err != nil {
+		return Market{}, err
+	}
+
+	return market, nil
+}
+
+// CargoTrade is what comes back after buying or selling cargo
+type CargoTrade = model.CargoTrade
+
+// SellCargo sells units of a good from the hold to the market the ship is docked at. A market
+// only takes up to its trade volume in one go.
+func SellCargo(token string, shipSymbol string, tradeSymbol TradeSymbol, units int) (CargoTrade, error) {
+	order := model.SellCargoRequest{Symbol: tradeSymbol, Units: units}
+	trade, err := model.SellCargo(NewClient(token), shipSymbol, order)
+
+	if err != nil {
+		return CargoTrade{}, err
+	}
+
+	return trade, nil
+}
+
+// PurchaseCargo buys units of a good from the market the ship is docked at, up to its trade volume
+func PurchaseCargo(token string, shipSymbol string, tradeSymbol TradeSymbol, units int) (CargoTrade, error) {
+	order := model.PurchaseCargoRequest{Symbol: tradeSymbol, Units: units}
+	trade, err := model.PurchaseCargo(NewClient(token), shipSymbol, order)
+
+	if err != nil {
+		return CargoTrade{}, err
+	}
+
+	return trade, nil
+}
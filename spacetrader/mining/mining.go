@@ -0,0 +1,772 @@
+// Package mining runs ships back and forth between an asteroid and the markets around it. Each
+// round the ship surveys if it can, extracts until the hold is full, throwing away anything no
+// market in the system buys, then flies to the market that pays best for the load, sells it,
+// refuels and heads back for more.
+//
+// Travel goes through the autopilot, so a ship's trips show up alongside any other journey:
+//
+//	miner := mining.New(api, pilot, prices)
+//	miner.Start("SHIP-1", "X1-TEST-B7")
+//	status, _ := miner.Status("SHIP-1")
+//
+// Given a queue, Start puts the mining in it rather than running it straight off, so it carries on
+// after a restart and gets retried if it fails.
+package mining
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"example.com/spacetrader"
+	"example.com/spacetrader/autopilot"
+	"example.com/spacetrader/history"
+	"example.com/spacetrader/queue"
+)
+
+// Step is what the ship is busy with right now
+type Step string
+
+const (
+	StepStarting    Step = "STARTING"
+	StepTravelling  Step = "TRAVELLING"
+	StepSurveying   Step = "SURVEYING"
+	StepExtracting  Step = "EXTRACTING"
+	StepCoolingDown Step = "COOLING_DOWN"
+	StepJettisoning Step = "JETTISONING"
+	StepSelling     Step = "SELLING"
+	StepRefuelling  Step = "REFUELLING"
+	StepStopped     Step = "STOPPED"
+	StepFailed      Step = "FAILED"
+)
+
+// ErrStopped is what Mine hands back when the ship was stopped with Stop rather than failing
+// or having its context cancelled
+var ErrStopped = errors.New("stopped")
+
+// ErrNotMining is what Stop hands back when there was nothing to stop
+var ErrNotMining = errors.New("isn't mining")
+
+// Running is false once the loop has stopped or given up
+func (s Step) Running() bool {
+	return s != StepStopped && s != StepFailed
+}
+
+// Status is how a ship's mining is going
+type Status struct {
+	ShipSymbol string
+	Asteroid   string
+	Step       Step
+	Detail     string
+	// Loads is how many holds have been sold, Extracted counts every unit dug up, thrown away or not
+	Loads     int
+	Extracted int
+	Earned    int
+	Error     string
+	Started   time.Time
+	Updated   time.Time
+}
+
+// Miner runs the mining loop, one goroutine per ship
+type Miner struct {
+	// Now is the clock cooldowns are checked against
+	Now func() time.Time
+	// Slack is added on to every cooldown so the reactor is definitely ready when we try again
+	Slack time.Duration
+	// Poll is how often to check on the autopilot while the ship's flying somewhere
+	Poll time.Duration
+
+	api    spacetrader.API
+	pilot  *autopilot.Pilot
+	prices *history.Store
+	tasks  *queue.Queue
+
+	mu       sync.Mutex
+	statuses map[string]*Status
+	cancels  map[string]context.CancelCauseFunc
+	running  sync.WaitGroup
+}
+
+// New sets up a miner. Prices picks where to sell, without any on record the market taking
+// the most of the load wins.
+func New(api spacetrader.API, pilot *autopilot.Pilot, prices *history.Store) *Miner {
+	return &Miner{
+		Now:      time.Now,
+		Slack:    time.Second,
+		Poll:     2 * time.Second,
+		api:      api,
+		pilot:    pilot,
+		prices:   prices,
+		statuses: map[string]*Status{},
+		cancels:  map[string]context.CancelCauseFunc{},
+	}
+}
+
+// TaskKind is what mining started with Start is queued as
+const TaskKind = "mining"
+
+// job is a mining task's payload
+type job struct {
+	ShipSymbol string `json:"shipSymbol"`
+	Asteroid   string `json:"asteroid"`
+}
+
+// Queue has Start put mining in tasks from now on, rather than running it straight off
+func (m *Miner) Queue(tasks *queue.Queue) {
+	m.tasks = tasks
+	tasks.Handle(TaskKind, m.work)
+}
+
+// Start sends a ship off to mine asteroid in the background, leave asteroid empty to mine
+// where the ship already is
+func (m *Miner) Start(shipSymbol string, asteroid string) error {
+	if m.tasks != nil {
+		return m.enqueue(shipSymbol, asteroid)
+	}
+
+	ctx, status, err := m.begin(context.Background(), shipSymbol, asteroid)
+	if err != nil {
+		return err
+	}
+
+	m.running.Add(1)
+	go func() {
+		defer m.running.Done()
+
+		m.finish(ctx, status, m.run(ctx, shipSymbol, status.Asteroid))
+	}()
+
+	return nil
+}
+
+// enqueue is Start with a queue, the ship's checked over now so a hopeless job isn't queued
+func (m *Miner) enqueue(shipSymbol string, asteroid string) error {
+	asteroid, err := m.check(shipSymbol, asteroid)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	err = m.busy(shipSymbol)
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	task, err := queue.NewTask(TaskKind, key(shipSymbol), job{ShipSymbol: shipSymbol, Asteroid: asteroid})
+	if err != nil {
+		return err
+	}
+	task.MaxAttempts = 5
+
+	queued, added, err := m.tasks.Enqueue(task)
+	if err != nil {
+		return err
+	}
+	if !added {
+		return fmt.Errorf("%s is already down to mine, since %s", shipSymbol, queued.Created.Local().Format(time.TimeOnly))
+	}
+
+	return nil
+}
+
+// work is the queue's handler for mining tasks
+func (m *Miner) work(ctx context.Context, task queue.Task) error {
+	var j job
+	if err := task.Decode(&j); err != nil {
+		return err
+	}
+
+	// A trip the ship was on when the server went down gets flown out by the autopilot first,
+	// mining picks up from wherever it lands
+	if err := m.settle(ctx, j.ShipSymbol); err != nil {
+		return err
+	}
+
+	err := m.Mine(ctx, j.ShipSymbol, j.Asteroid)
+
+	// Stopped from the ship page rather than failed, the job's done with
+	if errors.Is(err, ErrStopped) {
+		return nil
+	}
+
+	return err
+}
+
+// settle waits for any journey the ship's on to finish
+func (m *Miner) settle(ctx context.Context, shipSymbol string) error {
+	ticker := time.NewTicker(m.Poll)
+	defer ticker.Stop()
+
+	for {
+		journey, ok := m.pilot.Journey(shipSymbol)
+		if !ok || journey.Status != autopilot.StatusRunning {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Mine is Start for callers with a goroutine of their own to run the loop on. It only comes
+// back once the mining has stopped, been cancelled through parent or failed, and says which:
+// ErrStopped, parent's error or what went wrong.
+func (m *Miner) Mine(parent context.Context, shipSymbol string, asteroid string) error {
+	ctx, status, err := m.begin(parent, shipSymbol, asteroid)
+	if err != nil {
+		return err
+	}
+
+	m.running.Add(1)
+	defer m.running.Done()
+
+	// Whoever's running the loop gets the panic, but the ship mustn't be left looking busy
+	defer func() {
+		if r := recover(); r != nil {
+			m.finish(ctx, status, fmt.Errorf("panicked: %v", r))
+			panic(r)
+		}
+	}()
+
+	err = m.run(ctx, shipSymbol, status.Asteroid)
+	m.finish(ctx, status, err)
+
+	switch {
+	case parent.Err() != nil:
+		return parent.Err()
+	case errors.Is(context.Cause(ctx), ErrStopped):
+		return ErrStopped
+	}
+
+	return err
+}
+
+// begin checks the ship can mine asteroid and marks it as mining, handing back the context
+// Stop cancels and the run's status
+func (m *Miner) begin(parent context.Context, shipSymbol string, asteroid string) (context.Context, *Status, error) {
+	asteroid, err := m.check(shipSymbol, asteroid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.busy(shipSymbol); err != nil {
+		return nil, nil, err
+	}
+
+	now := m.Now()
+	status := &Status{ShipSymbol: shipSymbol, Asteroid: asteroid, Step: StepStarting, Started: now, Updated: now}
+	m.statuses[shipSymbol] = status
+
+	ctx, cancel := context.WithCancelCause(parent)
+	m.cancels[shipSymbol] = cancel
+
+	return ctx, status, nil
+}
+
+// check makes sure the ship has a laser and asteroid is one it can reach, handing back the
+// asteroid it'll mine
+func (m *Miner) check(shipSymbol string, asteroid string) (string, error) {
+	ship, err := m.api.GetShip(shipSymbol)
+	if err != nil {
+		return "", err
+	}
+
+	if !spacetrader.CanMine(ship) {
+		return "", fmt.Errorf("%s doesn't have a mining laser", shipSymbol)
+	}
+
+	if asteroid == "" {
+		asteroid = ship.Nav.WaypointSymbol
+	}
+	if spacetrader.SystemSymbol(asteroid) != ship.Nav.SystemSymbol {
+		return "", fmt.Errorf("%s is in %s, it can only mine in its own system", shipSymbol, ship.Nav.SystemSymbol)
+	}
+
+	waypoint, err := m.api.GetWaypoint(ship.Nav.SystemSymbol, asteroid)
+	if err != nil {
+		return "", err
+	}
+	if !spacetrader.Minable(waypoint.Type) {
+		return "", fmt.Errorf("%s isn't an asteroid", asteroid)
+	}
+
+	return asteroid, nil
+}
+
+// busy says why the ship can't start mining if it's already doing something, m.mu must be held
+func (m *Miner) busy(shipSymbol string) error {
+	if status, ok := m.statuses[shipSymbol]; ok && status.Step.Running() {
+		return fmt.Errorf("%s is already mining %s", shipSymbol, status.Asteroid)
+	}
+
+	if journey, ok := m.pilot.Journey(shipSymbol); ok && journey.Status == autopilot.StatusRunning {
+		return fmt.Errorf("%s is on autopilot to %s, cancel that first", shipSymbol, journey.Destination())
+	}
+
+	return nil
+}
+
+// finish records how a run of the loop ended
+func (m *Miner) finish(ctx context.Context, status *Status, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// A newer run has taken over the ship since this one was stopped
+	if m.statuses[status.ShipSymbol] != status {
+		return
+	}
+
+	// Asked before the run's context is let go of below, which cancels it too
+	cancelled := ctx.Err() != nil
+
+	if cancel, ok := m.cancels[status.ShipSymbol]; ok {
+		cancel(nil)
+		delete(m.cancels, status.ShipSymbol)
+	}
+
+	if !status.Step.Running() {
+		// Stopping has already recorded what happened
+		return
+	}
+
+	status.Step, status.Detail, status.Updated = StepFailed, "", m.Now()
+	if cancelled {
+		// Cancelled from outside, nothing went wrong
+		status.Step = StepStopped
+		return
+	}
+	status.Error = err.Error()
+}
+
+// Stop ends a ship's mining. If it's flying somewhere the autopilot lets it finish the current hop.
+func (m *Miner) Stop(shipSymbol string) error {
+	if m.stop(shipSymbol) {
+		return nil
+	}
+
+	// Nothing's running, but it might be queued up to try again
+	if m.tasks != nil {
+		if cancelled, err := m.tasks.Cancel(context.Background(), key(shipSymbol)); cancelled || err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%s %w", shipSymbol, ErrNotMining)
+}
+
+// stop cancels the ship's loop, it's false if there wasn't one running
+func (m *Miner) stop(shipSymbol string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status, ok := m.statuses[shipSymbol]
+	if !ok || !status.Step.Running() {
+		return false
+	}
+
+	if cancel, ok := m.cancels[shipSymbol]; ok {
+		cancel(ErrStopped)
+		delete(m.cancels, shipSymbol)
+	}
+
+	if status.Step == StepTravelling {
+		m.pilot.Cancel(shipSymbol)
+	}
+
+	status.Step, status.Detail, status.Updated = StepStopped, "", m.Now()
+
+	return true
+}
+
+// Status hands back a copy of where the ship's mining is up to
+func (m *Miner) Status(shipSymbol string) (Status, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status, ok := m.statuses[shipSymbol]
+	if !ok {
+		return Status{}, false
+	}
+
+	return *status, true
+}
+
+// Shutdown stops every ship and waits for the loops to wind down
+func (m *Miner) Shutdown() {
+	m.mu.Lock()
+	for shipSymbol, cancel := range m.cancels {
+		cancel(nil)
+		delete(m.cancels, shipSymbol)
+		m.statuses[shipSymbol].Step = StepStopped
+	}
+	m.mu.Unlock()
+
+	m.running.Wait()
+}
+
+// report updates what the ship is doing, unless it's been stopped meanwhile
+func (m *Miner) report(ctx context.Context, shipSymbol string, step Step, detail string) {
+	m.tally(ctx, shipSymbol, func(status *Status) {
+		status.Step, status.Detail = step, detail
+	})
+}
+
+// tally changes the ship's status under the lock
+func (m *Miner) tally(ctx context.Context, shipSymbol string, change func(*Status)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	status := m.statuses[shipSymbol]
+	change(status)
+	status.Updated = m.Now()
+}
+
+// market is one of the system's marketplaces and where it is
+type market struct {
+	spacetrader.Market
+	waypoint spacetrader.Waypoint
+}
+
+// buys reports whether the market takes a good at all
+func (m market) buys(symbol spacetrader.TradeSymbol) bool {
+	for _, goods := range [][]spacetrader.MarketGood{m.Imports, m.Exports, m.Exchange} {
+		for _, good := range goods {
+			if good.Symbol == symbol {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// run is the mining loop itself. It looks at the ship afresh every time round, so nothing
+// it does depends on remembering what happened last time except the survey in hand.
+func (m *Miner) run(ctx context.Context, shipSymbol string, asteroid string) error {
+	ship, err := m.api.GetShip(shipSymbol)
+	if err != nil {
+		return err
+	}
+
+	// The system's markets don't move, so they only need looking up once
+	waypoints, err := spacetrader.AllWaypoints(m.api, ship.Nav.SystemSymbol, spacetrader.WaypointQuery{})
+	if err != nil {
+		return err
+	}
+
+	markets := []market{}
+	for _, waypoint := range waypoints {
+		if !waypoint.HasTrait(spacetrader.WaypointTraitMarketplace) {
+			continue
+		}
+
+		found, err := m.api.GetMarket(ship.Nav.SystemSymbol, waypoint.Symbol)
+		if err != nil {
+			return err
+		}
+		markets = append(markets, market{Market: found, waypoint: waypoint})
+	}
+	if len(markets) == 0 {
+		return fmt.Errorf("there's nowhere in %s to sell", ship.Nav.SystemSymbol)
+	}
+
+	fuelStations, err := spacetrader.FindFuelStations(m.api, waypoints)
+	if err != nil {
+		return err
+	}
+
+	wanted := func(symbol spacetrader.TradeSymbol) bool {
+		for _, market := range markets {
+			if market.buys(symbol) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var survey *spacetrader.Survey
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		ship, err := m.api.GetShip(shipSymbol)
+		if err != nil {
+			return err
+		}
+
+		if ship.Cargo.Units >= ship.Cargo.Capacity {
+			if err := m.sell(ctx, ship, waypoints, markets, fuelStations); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if ship.Nav.WaypointSymbol != asteroid || ship.Nav.Status == spacetrader.ShipNavStatusInTransit {
+			if err := m.travel(ctx, ship, waypoints, fuelStations, asteroid); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if ship.Nav.Status == spacetrader.ShipNavStatusDocked {
+			if _, err := m.api.LaunchToOrbit(shipSymbol); err != nil {
+				return err
+			}
+		}
+
+		if err := m.cooldown(ctx, shipSymbol, ship.Cooldown); err != nil {
+			return err
+		}
+
+		if survey == nil && spacetrader.CanSurvey(ship) {
+			m.report(ctx, shipSymbol, StepSurveying, fmt.Sprintf("Surveying %s", asteroid))
+
+			surveyed, err := m.api.CreateSurvey(shipSymbol)
+			if cooldown, ok := spacetrader.CooldownOf(err); ok {
+				if err := m.cooldown(ctx, shipSymbol, cooldown); err != nil {
+					return err
+				}
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("surveying %s: %w", asteroid, err)
+			}
+
+			survey = bestSurvey(surveyed.Surveys, wanted)
+
+			// Surveying heats the reactor up just like extracting does
+			if err := m.cooldown(ctx, shipSymbol, surveyed.Cooldown); err != nil {
+				return err
+			}
+		}
+
+		m.report(ctx, shipSymbol, StepExtracting, fmt.Sprintf("Extracting at %s, hold %d/%d", asteroid, ship.Cargo.Units, ship.Cargo.Capacity))
+
+		extract, err := m.api.ExtractResources(shipSymbol, survey)
+		if cooldown, ok := spacetrader.CooldownOf(err); ok {
+			if err := m.cooldown(ctx, shipSymbol, cooldown); err != nil {
+				return err
+			}
+			continue
+		}
+		switch {
+		case spacetrader.HasCode(err, spacetrader.ErrCodeSurveyExpired, spacetrader.ErrCodeSurveyExhausted, spacetrader.ErrCodeSurveyVerification):
+			// Surveys wear out, get a new one next time round
+			survey = nil
+			continue
+		case spacetrader.HasCode(err, spacetrader.ErrCodeCargoFull):
+			// The hold filled up some other way, the next round sells it
+			continue
+		case err != nil:
+			return fmt.Errorf("extracting at %s: %w", asteroid, err)
+		}
+
+		yield := extract.Extraction.Yield
+		m.tally(ctx, shipSymbol, func(status *Status) { status.Extracted += yield.Units })
+
+		if !wanted(yield.Symbol) {
+			m.report(ctx, shipSymbol, StepJettisoning, fmt.Sprintf("Jettisoning %d %s, nobody here buys it", yield.Units, yield.Symbol))
+			if _, err := m.api.JettisonCargo(shipSymbol, yield.Symbol, yield.Units); err != nil {
+				return fmt.Errorf("jettisoning %s: %w", yield.Symbol, err)
+			}
+		}
+
+		if err := m.cooldown(ctx, shipSymbol, extract.Cooldown); err != nil {
+			return err
+		}
+	}
+}
+
+// sell takes a full hold to the best market for it, sells what it can there and tops up the tank
+func (m *Miner) sell(ctx context.Context, ship spacetrader.Ship, waypoints []spacetrader.Waypoint, markets []market, fuelStations map[string]int) error {
+	best := m.bestMarket(ship, markets)
+	if err := m.travel(ctx, ship, waypoints, fuelStations, best.Symbol); err != nil {
+		return err
+	}
+
+	ship, err := m.api.GetShip(ship.Symbol)
+	if err != nil {
+		return err
+	}
+	if ship.Nav.Status != spacetrader.ShipNavStatusDocked {
+		if _, err := m.api.DockShip(ship.Symbol); err != nil {
+			return err
+		}
+	}
+
+	// With a ship there the market posts its prices and trade volumes
+	live, err := m.api.GetMarket(ship.Nav.SystemSymbol, best.Symbol)
+	if err != nil {
+		return err
+	}
+
+	sold := 0
+	for _, cargo := range ship.Cargo.Inventory {
+		good, ok := live.TradeGood(cargo.Symbol)
+		if !ok {
+			continue
+		}
+
+		for units := cargo.Units; units > 0; {
+			lot := min(units, max(1, good.TradeVolume))
+			m.report(ctx, ship.Symbol, StepSelling, fmt.Sprintf("Selling %d %s at %s", lot, cargo.Symbol, best.Symbol))
+
+			trade, err := m.api.SellCargo(ship.Symbol, cargo.Symbol, lot)
+			if spacetrader.HasCode(err, spacetrader.ErrCodeTradeNotSold) {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("selling %s: %w", cargo.Symbol, err)
+			}
+
+			m.tally(ctx, ship.Symbol, func(status *Status) { status.Earned += trade.Transaction.TotalPrice })
+			units -= lot
+			sold += lot
+		}
+	}
+
+	if sold == 0 {
+		return fmt.Errorf("%s wouldn't buy any of the load", best.Symbol)
+	}
+	m.tally(ctx, ship.Symbol, func(status *Status) { status.Loads++ })
+
+	if _, ok := live.TradeGood(spacetrader.TradeFuel); ok && ship.Fuel.Current < ship.Fuel.Capacity {
+		m.report(ctx, ship.Symbol, StepRefuelling, fmt.Sprintf("Refuelling at %s", best.Symbol))
+
+		// Running short of credits isn't the end of the world, the autopilot refuels on the way if it has to
+		_, err := m.api.RefuelShip(ship.Symbol, 0)
+		if err != nil && !spacetrader.HasCode(err, spacetrader.ErrCodeNotEnoughCredits) {
+			return fmt.Errorf("refuelling: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// bestMarket picks where to sell the hold: whoever pays most for it once the fuel to get there
+// is taken off, going by the prices on record. Markets with no prices yet are judged on how
+// much of the load they take, and the nearest wins a tie.
+func (m *Miner) bestMarket(ship spacetrader.Ship, markets []market) market {
+	here, _ := m.api.GetWaypoint(ship.Nav.SystemSymbol, ship.Nav.WaypointSymbol)
+
+	fuelPrice := 0.0
+	for _, market := range markets {
+		if observation, ok := m.prices.Latest(market.Symbol, spacetrader.TradeFuel); ok && (fuelPrice == 0 || float64(observation.PurchasePrice)/100 < fuelPrice) {
+			fuelPrice = float64(observation.PurchasePrice) / 100
+		}
+	}
+
+	type score struct {
+		value    int
+		units    int
+		distance float64
+	}
+	scores := make([]score, len(markets))
+	for i, market := range markets {
+		scores[i].distance = spacetrader.Distance(here.PosX, here.PosY, market.waypoint.PosX, market.waypoint.PosY)
+		for _, cargo := range ship.Cargo.Inventory {
+			if !market.buys(cargo.Symbol) {
+				continue
+			}
+			scores[i].units += cargo.Units
+			if observation, ok := m.prices.Latest(market.Symbol, cargo.Symbol); ok {
+				scores[i].value += cargo.Units * observation.SellPrice
+			}
+		}
+		if ship.Fuel.Capacity > 0 {
+			scores[i].value -= int(math.Ceil(float64(spacetrader.FuelCost(spacetrader.FlightModeCruise, scores[i].distance)) * fuelPrice))
+		}
+	}
+
+	order := make([]int, len(markets))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := scores[order[i]], scores[order[j]]
+		if a.value != b.value {
+			return a.value > b.value
+		}
+		if a.units != b.units {
+			return a.units > b.units
+		}
+		return a.distance < b.distance
+	})
+
+	return markets[order[0]]
+}
+
+// travel flies the ship to destination on the autopilot and waits for it to get there
+func (m *Miner) travel(ctx context.Context, ship spacetrader.Ship, waypoints []spacetrader.Waypoint, fuelStations map[string]int, destination string) error {
+	m.report(ctx, ship.Symbol, StepTravelling, fmt.Sprintf("Flying to %s", destination))
+
+	return m.pilot.Fly(ctx, ship, waypoints, fuelStations, destination)
+}
+
+// cooldown sleeps until the ship's reactor is ready again
+func (m *Miner) cooldown(ctx context.Context, shipSymbol string, cooldown spacetrader.Cooldown) error {
+	if cooldown.RemainingSeconds <= 0 {
+		return nil
+	}
+
+	expiration, err := time.Parse(time.RFC3339Nano, cooldown.Expiration)
+	if err != nil {
+		return fmt.Errorf("%s has a strange cooldown expiry %q: %w", shipSymbol, cooldown.Expiration, err)
+	}
+
+	m.report(ctx, shipSymbol, StepCoolingDown, fmt.Sprintf("Cooling down until %s", expiration.Local().Format(time.TimeOnly)))
+
+	timer := time.NewTimer(expiration.Sub(m.Now()) + m.Slack)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// key is the ship's mining task key
+func key(shipSymbol string) string {
+	return TaskKind + ":" + shipSymbol
+}
+
+// bestSurvey picks the survey with the most deposits worth selling, nil when none of them are
+// worth aiming for. Bigger deposits last longer so they win a tie.
+func bestSurvey(surveys []spacetrader.Survey, wanted func(spacetrader.TradeSymbol) bool) *spacetrader.Survey {
+	sizes := map[spacetrader.SurveySize]int{spacetrader.SurveySizeSmall: 1, spacetrader.SurveySizeModerate: 2, spacetrader.SurveySizeLarge: 3}
+
+	var best *spacetrader.Survey
+	bestCount := 0
+	for i, survey := range surveys {
+		count := 0
+		for _, deposit := range survey.Deposits {
+			if wanted(deposit.Symbol) {
+				count++
+			}
+		}
+
+		if count > bestCount || (count == bestCount && count > 0 && sizes[survey.Size] > sizes[best.Size]) {
+			best, bestCount = &surveys[i], count
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,403 @@
+package mining_test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"example.com/spacetrader"
+	"example.com/spacetrader/autopilot"
+	"example.com/spacetrader/history"
+	"example.com/spacetrader/mining"
+)
+
+// X1-M has an asteroid field, a market next door that takes both ores and a hub further out
+// that only takes iron. Ships get wherever they're sent the moment they ask.
+var belt = []spacetrader.Waypoint{
+	{Symbol: "X1-M-AST", SystemSymbol: "X1-M", Type: spacetrader.WaypointTypeAsteroidField, PosX: 0, PosY: 0},
+	{Symbol: "X1-M-MKT", SystemSymbol: "X1-M", Type: spacetrader.WaypointTypePlanet, PosX: 10, PosY: 0, Traits: []spacetrader.Trait{{Symbol: spacetrader.WaypointTraitMarketplace}}},
+	{Symbol: "X1-M-HUB", SystemSymbol: "X1-M", Type: spacetrader.WaypointTypeOrbitalStation, PosX: 100, PosY: 0, Traits: []spacetrader.Trait{{Symbol: spacetrader.WaypointTraitMarketplace}}},
+}
+
+func selling(symbol spacetrader.TradeSymbol, price int) spacetrader.MarketTradeGood {
+	return spacetrader.MarketTradeGood{Symbol: symbol, SellPrice: price, PurchasePrice: price + 10, TradeVolume: 10}
+}
+
+var beltMarkets = map[string]spacetrader.Market{
+	"X1-M-MKT": {
+		Symbol:     "X1-M-MKT",
+		Imports:    []spacetrader.MarketGood{{Symbol: spacetrader.TradeIronOre}, {Symbol: spacetrader.TradeCopperOre}},
+		Exchange:   []spacetrader.MarketGood{{Symbol: spacetrader.TradeFuel}},
+		TradeGoods: []spacetrader.MarketTradeGood{selling(spacetrader.TradeIronOre, 40), selling(spacetrader.TradeCopperOre, 50), selling(spacetrader.TradeFuel, 1)},
+	},
+	"X1-M-HUB": {
+		Symbol:     "X1-M-HUB",
+		Imports:    []spacetrader.MarketGood{{Symbol: spacetrader.TradeIronOre}},
+		TradeGoods: []spacetrader.MarketTradeGood{selling(spacetrader.TradeIronOre, 500)},
+	},
+}
+
+// field plays the API for one mining ship. Every dig comes up with the next good in yields, and
+// once they've run out the asteroid is dry: the dig after the last one closes dry and waits on
+// release before failing, so a test can look at how things stand with the loop held still.
+type field struct {
+	spacetrader.API
+
+	yields  []spacetrader.TradeSymbol
+	dry     chan struct{}
+	release chan struct{}
+
+	mu         sync.Mutex
+	ship       spacetrader.Ship
+	flown      []string
+	soldAt     []string
+	sold       map[spacetrader.TradeSymbol]int
+	jettisoned map[spacetrader.TradeSymbol]int
+	surveys    []string
+}
+
+func (f *field) GetShip(shipSymbol string) (spacetrader.Ship, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ship := f.ship
+	ship.Cargo.Inventory = slices.Clone(f.ship.Cargo.Inventory)
+	return ship, nil
+}
+
+func (f *field) GetWaypoints(systemSymbol string, query spacetrader.WaypointQuery) ([]spacetrader.Waypoint, spacetrader.Meta, error) {
+	return belt, spacetrader.Meta{Total: len(belt)}, nil
+}
+
+func (f *field) GetWaypoint(systemSymbol string, waypointSymbol string) (spacetrader.Waypoint, error) {
+	for _, waypoint := range belt {
+		if waypoint.Symbol == waypointSymbol {
+			return waypoint, nil
+		}
+	}
+	return spacetrader.Waypoint{}, errors.New(waypointSymbol + " isn't in X1-M")
+}
+
+func (f *field) GetMarket(systemSymbol string, waypointSymbol string) (spacetrader.Market, error) {
+	return beltMarkets[waypointSymbol], nil
+}
+
+func (f *field) LaunchToOrbit(shipSymbol string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.ship.Nav.Status = spacetrader.ShipNavStatusInOrbit
+	return true, nil
+}
+
+func (f *field) DockShip(shipSymbol string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.ship.Nav.Status = spacetrader.ShipNavStatusDocked
+	return true, nil
+}
+
+func (f *field) SetFlightMode(shipSymbol string, mode spacetrader.FlightMode) (spacetrader.ShipNav, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.ship.Nav.FlightMode = mode
+	return f.ship.Nav, nil
+}
+
+func (f *field) NavigateShip(shipSymbol string, waypointSymbol string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.flown = append(f.flown, waypointSymbol)
+	f.ship.Nav.WaypointSymbol, f.ship.Nav.Status = waypointSymbol, spacetrader.ShipNavStatusInOrbit
+	return true, nil
+}
+
+func (f *field) RefuelShip(shipSymbol string, units int) (spacetrader.ShipRefuel, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.ship.Fuel.Current = f.ship.Fuel.Capacity
+	return spacetrader.ShipRefuel{Fuel: f.ship.Fuel}, nil
+}
+
+func (f *field) CreateSurvey(shipSymbol string) (spacetrader.ShipSurvey, error) {
+	survey := func(signature string, size spacetrader.SurveySize, deposits ...spacetrader.TradeSymbol) spacetrader.Survey {
+		s := spacetrader.Survey{Signature: signature, Symbol: "X1-M-AST", Size: size}
+		for _, deposit := range deposits {
+			s.Deposits = append(s.Deposits, spacetrader.SurveyDeposit{Symbol: deposit})
+		}
+		return s
+	}
+
+	return spacetrader.ShipSurvey{Surveys: []spacetrader.Survey{
+		survey("X1-M-AST-ICE", "LARGE", spacetrader.TradeIceWater, spacetrader.TradeQuartzSand, spacetrader.TradeIceWater),
+		survey("X1-M-AST-ORE", "MODERATE", spacetrader.TradeIronOre, spacetrader.TradeCopperOre, spacetrader.TradeIceWater),
+	}}, nil
+}
+
+func (f *field) ExtractResources(shipSymbol string, survey *spacetrader.Survey) (spacetrader.Extract, error) {
+	f.mu.Lock()
+	if survey != nil {
+		f.surveys = append(f.surveys, survey.Signature)
+	}
+	if f.ship.Nav.WaypointSymbol != "X1-M-AST" || f.ship.Nav.Status != spacetrader.ShipNavStatusInOrbit {
+		defer f.mu.Unlock()
+		return spacetrader.Extract{}, errors.New("not in orbit over the asteroid")
+	}
+	if len(f.yields) == 0 {
+		f.mu.Unlock()
+		close(f.dry)
+		<-f.release
+		return spacetrader.Extract{}, errors.New("the asteroid's dry")
+	}
+	defer f.mu.Unlock()
+
+	yield := spacetrader.ExtractionYield{Symbol: f.yields[0], Units: min(10, f.ship.Cargo.Capacity-f.ship.Cargo.Units)}
+	f.yields = f.yields[1:]
+	f.load(yield.Symbol, yield.Units)
+
+	// Digging heats the reactor, though by the time anyone looks it's cooled off again
+	now := time.Now().UTC()
+	cooldown := spacetrader.Cooldown{ShipSymbol: shipSymbol, TotalSeconds: 70, RemainingSeconds: 70, Expiration: now.Format(time.RFC3339Nano)}
+
+	return spacetrader.Extract{Extraction: spacetrader.ShipExtraction{ShipSymbol: shipSymbol, Yield: yield}, Cooldown: cooldown, Cargo: f.ship.Cargo}, nil
+}
+
+func (f *field) JettisonCargo(shipSymbol string, tradeSymbol spacetrader.TradeSymbol, units int) (spacetrader.ShipCargo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.jettisoned[tradeSymbol] += units
+	f.load(tradeSymbol, -units)
+	return f.ship.Cargo, nil
+}
+
+func (f *field) SellCargo(shipSymbol string, tradeSymbol spacetrader.TradeSymbol, units int) (spacetrader.CargoTrade, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.ship.Nav.Status != spacetrader.ShipNavStatusDocked {
+		return spacetrader.CargoTrade{}, errors.New("not docked")
+	}
+	good, ok := beltMarkets[f.ship.Nav.WaypointSymbol].TradeGood(tradeSymbol)
+	if !ok {
+		return spacetrader.CargoTrade{}, &spacetrader.APIError{Code: int(spacetrader.ErrCodeTradeNotSold), Message: "not sold here"}
+	}
+
+	if !slices.Contains(f.soldAt, f.ship.Nav.WaypointSymbol) {
+		f.soldAt = append(f.soldAt, f.ship.Nav.WaypointSymbol)
+	}
+	f.sold[tradeSymbol] += units
+	f.load(tradeSymbol, -units)
+	return spacetrader.CargoTrade{Cargo: f.ship.Cargo, Transaction: spacetrader.MarketTransaction{TotalPrice: units * good.SellPrice}}, nil
+}
+
+// load puts units of a good in the hold, or takes them out when units is negative. f.mu must be held.
+func (f *field) load(symbol spacetrader.TradeSymbol, units int) {
+	f.ship.Cargo.Units += units
+	for i, cargo := range f.ship.Cargo.Inventory {
+		if cargo.Symbol == symbol {
+			f.ship.Cargo.Inventory[i].Units += units
+			if f.ship.Cargo.Inventory[i].Units <= 0 {
+				f.ship.Cargo.Inventory = slices.Delete(f.ship.Cargo.Inventory, i, i+1)
+			}
+			return
+		}
+	}
+	f.ship.Cargo.Inventory = append(f.ship.Cargo.Inventory, spacetrader.Cargo{Symbol: symbol, Units: units})
+}
+
+// prospector is a ship with a 30 unit hold and a laser that digs 10 at a time, in orbit over the
+// asteroid unless change says otherwise
+func prospector(change func(*spacetrader.Ship)) spacetrader.Ship {
+	ship := spacetrader.Ship{
+		Symbol: "SHIP-1",
+		Nav:    spacetrader.ShipNav{SystemSymbol: "X1-M", WaypointSymbol: "X1-M-AST", Status: spacetrader.ShipNavStatusInOrbit, FlightMode: spacetrader.FlightModeCruise},
+		Engine: spacetrader.ShipEngine{Speed: 30},
+		Fuel:   spacetrader.ShipFuel{Current: 400, Capacity: 400},
+		Cargo:  spacetrader.ShipCargo{Capacity: 30, Inventory: []spacetrader.Cargo{}},
+		Mounts: []spacetrader.ShipMount{{Symbol: spacetrader.TradeMountMiningLaserI, Strength: 10}},
+	}
+	if change != nil {
+		change(&ship)
+	}
+
+	return ship
+}
+
+// dig sets a miner up over a field that gives up yields, with prices already on record for markets
+func dig(t *testing.T, ship spacetrader.Ship, yields []spacetrader.TradeSymbol, recorded ...spacetrader.Market) (*mining.Miner, *field) {
+	t.Helper()
+
+	api := &field{
+		yields:     yields,
+		dry:        make(chan struct{}),
+		release:    make(chan struct{}),
+		ship:       ship,
+		sold:       map[spacetrader.TradeSymbol]int{},
+		jettisoned: map[spacetrader.TradeSymbol]int{},
+	}
+
+	pilot, err := autopilot.New(api, filepath.Join(t.TempDir(), "autopilot.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pilot.Slack, pilot.Poll = 0, time.Millisecond
+	t.Cleanup(pilot.Stop)
+
+	prices, err := history.Open(filepath.Join(t.TempDir(), "market-history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { prices.Close() })
+	for _, market := range recorded {
+		if err := prices.Record(market, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	miner := mining.New(api, pilot, prices)
+	miner.Slack, miner.Poll = 0, time.Millisecond
+
+	return miner, api
+}
+
+// mine runs the loop in the background until the field runs dry, handing back what Mine returns
+func mine(t *testing.T, ctx context.Context, miner *mining.Miner, api *field) <-chan error {
+	t.Helper()
+
+	mined := make(chan error, 1)
+	go func() { mined <- miner.Mine(ctx, "SHIP-1", "X1-M-AST") }()
+
+	select {
+	case <-api.dry:
+	case err := <-mined:
+		t.Fatalf("Mine gave up before the field ran dry: %v", err)
+	case <-time.After(5 * time.Second):
+		status, _ := miner.Status("SHIP-1")
+		t.Fatalf("field never ran dry, got as far as %s: %s", status.Step, status.Detail)
+	}
+
+	return mined
+}
+
+func TestMiningLoop(t *testing.T) {
+	iron, copper, ice := spacetrader.TradeIronOre, spacetrader.TradeCopperOre, spacetrader.TradeIceWater
+	hub := beltMarkets["X1-M-HUB"]
+
+	cases := []struct {
+		name     string
+		ship     spacetrader.Ship
+		yields   []spacetrader.TradeSymbol
+		recorded []spacetrader.Market
+		// flown is everywhere the autopilot sent the ship, soldAt where it sold anything
+		flown      []string
+		soldAt     []string
+		sold       map[spacetrader.TradeSymbol]int
+		jettisoned map[spacetrader.TradeSymbol]int
+		surveys    []string
+		loads      int
+		extracted  int
+	}{
+		{"a full hold goes to whoever takes the most of it", prospector(nil), []spacetrader.TradeSymbol{iron, copper, iron, iron},
+			nil, []string{"X1-M-MKT", "X1-M-AST"}, []string{"X1-M-MKT"}, map[spacetrader.TradeSymbol]int{iron: 20, copper: 10}, map[spacetrader.TradeSymbol]int{}, nil, 1, 40},
+		{"what nobody buys is thrown away", prospector(nil), []spacetrader.TradeSymbol{iron, ice, iron, ice, iron},
+			nil, []string{"X1-M-MKT", "X1-M-AST"}, []string{"X1-M-MKT"}, map[spacetrader.TradeSymbol]int{iron: 30}, map[spacetrader.TradeSymbol]int{ice: 20}, nil, 1, 50},
+		// The hub pays so well for iron it's worth the trip, the copper stays aboard for next time
+		{"prices on record pick the market", prospector(nil), []spacetrader.TradeSymbol{iron, copper, iron, iron},
+			[]spacetrader.Market{hub}, []string{"X1-M-HUB", "X1-M-AST"}, []string{"X1-M-HUB"}, map[spacetrader.TradeSymbol]int{iron: 20}, map[spacetrader.TradeSymbol]int{}, nil, 1, 40},
+		{"surveys aim for the ores", prospector(func(ship *spacetrader.Ship) {
+			ship.Mounts = append(ship.Mounts, spacetrader.ShipMount{Symbol: spacetrader.TradeMountSurveyorI, Strength: 1})
+		}), []spacetrader.TradeSymbol{iron, iron},
+			nil, nil, nil, map[spacetrader.TradeSymbol]int{}, map[spacetrader.TradeSymbol]int{}, []string{"X1-M-AST-ORE", "X1-M-AST-ORE", "X1-M-AST-ORE"}, 0, 20},
+		{"it flies out to the asteroid first", prospector(func(ship *spacetrader.Ship) {
+			ship.Nav.WaypointSymbol, ship.Nav.Status = "X1-M-MKT", spacetrader.ShipNavStatusDocked
+		}), []spacetrader.TradeSymbol{iron},
+			nil, []string{"X1-M-AST"}, nil, map[spacetrader.TradeSymbol]int{}, map[spacetrader.TradeSymbol]int{}, nil, 0, 10},
+		{"a hold that's already full is sold before digging", prospector(func(ship *spacetrader.Ship) {
+			ship.Cargo.Units, ship.Cargo.Inventory = 30, []spacetrader.Cargo{{Symbol: copper, Units: 30}}
+		}), []spacetrader.TradeSymbol{iron},
+			nil, []string{"X1-M-MKT", "X1-M-AST"}, []string{"X1-M-MKT"}, map[spacetrader.TradeSymbol]int{copper: 30}, map[spacetrader.TradeSymbol]int{}, nil, 1, 10},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			miner, api := dig(t, c.ship, c.yields, c.recorded...)
+			mined := mine(t, context.Background(), miner, api)
+
+			status, _ := miner.Status("SHIP-1")
+			api.mu.Lock()
+			flown, soldAt, sold, jettisoned, surveys := api.flown, api.soldAt, api.sold, api.jettisoned, api.surveys
+			api.mu.Unlock()
+
+			miner.Stop("SHIP-1")
+			close(api.release)
+			<-mined
+
+			if !slices.Equal(flown, c.flown) || !slices.Equal(soldAt, c.soldAt) {
+				t.Errorf("flew to %v and sold at %v, want %v and %v", flown, soldAt, c.flown, c.soldAt)
+			}
+			if !maps.Equal(sold, c.sold) || !maps.Equal(jettisoned, c.jettisoned) {
+				t.Errorf("sold %v and threw away %v, want %v and %v", sold, jettisoned, c.sold, c.jettisoned)
+			}
+			if !slices.Equal(surveys, c.surveys) {
+				t.Errorf("dug with surveys %v, want %v", surveys, c.surveys)
+			}
+			if status.Loads != c.loads || status.Extracted != c.extracted {
+				t.Errorf("sold %d loads out of %d extracted, want %d out of %d", status.Loads, status.Extracted, c.loads, c.extracted)
+			}
+		})
+	}
+}
+
+func TestMiningEnds(t *testing.T) {
+	cases := []struct {
+		name string
+		// end finishes the mining while the field is dry, the asteroid's let go of straight after
+		end  func(miner *mining.Miner, cancel context.CancelFunc)
+		want string
+		step mining.Step
+	}{
+		{"stopped", func(miner *mining.Miner, cancel context.CancelFunc) { miner.Stop("SHIP-1") }, mining.ErrStopped.Error(), mining.StepStopped},
+		{"cancelled", func(miner *mining.Miner, cancel context.CancelFunc) { cancel() }, context.Canceled.Error(), mining.StepStopped},
+		{"failed", func(miner *mining.Miner, cancel context.CancelFunc) {}, "extracting at X1-M-AST: the asteroid's dry", mining.StepFailed},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			miner, api := dig(t, prospector(nil), []spacetrader.TradeSymbol{spacetrader.TradeIronOre})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			mined := mine(t, ctx, miner, api)
+
+			c.end(miner, cancel)
+			close(api.release)
+
+			err := <-mined
+			if err == nil || err.Error() != c.want {
+				t.Errorf("Mine came back with %v, want %s", err, c.want)
+			}
+			if c.step == mining.StepStopped && !errors.Is(err, mining.ErrStopped) && !errors.Is(err, context.Canceled) {
+				t.Errorf("%v doesn't match ErrStopped or context.Canceled", err)
+			}
+
+			status, _ := miner.Status("SHIP-1")
+			if status.Step != c.step || (c.step == mining.StepFailed) != strings.Contains(status.Error, "dry") {
+				t.Errorf("mining ended %s with error %q, want %s", status.Step, status.Error, c.step)
+			}
+
+			// Stopping twice has nothing left to stop
+			if err := miner.Stop("SHIP-1"); !errors.Is(err, mining.ErrNotMining) {
+				t.Errorf("stopping again got %v, want ErrNotMining", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,522 @@
+// Code generated by openapigen from the SpaceTraders OpenAPI spec. DO NOT EDIT.
+
+package model
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Doer sends one request to the API and decodes the whole response body, data and meta, into out
+type Doer interface {
+	Do(method string, path string, body any, out any) error
+}
+
+// GetMyAgent is GET /my/agent, Get Agent
+func GetMyAgent(d Doer) (Agent, error) {
+	path := "/my/agent"
+
+	var response struct {
+		Data Agent `json:"data"`
+	}
+	err := d.Do(http.MethodGet, path, nil, &response)
+
+	return response.Data, err
+}
+
+// GetContracts is GET /my/contracts, List Contracts
+func GetContracts(d Doer, query url.Values) ([]Contract, Meta, error) {
+	path := "/my/contracts"
+	if len(query) > 0 {
+		path = fmt.Sprint(path, "?", query.Encode())
+	}
+
+	var response struct {
+		Data []Contract `json:"data"`
+		Meta Meta       `json:"meta"`
+	}
+	err := d.Do(http.MethodGet, path, nil, &response)
+
+	return response.Data, response.Meta, err
+}
+
+// AcceptContract is POST /my/contracts/{contractId}/accept, Accept Contract
+func AcceptContract(d Doer, contractId string) (ContractUpdate, error) {
+	path := fmt.Sprint("/my/contracts/", url.PathEscape(contractId), "/accept")
+
+	var response struct {
+		Data ContractUpdate `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, nil, &response)
+
+	return response.Data, err
+}
+
+// DeliverContract is POST /my/contracts/{contractId}/deliver, Deliver Cargo to Contract
+func DeliverContract(d Doer, contractId string, body DeliverContractRequest) (ContractDeliveryUpdate, error) {
+	path := fmt.Sprint("/my/contracts/", url.PathEscape(contractId), "/deliver")
+
+	var response struct {
+		Data ContractDeliveryUpdate `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, body, &response)
+
+	return response.Data, err
+}
+
+// FulfillContract is POST /my/contracts/{contractId}/fulfill, Fulfill Contract
+func FulfillContract(d Doer, contractId string) (ContractUpdate, error) {
+	path := fmt.Sprint("/my/contracts/", url.PathEscape(contractId), "/fulfill")
+
+	var response struct {
+		Data ContractUpdate `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, nil, &response)
+
+	return response.Data, err
+}
+
+// GetMyShips is GET /my/ships, List Ships
+func GetMyShips(d Doer, query url.Values) ([]Ship, Meta, error) {
+	path := "/my/ships"
+	if len(query) > 0 {
+		path = fmt.Sprint(path, "?", query.Encode())
+	}
+
+	var response struct {
+		Data []Ship `json:"data"`
+		Meta Meta   `json:"meta"`
+	}
+	err := d.Do(http.MethodGet, path, nil, &response)
+
+	return response.Data, response.Meta, err
+}
+
+// GetMyShip is GET /my/ships/{shipSymbol}, Get Ship
+func GetMyShip(d Doer, shipSymbol string) (Ship, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol))
+
+	var response struct {
+		Data Ship `json:"data"`
+	}
+	err := d.Do(http.MethodGet, path, nil, &response)
+
+	return response.Data, err
+}
+
+// DockShip is POST /my/ships/{shipSymbol}/dock, Dock Ship
+func DockShip(d Doer, shipSymbol string) (ShipTransit, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/dock")
+
+	var response struct {
+		Data ShipTransit `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, nil, &response)
+
+	return response.Data, err
+}
+
+// ExtractResources is POST /my/ships/{shipSymbol}/extract, Extract Resources
+func ExtractResources(d Doer, shipSymbol string) (Extract, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/extract")
+
+	var response struct {
+		Data Extract `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, nil, &response)
+
+	return response.Data, err
+}
+
+// ExtractResourcesWithSurvey is POST /my/ships/{shipSymbol}/extract/survey, Extract Resources with Survey
+func ExtractResourcesWithSurvey(d Doer, shipSymbol string, body Survey) (Extract, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/extract/survey")
+
+	var response struct {
+		Data Extract `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, body, &response)
+
+	return response.Data, err
+}
+
+// Jettison is POST /my/ships/{shipSymbol}/jettison, Jettison Cargo
+func Jettison(d Doer, shipSymbol string, body JettisonRequest) (JettisonResult, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/jettison")
+
+	var response struct {
+		Data JettisonResult `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, body, &response)
+
+	return response.Data, err
+}
+
+// JumpShip is POST /my/ships/{shipSymbol}/jump, Jump Ship
+func JumpShip(d Doer, shipSymbol string, body JumpShipRequest) (ShipJump, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/jump")
+
+	var response struct {
+		Data ShipJump `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, body, &response)
+
+	return response.Data, err
+}
+
+// GetShipModules is GET /my/ships/{shipSymbol}/modules, Get Ship Modules
+func GetShipModules(d Doer, shipSymbol string) ([]ShipModule, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/modules")
+
+	var response struct {
+		Data []ShipModule `json:"data"`
+	}
+	err := d.Do(http.MethodGet, path, nil, &response)
+
+	return response.Data, err
+}
+
+// InstallShipModule is POST /my/ships/{shipSymbol}/modules/install, Install Ship Module
+func InstallShipModule(d Doer, shipSymbol string, body InstallShipModuleRequest) (ModuleChange, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/modules/install")
+
+	var response struct {
+		Data ModuleChange `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, body, &response)
+
+	return response.Data, err
+}
+
+// RemoveShipModule is POST /my/ships/{shipSymbol}/modules/remove, Remove Ship Module
+func RemoveShipModule(d Doer, shipSymbol string, body RemoveShipModuleRequest) (ModuleChange, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/modules/remove")
+
+	var response struct {
+		Data ModuleChange `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, body, &response)
+
+	return response.Data, err
+}
+
+// GetMounts is GET /my/ships/{shipSymbol}/mounts, Get Mounts
+func GetMounts(d Doer, shipSymbol string) ([]ShipMount, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/mounts")
+
+	var response struct {
+		Data []ShipMount `json:"data"`
+	}
+	err := d.Do(http.MethodGet, path, nil, &response)
+
+	return response.Data, err
+}
+
+// InstallMount is POST /my/ships/{shipSymbol}/mounts/install, Install Mount
+func InstallMount(d Doer, shipSymbol string, body InstallMountRequest) (MountChange, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/mounts/install")
+
+	var response struct {
+		Data MountChange `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, body, &response)
+
+	return response.Data, err
+}
+
+// RemoveMount is POST /my/ships/{shipSymbol}/mounts/remove, Remove Mount
+func RemoveMount(d Doer, shipSymbol string, body RemoveMountRequest) (MountChange, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/mounts/remove")
+
+	var response struct {
+		Data MountChange `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, body, &response)
+
+	return response.Data, err
+}
+
+// PatchShipNav is PATCH /my/ships/{shipSymbol}/nav, Patch Ship Nav
+func PatchShipNav(d Doer, shipSymbol string, body PatchShipNavRequest) (ShipNav, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/nav")
+
+	var response struct {
+		Data ShipNav `json:"data"`
+	}
+	err := d.Do(http.MethodPatch, path, body, &response)
+
+	return response.Data, err
+}
+
+// NavigateShip is POST /my/ships/{shipSymbol}/navigate, Navigate Ship
+func NavigateShip(d Doer, shipSymbol string, body NavigateShipRequest) (ShipTransit, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/navigate")
+
+	var response struct {
+		Data ShipTransit `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, body, &response)
+
+	return response.Data, err
+}
+
+// OrbitShip is POST /my/ships/{shipSymbol}/orbit, Orbit Ship
+func OrbitShip(d Doer, shipSymbol string) (ShipTransit, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/orbit")
+
+	var response struct {
+		Data ShipTransit `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, nil, &response)
+
+	return response.Data, err
+}
+
+// PurchaseCargo is POST /my/ships/{shipSymbol}/purchase, Purchase Cargo
+func PurchaseCargo(d Doer, shipSymbol string, body PurchaseCargoRequest) (CargoTrade, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/purchase")
+
+	var response struct {
+		Data CargoTrade `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, body, &response)
+
+	return response.Data, err
+}
+
+// ShipRefine is POST /my/ships/{shipSymbol}/refine, Ship Refine
+func ShipRefine(d Doer, shipSymbol string, body ShipRefineRequest) (Refinement, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/refine")
+
+	var response struct {
+		Data Refinement `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, body, &response)
+
+	return response.Data, err
+}
+
+// RefuelShip is POST /my/ships/{shipSymbol}/refuel, Refuel Ship
+func RefuelShip(d Doer, shipSymbol string, body RefuelShipRequest) (ShipRefuel, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/refuel")
+
+	var response struct {
+		Data ShipRefuel `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, body, &response)
+
+	return response.Data, err
+}
+
+// GetRepairShip is GET /my/ships/{shipSymbol}/repair, Get Repair Ship
+func GetRepairShip(d Doer, shipSymbol string) (GetRepairShipResult, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/repair")
+
+	var response struct {
+		Data GetRepairShipResult `json:"data"`
+	}
+	err := d.Do(http.MethodGet, path, nil, &response)
+
+	return response.Data, err
+}
+
+// RepairShip is POST /my/ships/{shipSymbol}/repair, Repair Ship
+func RepairShip(d Doer, shipSymbol string) (ShipRepair, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/repair")
+
+	var response struct {
+		Data ShipRepair `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, nil, &response)
+
+	return response.Data, err
+}
+
+// GetScrapShip is GET /my/ships/{shipSymbol}/scrap, Get Scrap Ship
+func GetScrapShip(d Doer, shipSymbol string) (GetScrapShipResult, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/scrap")
+
+	var response struct {
+		Data GetScrapShipResult `json:"data"`
+	}
+	err := d.Do(http.MethodGet, path, nil, &response)
+
+	return response.Data, err
+}
+
+// ScrapShip is POST /my/ships/{shipSymbol}/scrap, Scrap Ship
+func ScrapShip(d Doer, shipSymbol string) (ShipScrap, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/scrap")
+
+	var response struct {
+		Data ShipScrap `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, nil, &response)
+
+	return response.Data, err
+}
+
+// SellCargo is POST /my/ships/{shipSymbol}/sell, Sell Cargo
+func SellCargo(d Doer, shipSymbol string, body SellCargoRequest) (CargoTrade, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/sell")
+
+	var response struct {
+		Data CargoTrade `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, body, &response)
+
+	return response.Data, err
+}
+
+// SiphonResources is POST /my/ships/{shipSymbol}/siphon, Siphon Resources
+func SiphonResources(d Doer, shipSymbol string) (SiphonResourcesResult, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/siphon")
+
+	var response struct {
+		Data SiphonResourcesResult `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, nil, &response)
+
+	return response.Data, err
+}
+
+// CreateSurvey is POST /my/ships/{shipSymbol}/survey, Create Survey
+func CreateSurvey(d Doer, shipSymbol string) (ShipSurvey, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/survey")
+
+	var response struct {
+		Data ShipSurvey `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, nil, &response)
+
+	return response.Data, err
+}
+
+// TransferCargo is POST /my/ships/{shipSymbol}/transfer, Transfer Cargo
+func TransferCargo(d Doer, shipSymbol string, body TransferCargoRequest) (TransferCargoResult, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/transfer")
+
+	var response struct {
+		Data TransferCargoResult `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, body, &response)
+
+	return response.Data, err
+}
+
+// WarpShip is POST /my/ships/{shipSymbol}/warp, Warp Ship
+func WarpShip(d Doer, shipSymbol string, body WarpShipRequest) (ShipTransit, error) {
+	path := fmt.Sprint("/my/ships/", url.PathEscape(shipSymbol), "/warp")
+
+	var response struct {
+		Data ShipTransit `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, body, &response)
+
+	return response.Data, err
+}
+
+// GetSystems is GET /systems, List Systems
+func GetSystems(d Doer, query url.Values) ([]System, Meta, error) {
+	path := "/systems"
+	if len(query) > 0 {
+		path = fmt.Sprint(path, "?", query.Encode())
+	}
+
+	var response struct {
+		Data []System `json:"data"`
+		Meta Meta     `json:"meta"`
+	}
+	err := d.Do(http.MethodGet, path, nil, &response)
+
+	return response.Data, response.Meta, err
+}
+
+// GetSystem is GET /systems/{systemSymbol}, Get System
+func GetSystem(d Doer, systemSymbol string) (System, error) {
+	path := fmt.Sprint("/systems/", url.PathEscape(systemSymbol))
+
+	var response struct {
+		Data System `json:"data"`
+	}
+	err := d.Do(http.MethodGet, path, nil, &response)
+
+	return response.Data, err
+}
+
+// GetSystemWaypoints is GET /systems/{systemSymbol}/waypoints, List Waypoints in System
+func GetSystemWaypoints(d Doer, systemSymbol string, query url.Values) ([]Waypoint, Meta, error) {
+	path := fmt.Sprint("/systems/", url.PathEscape(systemSymbol), "/waypoints")
+	if len(query) > 0 {
+		path = fmt.Sprint(path, "?", query.Encode())
+	}
+
+	var response struct {
+		Data []Waypoint `json:"data"`
+		Meta Meta       `json:"meta"`
+	}
+	err := d.Do(http.MethodGet, path, nil, &response)
+
+	return response.Data, response.Meta, err
+}
+
+// GetWaypoint is GET /systems/{systemSymbol}/waypoints/{waypointSymbol}, Get Waypoint
+func GetWaypoint(d Doer, systemSymbol string, waypointSymbol string) (Waypoint, error) {
+	path := fmt.Sprint("/systems/", url.PathEscape(systemSymbol), "/waypoints/", url.PathEscape(waypointSymbol))
+
+	var response struct {
+		Data Waypoint `json:"data"`
+	}
+	err := d.Do(http.MethodGet, path, nil, &response)
+
+	return response.Data, err
+}
+
+// GetConstruction is GET /systems/{systemSymbol}/waypoints/{waypointSymbol}/construction, Get Construction Site
+func GetConstruction(d Doer, systemSymbol string, waypointSymbol string) (Construction, error) {
+	path := fmt.Sprint("/systems/", url.PathEscape(systemSymbol), "/waypoints/", url.PathEscape(waypointSymbol), "/construction")
+
+	var response struct {
+		Data Construction `json:"data"`
+	}
+	err := d.Do(http.MethodGet, path, nil, &response)
+
+	return response.Data, err
+}
+
+// SupplyConstruction is POST /systems/{systemSymbol}/waypoints/{waypointSymbol}/construction/supply, Supply Construction Site
+func SupplyConstruction(d Doer, systemSymbol string, waypointSymbol string, body SupplyConstructionRequest) (ConstructionSupply, error) {
+	path := fmt.Sprint("/systems/", url.PathEscape(systemSymbol), "/waypoints/", url.PathEscape(waypointSymbol), "/construction/supply")
+
+	var response struct {
+		Data ConstructionSupply `json:"data"`
+	}
+	err := d.Do(http.MethodPost, path, body, &response)
+
+	return response.Data, err
+}
+
+// GetJumpGate is GET /systems/{systemSymbol}/waypoints/{waypointSymbol}/jump-gate, Get Jump Gate
+func GetJumpGate(d Doer, systemSymbol string, waypointSymbol string) (JumpGate, error) {
+	path := fmt.Sprint("/systems/", url.PathEscape(systemSymbol), "/waypoints/", url.PathEscape(waypointSymbol), "/jump-gate")
+
+	var response struct {
+		Data JumpGate `json:"data"`
+	}
+	err := d.Do(http.MethodGet, path, nil, &response)
+
+	return response.Data, err
+}
+
+// GetMarket is GET /systems/{systemSymbol}/waypoints/{waypointSymbol}/market, Get Market
+func GetMarket(d Doer, systemSymbol string, waypointSymbol string) (Market, error) {
+	path := fmt.Sprint("/systems/", url.PathEscape(systemSymbol), "/waypoints/", url.PathEscape(waypointSymbol), "/market")
+
+	var response struct {
+		Data Market `json:"data"`
+	}
+	err := d.Do(http.MethodGet, path, nil, &response)
+
+	return response.Data, err
+}
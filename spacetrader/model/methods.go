@@ -0,0 +1,56 @@
+package model
+
+import "strings"
+
+// The client hangs a few helpers off the generated models. They live here, out of the way
+// of go generate, since the models are aliased rather than wrapped.
+
+// HasTrait reports whether the waypoint has been tagged with trait
+func (w Waypoint) HasTrait(trait WaypointTraitSymbol) bool {
+	for _, wTrait := range w.Traits {
+		if wTrait.Symbol == trait {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TradeGood finds the going rate for a good, if the market has one posted
+func (m Market) TradeGood(symbol TradeSymbol) (MarketTradeGood, bool) {
+	for _, good := range m.TradeGoods {
+		if good.Symbol == symbol {
+			return good, true
+		}
+	}
+
+	return MarketTradeGood{}, false
+}
+
+// Sells reports whether the market deals in a good at all, even without one of our ships there to see prices
+func (m Market) Sells(symbol TradeSymbol) bool {
+	for _, goods := range [][]TradeGood{m.Exports, m.Exchange} {
+		for _, good := range goods {
+			if good.Symbol == symbol {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// IsMount reports whether a trade good is a mount that can be installed
+func (t TradeSymbol) IsMount() bool {
+	return strings.HasPrefix(string(t), "MOUNT_")
+}
+
+// IsModule reports whether a trade good is a module that can be installed
+func (t TradeSymbol) IsModule() bool {
+	return strings.HasPrefix(string(t), "MODULE_")
+}
+
+// Remaining is how many more units the site needs before this material is done
+func (m ConstructionMaterial) Remaining() int {
+	return max(0, m.Required-m.Fulfilled)
+}
@@ -0,0 +1,1486 @@
+// Code generated by openapigen from the SpaceTraders OpenAPI spec. DO NOT EDIT.
+
+// Package model is generated from the SpaceTraders OpenAPI spec. Only methods.go is written by hand, go generate overwrites the rest.
+package model
+
+type Agent struct {
+	AccountId       string `json:"accountId,omitempty"`
+	Credits         int64  `json:"credits"`
+	Headquarters    string `json:"headquarters"`
+	ShipCount       int    `json:"shipCount"`
+	StartingFaction string `json:"startingFaction"`
+	Symbol          string `json:"symbol"`
+}
+
+// Chart: The chart of a system or waypoint, which makes the location visible to other agents.
+type Chart struct {
+	SubmittedBy    string `json:"submittedBy,omitempty"`
+	SubmittedOn    string `json:"submittedOn,omitempty"`
+	WaypointSymbol string `json:"waypointSymbol,omitempty"`
+}
+
+// Construction: The construction details of a waypoint.
+type Construction struct {
+	IsComplete bool                   `json:"isComplete"`
+	Materials  []ConstructionMaterial `json:"materials"`
+	Symbol     string                 `json:"symbol"`
+}
+
+// ConstructionMaterial: The details of the required construction materials for a given waypoint under construction.
+type ConstructionMaterial struct {
+	// The number of units fulfilled toward the required amount.
+	Fulfilled int `json:"fulfilled"`
+	// The number of units required.
+	Required    int         `json:"required"`
+	TradeSymbol TradeSymbol `json:"tradeSymbol"`
+}
+
+type Contract struct {
+	Accepted         bool          `json:"accepted"`
+	DeadlineToAccept string        `json:"deadlineToAccept,omitempty"`
+	Expiration       string        `json:"expiration"`
+	FactionSymbol    string        `json:"factionSymbol"`
+	Fulfilled        bool          `json:"fulfilled"`
+	Identifier       string        `json:"id"`
+	Terms            ContractTerms `json:"terms"`
+	Type             ContractType  `json:"type"`
+}
+
+type ContractType string
+
+const (
+	ContractTypeProcurement ContractType = "PROCUREMENT"
+	ContractTypeTransport   ContractType = "TRANSPORT"
+	ContractTypeShuttle     ContractType = "SHUTTLE"
+)
+
+var ContractTypeValues = []ContractType{
+	ContractTypeProcurement,
+	ContractTypeTransport,
+	ContractTypeShuttle,
+}
+
+func (v ContractType) Valid() bool {
+	for _, known := range ContractTypeValues {
+		if v == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+type ContractDeliverGood struct {
+	DestinationSymbol string      `json:"destinationSymbol"`
+	TradeSymbol       TradeSymbol `json:"tradeSymbol"`
+	UnitsFulfilled    int         `json:"unitsFulfilled"`
+	UnitsRequired     int         `json:"unitsRequired"`
+}
+
+type ContractPayment struct {
+	OnAccepted  int `json:"onAccepted"`
+	OnFulfilled int `json:"onFulfilled"`
+}
+
+type ContractTerms struct {
+	Deadline string                `json:"deadline"`
+	Deliver  []ContractDeliverGood `json:"deliver,omitempty"`
+	Payment  ContractPayment       `json:"payment"`
+}
+
+type Cooldown struct {
+	Expiration       string `json:"expiration,omitempty"`
+	RemainingSeconds int    `json:"remainingSeconds"`
+	ShipSymbol       string `json:"shipSymbol"`
+	TotalSeconds     int    `json:"totalSeconds"`
+}
+
+// Extraction: Extraction details.
+type Extraction struct {
+	ShipSymbol string          `json:"shipSymbol"`
+	Yield      ExtractionYield `json:"yield"`
+}
+
+// ExtractionYield: A yield from the extraction operation.
+type ExtractionYield struct {
+	Symbol TradeSymbol `json:"symbol"`
+	Units  int         `json:"units"`
+}
+
+// FactionSymbol: The symbol of the faction.
+type FactionSymbol string
+
+const (
+	FactionSymbolCosmic   FactionSymbol = "COSMIC"
+	FactionSymbolVoid     FactionSymbol = "VOID"
+	FactionSymbolGalactic FactionSymbol = "GALACTIC"
+	FactionSymbolQuantum  FactionSymbol = "QUANTUM"
+	FactionSymbolDominion FactionSymbol = "DOMINION"
+	FactionSymbolAstro    FactionSymbol = "ASTRO"
+	FactionSymbolCorsairs FactionSymbol = "CORSAIRS"
+	FactionSymbolObsidian FactionSymbol = "OBSIDIAN"
+	FactionSymbolAegis    FactionSymbol = "AEGIS"
+	FactionSymbolUnited   FactionSymbol = "UNITED"
+	FactionSymbolSolitary FactionSymbol = "SOLITARY"
+	FactionSymbolCobalt   FactionSymbol = "COBALT"
+	FactionSymbolOmega    FactionSymbol = "OMEGA"
+	FactionSymbolEcho     FactionSymbol = "ECHO"
+	FactionSymbolLords    FactionSymbol = "LORDS"
+	FactionSymbolCult     FactionSymbol = "CULT"
+	FactionSymbolAncients FactionSymbol = "ANCIENTS"
+	FactionSymbolShadow   FactionSymbol = "SHADOW"
+	FactionSymbolEthereal FactionSymbol = "ETHEREAL"
+)
+
+var FactionSymbolValues = []FactionSymbol{
+	FactionSymbolCosmic,
+	FactionSymbolVoid,
+	FactionSymbolGalactic,
+	FactionSymbolQuantum,
+	FactionSymbolDominion,
+	FactionSymbolAstro,
+	FactionSymbolCorsairs,
+	FactionSymbolObsidian,
+	FactionSymbolAegis,
+	FactionSymbolUnited,
+	FactionSymbolSolitary,
+	FactionSymbolCobalt,
+	FactionSymbolOmega,
+	FactionSymbolEcho,
+	FactionSymbolLords,
+	FactionSymbolCult,
+	FactionSymbolAncients,
+	FactionSymbolShadow,
+	FactionSymbolEthereal,
+}
+
+func (v FactionSymbol) Valid() bool {
+	for _, known := range FactionSymbolValues {
+		if v == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+type JumpGate struct {
+	// All the gates that are connected to this waypoint.
+	Connections []string `json:"connections"`
+	Symbol      string   `json:"symbol"`
+}
+
+type Market struct {
+	Exchange     []TradeGood         `json:"exchange"`
+	Exports      []TradeGood         `json:"exports"`
+	Imports      []TradeGood         `json:"imports"`
+	Symbol       string              `json:"symbol"`
+	TradeGoods   []MarketTradeGood   `json:"tradeGoods,omitempty"`
+	Transactions []MarketTransaction `json:"transactions,omitempty"`
+}
+
+type MarketTradeGood struct {
+	Activity      MarketTradeGoodActivity `json:"activity,omitempty"`
+	PurchasePrice int                     `json:"purchasePrice"`
+	SellPrice     int                     `json:"sellPrice"`
+	Supply        MarketTradeGoodSupply   `json:"supply"`
+	Symbol        TradeSymbol             `json:"symbol"`
+	TradeVolume   int                     `json:"tradeVolume"`
+	Type          MarketTradeGoodType     `json:"type"`
+}
+
+type MarketTradeGoodActivity string
+
+const (
+	MarketTradeGoodActivityWeak       MarketTradeGoodActivity = "WEAK"
+	MarketTradeGoodActivityGrowing    MarketTradeGoodActivity = "GROWING"
+	MarketTradeGoodActivityStrong     MarketTradeGoodActivity = "STRONG"
+	MarketTradeGoodActivityRestricted MarketTradeGoodActivity = "RESTRICTED"
+)
+
+var MarketTradeGoodActivityValues = []MarketTradeGoodActivity{
+	MarketTradeGoodActivityWeak,
+	MarketTradeGoodActivityGrowing,
+	MarketTradeGoodActivityStrong,
+	MarketTradeGoodActivityRestricted,
+}
+
+func (v MarketTradeGoodActivity) Valid() bool {
+	for _, known := range MarketTradeGoodActivityValues {
+		if v == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+type MarketTradeGoodSupply string
+
+const (
+	MarketTradeGoodSupplyScarce   MarketTradeGoodSupply = "SCARCE"
+	MarketTradeGoodSupplyLimited  MarketTradeGoodSupply = "LIMITED"
+	MarketTradeGoodSupplyModerate MarketTradeGoodSupply = "MODERATE"
+	MarketTradeGoodSupplyHigh     MarketTradeGoodSupply = "HIGH"
+	MarketTradeGoodSupplyAbundant MarketTradeGoodSupply = "ABUNDANT"
+)
+
+var MarketTradeGoodSupplyValues = []MarketTradeGoodSupply{
+	MarketTradeGoodSupplyScarce,
+	MarketTradeGoodSupplyLimited,
+	MarketTradeGoodSupplyModerate,
+	MarketTradeGoodSupplyHigh,
+	MarketTradeGoodSupplyAbundant,
+}
+
+func (v MarketTradeGoodSupply) Valid() bool {
+	for _, known := range MarketTradeGoodSupplyValues {
+		if v == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+type MarketTradeGoodType string
+
+const (
+	MarketTradeGoodTypeExport   MarketTradeGoodType = "EXPORT"
+	MarketTradeGoodTypeImport   MarketTradeGoodType = "IMPORT"
+	MarketTradeGoodTypeExchange MarketTradeGoodType = "EXCHANGE"
+)
+
+var MarketTradeGoodTypeValues = []MarketTradeGoodType{
+	MarketTradeGoodTypeExport,
+	MarketTradeGoodTypeImport,
+	MarketTradeGoodTypeExchange,
+}
+
+func (v MarketTradeGoodType) Valid() bool {
+	for _, known := range MarketTradeGoodTypeValues {
+		if v == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+type MarketTransaction struct {
+	PricePerUnit   int                   `json:"pricePerUnit"`
+	ShipSymbol     string                `json:"shipSymbol"`
+	Timestamp      string                `json:"timestamp"`
+	TotalPrice     int                   `json:"totalPrice"`
+	TradeSymbol    TradeSymbol           `json:"tradeSymbol"`
+	Type           MarketTransactionType `json:"type"`
+	Units          int                   `json:"units"`
+	WaypointSymbol string                `json:"waypointSymbol"`
+}
+
+type MarketTransactionType string
+
+const (
+	MarketTransactionTypePurchase MarketTransactionType = "PURCHASE"
+	MarketTransactionTypeSell     MarketTransactionType = "SELL"
+)
+
+var MarketTransactionTypeValues = []MarketTransactionType{
+	MarketTransactionTypePurchase,
+	MarketTransactionTypeSell,
+}
+
+func (v MarketTransactionType) Valid() bool {
+	for _, known := range MarketTransactionTypeValues {
+		if v == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+type Meta struct {
+	Limit int `json:"limit"`
+	Page  int `json:"page"`
+	Total int `json:"total"`
+}
+
+// RepairTransaction: Result of a repair transaction.
+type RepairTransaction struct {
+	ShipSymbol     string `json:"shipSymbol"`
+	Timestamp      string `json:"timestamp"`
+	TotalPrice     int    `json:"totalPrice"`
+	WaypointSymbol string `json:"waypointSymbol"`
+}
+
+// ScrapTransaction: Result of a scrap transaction.
+type ScrapTransaction = RepairTransaction
+
+type Ship struct {
+	Cargo        ShipCargo        `json:"cargo"`
+	Cooldown     Cooldown         `json:"cooldown"`
+	Crew         ShipCrew         `json:"crew"`
+	Engine       ShipEngine       `json:"engine"`
+	Frame        ShipFrame        `json:"frame"`
+	Fuel         ShipFuel         `json:"fuel"`
+	Modules      []ShipModule     `json:"modules"`
+	Mounts       []ShipMount      `json:"mounts"`
+	Nav          ShipNav          `json:"nav"`
+	Reactor      ShipReactor      `json:"reactor"`
+	Registration ShipRegistration `json:"registration"`
+	Symbol       string           `json:"symbol"`
+}
+
+type ShipCargo struct {
+	Capacity  int             `json:"capacity"`
+	Inventory []ShipCargoItem `json:"inventory"`
+	Units     int             `json:"units"`
+}
+
+type ShipCargoItem struct {
+	Description string      `json:"description"`
+	Name        string      `json:"name"`
+	Symbol      TradeSymbol `json:"symbol"`
+	Units       int         `json:"units"`
+}
+
+// ShipCrew: The ship's crew service and maintain the ship's systems and equipment.
+type ShipCrew struct {
+	// The maximum number of crew members the ship can support.
+	Capacity int `json:"capacity"`
+	// The current number of crew members on the ship.
+	Current int `json:"current"`
+	// A rough measure of the crew's morale. A higher morale means the crew is happier and more productive.
+	Morale int `json:"morale"`
+	// The minimum number of crew members required to maintain the ship.
+	Required int `json:"required"`
+	// The rotation of crew shifts. A stricter shift improves the ship's performance. A more relaxed shift improves the crew's morale.
+	Rotation ShipCrewRotation `json:"rotation"`
+	// The amount of credits per crew member paid per hour.
+	Wages int `json:"wages"`
+}
+
+// ShipCrewRotation: The rotation of crew shifts. A stricter shift improves the ship's performance. A more relaxed shift improves the crew's morale.
+type ShipCrewRotation string
+
+const (
+	ShipCrewRotationStrict  ShipCrewRotation = "STRICT"
+	ShipCrewRotationRelaxed ShipCrewRotation = "RELAXED"
+)
+
+var ShipCrewRotationValues = []ShipCrewRotation{
+	ShipCrewRotationStrict,
+	ShipCrewRotationRelaxed,
+}
+
+func (v ShipCrewRotation) Valid() bool {
+	for _, known := range ShipCrewRotationValues {
+		if v == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ShipEngine: The engine determines how quickly a ship travels between waypoints.
+type ShipEngine struct {
+	// The repairable condition of a component, from 0 to 1.
+	Condition   float64 `json:"condition"`
+	Description string  `json:"description"`
+	// The overall integrity of the component, from 0 to 1. Lost integrity can't be repaired.
+	Integrity float64 `json:"integrity"`
+	Name      string  `json:"name"`
+	// The overall quality of the component, which determines the quality of the component.
+	Quality      float64          `json:"quality"`
+	Requirements ShipRequirements `json:"requirements"`
+	// The speed stat of this engine. The higher the speed, the faster a ship can travel from one point to another.
+	Speed int `json:"speed"`
+	// Symbol of the engine.
+	Symbol TradeSymbol `json:"symbol"`
+}
+
+// ShipFrame: The frame of the ship. The frame determines the number of modules and mounting points of the ship, as well as base fuel capacity.
+type ShipFrame struct {
+	// The repairable condition of a component, from 0 to 1.
+	Condition    float64 `json:"condition"`
+	Description  string  `json:"description"`
+	FuelCapacity int     `json:"fuelCapacity"`
+	// The overall integrity of the component, from 0 to 1. Lost integrity can't be repaired.
+	Integrity      float64 `json:"integrity"`
+	ModuleSlots    int     `json:"moduleSlots"`
+	MountingPoints int     `json:"mountingPoints"`
+	Name           string  `json:"name"`
+	// The overall quality of the component, which determines the quality of the component.
+	Quality      float64          `json:"quality"`
+	Requirements ShipRequirements `json:"requirements"`
+	// Symbol of the frame.
+	Symbol TradeSymbol `json:"symbol"`
+}
+
+type ShipFuel struct {
+	Capacity int              `json:"capacity"`
+	Consumed ShipFuelConsumed `json:"consumed,omitempty"`
+	Current  int              `json:"current"`
+}
+
+type ShipFuelConsumed struct {
+	Amount    int    `json:"amount"`
+	Timestamp string `json:"timestamp"`
+}
+
+// ShipModificationTransaction: Result of a transaction for a ship modification, such as installing a mount or a module.
+type ShipModificationTransaction struct {
+	ShipSymbol     string      `json:"shipSymbol"`
+	Timestamp      string      `json:"timestamp"`
+	TotalPrice     int         `json:"totalPrice"`
+	TradeSymbol    TradeSymbol `json:"tradeSymbol"`
+	WaypointSymbol string      `json:"waypointSymbol"`
+}
+
+// ShipModule: A module can be installed in a ship and provides a set of capabilities such as storage space or quarters for crew.
+type ShipModule struct {
+	// Modules that provide capacity, such as cargo hold or crew quarters will show this value to denote how much of a bonus the module grants.
+	Capacity    int    `json:"capacity,omitempty"`
+	Description string `json:"description"`
+	Name        string `json:"name"`
+	// Modules that have a range will such as a sensor array show this value to denote how far can the module reach with its capabilities.
+	Range        int              `json:"range,omitempty"`
+	Requirements ShipRequirements `json:"requirements"`
+	// Symbol of the module.
+	Symbol TradeSymbol `json:"symbol"`
+}
+
+// ShipMount: A mount is installed on the exterier of a ship.
+type ShipMount struct {
+	// Mounts that have this value denote what goods can be produced from using the mount.
+	Deposits     []TradeSymbol    `json:"deposits,omitempty"`
+	Description  string           `json:"description,omitempty"`
+	Name         string           `json:"name"`
+	Requirements ShipRequirements `json:"requirements"`
+	// Mounts that have this value, such as mining lasers, denote how powerful this mount's capabilities are.
+	Strength int `json:"strength,omitempty"`
+	// Symbol of the mount.
+	Symbol TradeSymbol `json:"symbol"`
+}
+
+type ShipNav struct {
+	FlightMode     ShipNavFlightMode `json:"flightMode"`
+	Route          ShipNavRoute      `json:"route"`
+	Status         ShipNavStatus     `json:"status"`
+	SystemSymbol   string            `json:"systemSymbol"`
+	WaypointSymbol string            `json:"waypointSymbol"`
+}
+
+// ShipNavFlightMode: The ship's set speed when traveling between waypoints or systems.
+type ShipNavFlightMode string
+
+const (
+	ShipNavFlightModeDrift   ShipNavFlightMode = "DRIFT"
+	ShipNavFlightModeStealth ShipNavFlightMode = "STEALTH"
+	ShipNavFlightModeCruise  ShipNavFlightMode = "CRUISE"
+	ShipNavFlightModeBurn    ShipNavFlightMode = "BURN"
+)
+
+var ShipNavFlightModeValues = []ShipNavFlightMode{
+	ShipNavFlightModeDrift,
+	ShipNavFlightModeStealth,
+	ShipNavFlightModeCruise,
+	ShipNavFlightModeBurn,
+}
+
+func (v ShipNavFlightMode) Valid() bool {
+	for _, known := range ShipNavFlightModeValues {
+		if v == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+type ShipNavRoute struct {
+	Arrival       string               `json:"arrival"`
+	DepartureTime string               `json:"departureTime"`
+	Destination   ShipNavRouteWaypoint `json:"destination"`
+	Origin        ShipNavRouteWaypoint `json:"origin"`
+}
+
+type ShipNavRouteWaypoint struct {
+	Symbol       string       `json:"symbol"`
+	SystemSymbol string       `json:"systemSymbol"`
+	Type         WaypointType `json:"type"`
+	PosX         int          `json:"x"`
+	PosY         int          `json:"y"`
+}
+
+// ShipNavStatus: The current status of the ship
+type ShipNavStatus string
+
+const (
+	ShipNavStatusInTransit ShipNavStatus = "IN_TRANSIT"
+	ShipNavStatusInOrbit   ShipNavStatus = "IN_ORBIT"
+	ShipNavStatusDocked    ShipNavStatus = "DOCKED"
+)
+
+var ShipNavStatusValues = []ShipNavStatus{
+	ShipNavStatusInTransit,
+	ShipNavStatusInOrbit,
+	ShipNavStatusDocked,
+}
+
+func (v ShipNavStatus) Valid() bool {
+	for _, known := range ShipNavStatusValues {
+		if v == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ShipReactor: The reactor of the ship. The reactor is responsible for powering the ship's systems and weapons.
+type ShipReactor struct {
+	// The repairable condition of a component, from 0 to 1.
+	Condition   float64 `json:"condition"`
+	Description string  `json:"description"`
+	// The overall integrity of the component, from 0 to 1. Lost integrity can't be repaired.
+	Integrity float64 `json:"integrity"`
+	Name      string  `json:"name"`
+	// The amount of power provided by this reactor. The more power a reactor provides to the ship, the lower the cooldown it gets when using a module or mount that taxes the ship's power.
+	PowerOutput int `json:"powerOutput"`
+	// The overall quality of the component, which determines the quality of the component.
+	Quality      float64          `json:"quality"`
+	Requirements ShipRequirements `json:"requirements"`
+	// Symbol of the reactor.
+	Symbol TradeSymbol `json:"symbol"`
+}
+
+type ShipRegistration struct {
+	FactionSymbol string   `json:"factionSymbol"`
+	Name          string   `json:"name"`
+	Role          ShipRole `json:"role"`
+}
+
+// ShipRequirements: The requirements for installation on a ship
+type ShipRequirements struct {
+	// The number of crew required for operation.
+	Crew int `json:"crew,omitempty"`
+	// The amount of power required from the reactor.
+	Power int `json:"power,omitempty"`
+	// The number of module slots required for installation.
+	Slots int `json:"slots,omitempty"`
+}
+
+// ShipRole: The registered role of the ship
+type ShipRole string
+
+const (
+	ShipRoleFabricator  ShipRole = "FABRICATOR"
+	ShipRoleHarvester   ShipRole = "HARVESTER"
+	ShipRoleHauler      ShipRole = "HAULER"
+	ShipRoleInterceptor ShipRole = "INTERCEPTOR"
+	ShipRoleExcavator   ShipRole = "EXCAVATOR"
+	ShipRoleTransport   ShipRole = "TRANSPORT"
+	ShipRoleRepair      ShipRole = "REPAIR"
+	ShipRoleSurveyor    ShipRole = "SURVEYOR"
+	ShipRoleCommand     ShipRole = "COMMAND"
+	ShipRoleCarrier     ShipRole = "CARRIER"
+	ShipRolePatrol      ShipRole = "PATROL"
+	ShipRoleSatellite   ShipRole = "SATELLITE"
+	ShipRoleExplorer    ShipRole = "EXPLORER"
+	ShipRoleRefinery    ShipRole = "REFINERY"
+)
+
+var ShipRoleValues = []ShipRole{
+	ShipRoleFabricator,
+	ShipRoleHarvester,
+	ShipRoleHauler,
+	ShipRoleInterceptor,
+	ShipRoleExcavator,
+	ShipRoleTransport,
+	ShipRoleRepair,
+	ShipRoleSurveyor,
+	ShipRoleCommand,
+	ShipRoleCarrier,
+	ShipRolePatrol,
+	ShipRoleSatellite,
+	ShipRoleExplorer,
+	ShipRoleRefinery,
+}
+
+func (v ShipRole) Valid() bool {
+	for _, known := range ShipRoleValues {
+		if v == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Siphon: Siphon details.
+type Siphon struct {
+	ShipSymbol string      `json:"shipSymbol"`
+	Yield      SiphonYield `json:"yield"`
+}
+
+// SiphonYield: A yield from the siphon operation.
+type SiphonYield struct {
+	Symbol TradeSymbol `json:"symbol"`
+	Units  int         `json:"units"`
+}
+
+// Survey: A resource survey of a waypoint, detailing a specific extraction location and the types of resources that can be found there.
+type Survey struct {
+	Deposits   []SurveyDeposit `json:"deposits"`
+	Expiration string          `json:"expiration"`
+	Signature  string          `json:"signature"`
+	Size       SurveySize      `json:"size"`
+	Symbol     string          `json:"symbol"`
+}
+
+type SurveySize string
+
+const (
+	SurveySizeSmall    SurveySize = "SMALL"
+	SurveySizeModerate SurveySize = "MODERATE"
+	SurveySizeLarge    SurveySize = "LARGE"
+)
+
+var SurveySizeValues = []SurveySize{
+	SurveySizeSmall,
+	SurveySizeModerate,
+	SurveySizeLarge,
+}
+
+func (v SurveySize) Valid() bool {
+	for _, known := range SurveySizeValues {
+		if v == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SurveyDeposit: A surveyed deposit of a mineral or resource available for extraction.
+type SurveyDeposit struct {
+	Symbol TradeSymbol `json:"symbol"`
+}
+
+type System struct {
+	SectorSymbol string     `json:"sectorSymbol"`
+	Symbol       string     `json:"symbol"`
+	Type         string     `json:"type"`
+	Waypoints    []Waypoint `json:"waypoints"`
+	PosX         int        `json:"x"`
+	PosY         int        `json:"y"`
+}
+
+type SystemWaypoint struct {
+	Orbitals []WaypointOrbital `json:"orbitals"`
+	Orbits   string            `json:"orbits,omitempty"`
+	Symbol   string            `json:"symbol"`
+	Type     WaypointType      `json:"type"`
+	PosX     int               `json:"x"`
+	PosY     int               `json:"y"`
+}
+
+type TradeGood struct {
+	Description string      `json:"description"`
+	Name        string      `json:"name"`
+	Symbol      TradeSymbol `json:"symbol"`
+}
+
+// TradeSymbol: The good's symbol.
+type TradeSymbol string
+
+const (
+	TradeSymbolPreciousStones          TradeSymbol = "PRECIOUS_STONES"
+	TradeSymbolQuartzSand              TradeSymbol = "QUARTZ_SAND"
+	TradeSymbolSiliconCrystals         TradeSymbol = "SILICON_CRYSTALS"
+	TradeSymbolAmmoniaIce              TradeSymbol = "AMMONIA_ICE"
+	TradeSymbolLiquidHydrogen          TradeSymbol = "LIQUID_HYDROGEN"
+	TradeSymbolLiquidNitrogen          TradeSymbol = "LIQUID_NITROGEN"
+	TradeSymbolIceWater                TradeSymbol = "ICE_WATER"
+	TradeSymbolExoticMatter            TradeSymbol = "EXOTIC_MATTER"
+	TradeSymbolAdvancedCircuitry       TradeSymbol = "ADVANCED_CIRCUITRY"
+	TradeSymbolGravitonEmitters        TradeSymbol = "GRAVITON_EMITTERS"
+	TradeSymbolIron                    TradeSymbol = "IRON"
+	TradeSymbolIronOre                 TradeSymbol = "IRON_ORE"
+	TradeSymbolCopper                  TradeSymbol = "COPPER"
+	TradeSymbolCopperOre               TradeSymbol = "COPPER_ORE"
+	TradeSymbolAluminum                TradeSymbol = "ALUMINUM"
+	TradeSymbolAluminumOre             TradeSymbol = "ALUMINUM_ORE"
+	TradeSymbolSilver                  TradeSymbol = "SILVER"
+	TradeSymbolSilverOre               TradeSymbol = "SILVER_ORE"
+	TradeSymbolGold                    TradeSymbol = "GOLD"
+	TradeSymbolGoldOre                 TradeSymbol = "GOLD_ORE"
+	TradeSymbolPlatinum                TradeSymbol = "PLATINUM"
+	TradeSymbolPlatinumOre             TradeSymbol = "PLATINUM_ORE"
+	TradeSymbolDiamonds                TradeSymbol = "DIAMONDS"
+	TradeSymbolUranite                 TradeSymbol = "URANITE"
+	TradeSymbolUraniteOre              TradeSymbol = "URANITE_ORE"
+	TradeSymbolMeritium                TradeSymbol = "MERITIUM"
+	TradeSymbolMeritiumOre             TradeSymbol = "MERITIUM_ORE"
+	TradeSymbolHydrocarbon             TradeSymbol = "HYDROCARBON"
+	TradeSymbolAntimatter              TradeSymbol = "ANTIMATTER"
+	TradeSymbolFabMats                 TradeSymbol = "FAB_MATS"
+	TradeSymbolFertilizers             TradeSymbol = "FERTILIZERS"
+	TradeSymbolFabrics                 TradeSymbol = "FABRICS"
+	TradeSymbolFood                    TradeSymbol = "FOOD"
+	TradeSymbolJewelry                 TradeSymbol = "JEWELRY"
+	TradeSymbolMachinery               TradeSymbol = "MACHINERY"
+	TradeSymbolFirearms                TradeSymbol = "FIREARMS"
+	TradeSymbolAssaultRifles           TradeSymbol = "ASSAULT_RIFLES"
+	TradeSymbolMilitaryEquipment       TradeSymbol = "MILITARY_EQUIPMENT"
+	TradeSymbolExplosives              TradeSymbol = "EXPLOSIVES"
+	TradeSymbolLabInstruments          TradeSymbol = "LAB_INSTRUMENTS"
+	TradeSymbolAmmunition              TradeSymbol = "AMMUNITION"
+	TradeSymbolElectronics             TradeSymbol = "ELECTRONICS"
+	TradeSymbolShipPlating             TradeSymbol = "SHIP_PLATING"
+	TradeSymbolShipParts               TradeSymbol = "SHIP_PARTS"
+	TradeSymbolEquipment               TradeSymbol = "EQUIPMENT"
+	TradeSymbolFuel                    TradeSymbol = "FUEL"
+	TradeSymbolMedicine                TradeSymbol = "MEDICINE"
+	TradeSymbolDrugs                   TradeSymbol = "DRUGS"
+	TradeSymbolClothing                TradeSymbol = "CLOTHING"
+	TradeSymbolMicroprocessors         TradeSymbol = "MICROPROCESSORS"
+	TradeSymbolPlastics                TradeSymbol = "PLASTICS"
+	TradeSymbolPolynucleotides         TradeSymbol = "POLYNUCLEOTIDES"
+	TradeSymbolBiocomposites           TradeSymbol = "BIOCOMPOSITES"
+	TradeSymbolQuantumStabilizers      TradeSymbol = "QUANTUM_STABILIZERS"
+	TradeSymbolNanobots                TradeSymbol = "NANOBOTS"
+	TradeSymbolAiMainframes            TradeSymbol = "AI_MAINFRAMES"
+	TradeSymbolQuantumDrives           TradeSymbol = "QUANTUM_DRIVES"
+	TradeSymbolRoboticDrones           TradeSymbol = "ROBOTIC_DRONES"
+	TradeSymbolCyberImplants           TradeSymbol = "CYBER_IMPLANTS"
+	TradeSymbolGeneTherapeutics        TradeSymbol = "GENE_THERAPEUTICS"
+	TradeSymbolNeuralChips             TradeSymbol = "NEURAL_CHIPS"
+	TradeSymbolMoodRegulators          TradeSymbol = "MOOD_REGULATORS"
+	TradeSymbolViralAgents             TradeSymbol = "VIRAL_AGENTS"
+	TradeSymbolMicroFusionGenerators   TradeSymbol = "MICRO_FUSION_GENERATORS"
+	TradeSymbolSupergrains             TradeSymbol = "SUPERGRAINS"
+	TradeSymbolLaserRifles             TradeSymbol = "LASER_RIFLES"
+	TradeSymbolHolographics            TradeSymbol = "HOLOGRAPHICS"
+	TradeSymbolShipSalvage             TradeSymbol = "SHIP_SALVAGE"
+	TradeSymbolRelicTech               TradeSymbol = "RELIC_TECH"
+	TradeSymbolNovelLifeforms          TradeSymbol = "NOVEL_LIFEFORMS"
+	TradeSymbolBotanicalSpecimens      TradeSymbol = "BOTANICAL_SPECIMENS"
+	TradeSymbolCulturalArtifacts       TradeSymbol = "CULTURAL_ARTIFACTS"
+	TradeSymbolFrameProbe              TradeSymbol = "FRAME_PROBE"
+	TradeSymbolFrameDrone              TradeSymbol = "FRAME_DRONE"
+	TradeSymbolFrameInterceptor        TradeSymbol = "FRAME_INTERCEPTOR"
+	TradeSymbolFrameRacer              TradeSymbol = "FRAME_RACER"
+	TradeSymbolFrameFighter            TradeSymbol = "FRAME_FIGHTER"
+	TradeSymbolFrameFrigate            TradeSymbol = "FRAME_FRIGATE"
+	TradeSymbolFrameShuttle            TradeSymbol = "FRAME_SHUTTLE"
+	TradeSymbolFrameExplorer           TradeSymbol = "FRAME_EXPLORER"
+	TradeSymbolFrameMiner              TradeSymbol = "FRAME_MINER"
+	TradeSymbolFrameLightFreighter     TradeSymbol = "FRAME_LIGHT_FREIGHTER"
+	TradeSymbolFrameHeavyFreighter     TradeSymbol = "FRAME_HEAVY_FREIGHTER"
+	TradeSymbolFrameTransport          TradeSymbol = "FRAME_TRANSPORT"
+	TradeSymbolFrameDestroyer          TradeSymbol = "FRAME_DESTROYER"
+	TradeSymbolFrameCruiser            TradeSymbol = "FRAME_CRUISER"
+	TradeSymbolFrameCarrier            TradeSymbol = "FRAME_CARRIER"
+	TradeSymbolReactorSolarI           TradeSymbol = "REACTOR_SOLAR_I"
+	TradeSymbolReactorFusionI          TradeSymbol = "REACTOR_FUSION_I"
+	TradeSymbolReactorFissionI         TradeSymbol = "REACTOR_FISSION_I"
+	TradeSymbolReactorChemicalI        TradeSymbol = "REACTOR_CHEMICAL_I"
+	TradeSymbolReactorAntimatterI      TradeSymbol = "REACTOR_ANTIMATTER_I"
+	TradeSymbolEngineImpulseDriveI     TradeSymbol = "ENGINE_IMPULSE_DRIVE_I"
+	TradeSymbolEngineIonDriveI         TradeSymbol = "ENGINE_ION_DRIVE_I"
+	TradeSymbolEngineIonDriveIi        TradeSymbol = "ENGINE_ION_DRIVE_II"
+	TradeSymbolEngineHyperDriveI       TradeSymbol = "ENGINE_HYPER_DRIVE_I"
+	TradeSymbolModuleMineralProcessorI TradeSymbol = "MODULE_MINERAL_PROCESSOR_I"
+	TradeSymbolModuleGasProcessorI     TradeSymbol = "MODULE_GAS_PROCESSOR_I"
+	TradeSymbolModuleCargoHoldI        TradeSymbol = "MODULE_CARGO_HOLD_I"
+	TradeSymbolModuleCargoHoldIi       TradeSymbol = "MODULE_CARGO_HOLD_II"
+	TradeSymbolModuleCargoHoldIii      TradeSymbol = "MODULE_CARGO_HOLD_III"
+	TradeSymbolModuleCrewQuartersI     TradeSymbol = "MODULE_CREW_QUARTERS_I"
+	TradeSymbolModuleEnvoyQuartersI    TradeSymbol = "MODULE_ENVOY_QUARTERS_I"
+	TradeSymbolModulePassengerCabinI   TradeSymbol = "MODULE_PASSENGER_CABIN_I"
+	TradeSymbolModuleMicroRefineryI    TradeSymbol = "MODULE_MICRO_REFINERY_I"
+	TradeSymbolModuleScienceLabI       TradeSymbol = "MODULE_SCIENCE_LAB_I"
+	TradeSymbolModuleJumpDriveI        TradeSymbol = "MODULE_JUMP_DRIVE_I"
+	TradeSymbolModuleJumpDriveIi       TradeSymbol = "MODULE_JUMP_DRIVE_II"
+	TradeSymbolModuleJumpDriveIii      TradeSymbol = "MODULE_JUMP_DRIVE_III"
+	TradeSymbolModuleWarpDriveI        TradeSymbol = "MODULE_WARP_DRIVE_I"
+	TradeSymbolModuleWarpDriveIi       TradeSymbol = "MODULE_WARP_DRIVE_II"
+	TradeSymbolModuleWarpDriveIii      TradeSymbol = "MODULE_WARP_DRIVE_III"
+	TradeSymbolModuleShieldGeneratorI  TradeSymbol = "MODULE_SHIELD_GENERATOR_I"
+	TradeSymbolModuleShieldGeneratorIi TradeSymbol = "MODULE_SHIELD_GENERATOR_II"
+	TradeSymbolModuleOreRefineryI      TradeSymbol = "MODULE_ORE_REFINERY_I"
+	TradeSymbolModuleFuelRefineryI     TradeSymbol = "MODULE_FUEL_REFINERY_I"
+	TradeSymbolMountGasSiphonI         TradeSymbol = "MOUNT_GAS_SIPHON_I"
+	TradeSymbolMountGasSiphonIi        TradeSymbol = "MOUNT_GAS_SIPHON_II"
+	TradeSymbolMountGasSiphonIii       TradeSymbol = "MOUNT_GAS_SIPHON_III"
+	TradeSymbolMountSurveyorI          TradeSymbol = "MOUNT_SURVEYOR_I"
+	TradeSymbolMountSurveyorIi         TradeSymbol = "MOUNT_SURVEYOR_II"
+	TradeSymbolMountSurveyorIii        TradeSymbol = "MOUNT_SURVEYOR_III"
+	TradeSymbolMountSensorArrayI       TradeSymbol = "MOUNT_SENSOR_ARRAY_I"
+	TradeSymbolMountSensorArrayIi      TradeSymbol = "MOUNT_SENSOR_ARRAY_II"
+	TradeSymbolMountSensorArrayIii     TradeSymbol = "MOUNT_SENSOR_ARRAY_III"
+	TradeSymbolMountMiningLaserI       TradeSymbol = "MOUNT_MINING_LASER_I"
+	TradeSymbolMountMiningLaserIi      TradeSymbol = "MOUNT_MINING_LASER_II"
+	TradeSymbolMountMiningLaserIii     TradeSymbol = "MOUNT_MINING_LASER_III"
+	TradeSymbolMountLaserCannonI       TradeSymbol = "MOUNT_LASER_CANNON_I"
+	TradeSymbolMountMissileLauncherI   TradeSymbol = "MOUNT_MISSILE_LAUNCHER_I"
+	TradeSymbolMountTurretI            TradeSymbol = "MOUNT_TURRET_I"
+	TradeSymbolShipProbe               TradeSymbol = "SHIP_PROBE"
+	TradeSymbolShipMiningDrone         TradeSymbol = "SHIP_MINING_DRONE"
+	TradeSymbolShipSiphonDrone         TradeSymbol = "SHIP_SIPHON_DRONE"
+	TradeSymbolShipInterceptor         TradeSymbol = "SHIP_INTERCEPTOR"
+	TradeSymbolShipLightHauler         TradeSymbol = "SHIP_LIGHT_HAULER"
+	TradeSymbolShipCommandFrigate      TradeSymbol = "SHIP_COMMAND_FRIGATE"
+	TradeSymbolShipExplorer            TradeSymbol = "SHIP_EXPLORER"
+	TradeSymbolShipHeavyFreighter      TradeSymbol = "SHIP_HEAVY_FREIGHTER"
+	TradeSymbolShipLightShuttle        TradeSymbol = "SHIP_LIGHT_SHUTTLE"
+	TradeSymbolShipOreHound            TradeSymbol = "SHIP_ORE_HOUND"
+	TradeSymbolShipRefiningFreighter   TradeSymbol = "SHIP_REFINING_FREIGHTER"
+	TradeSymbolShipSurveyor            TradeSymbol = "SHIP_SURVEYOR"
+)
+
+var TradeSymbolValues = []TradeSymbol{
+	TradeSymbolPreciousStones,
+	TradeSymbolQuartzSand,
+	TradeSymbolSiliconCrystals,
+	TradeSymbolAmmoniaIce,
+	TradeSymbolLiquidHydrogen,
+	TradeSymbolLiquidNitrogen,
+	TradeSymbolIceWater,
+	TradeSymbolExoticMatter,
+	TradeSymbolAdvancedCircuitry,
+	TradeSymbolGravitonEmitters,
+	TradeSymbolIron,
+	TradeSymbolIronOre,
+	TradeSymbolCopper,
+	TradeSymbolCopperOre,
+	TradeSymbolAluminum,
+	TradeSymbolAluminumOre,
+	TradeSymbolSilver,
+	TradeSymbolSilverOre,
+	TradeSymbolGold,
+	TradeSymbolGoldOre,
+	TradeSymbolPlatinum,
+	TradeSymbolPlatinumOre,
+	TradeSymbolDiamonds,
+	TradeSymbolUranite,
+	TradeSymbolUraniteOre,
+	TradeSymbolMeritium,
+	TradeSymbolMeritiumOre,
+	TradeSymbolHydrocarbon,
+	TradeSymbolAntimatter,
+	TradeSymbolFabMats,
+	TradeSymbolFertilizers,
+	TradeSymbolFabrics,
+	TradeSymbolFood,
+	TradeSymbolJewelry,
+	TradeSymbolMachinery,
+	TradeSymbolFirearms,
+	TradeSymbolAssaultRifles,
+	TradeSymbolMilitaryEquipment,
+	TradeSymbolExplosives,
+	TradeSymbolLabInstruments,
+	TradeSymbolAmmunition,
+	TradeSymbolElectronics,
+	TradeSymbolShipPlating,
+	TradeSymbolShipParts,
+	TradeSymbolEquipment,
+	TradeSymbolFuel,
+	TradeSymbolMedicine,
+	TradeSymbolDrugs,
+	TradeSymbolClothing,
+	TradeSymbolMicroprocessors,
+	TradeSymbolPlastics,
+	TradeSymbolPolynucleotides,
+	TradeSymbolBiocomposites,
+	TradeSymbolQuantumStabilizers,
+	TradeSymbolNanobots,
+	TradeSymbolAiMainframes,
+	TradeSymbolQuantumDrives,
+	TradeSymbolRoboticDrones,
+	TradeSymbolCyberImplants,
+	TradeSymbolGeneTherapeutics,
+	TradeSymbolNeuralChips,
+	TradeSymbolMoodRegulators,
+	TradeSymbolViralAgents,
+	TradeSymbolMicroFusionGenerators,
+	TradeSymbolSupergrains,
+	TradeSymbolLaserRifles,
+	TradeSymbolHolographics,
+	TradeSymbolShipSalvage,
+	TradeSymbolRelicTech,
+	TradeSymbolNovelLifeforms,
+	TradeSymbolBotanicalSpecimens,
+	TradeSymbolCulturalArtifacts,
+	TradeSymbolFrameProbe,
+	TradeSymbolFrameDrone,
+	TradeSymbolFrameInterceptor,
+	TradeSymbolFrameRacer,
+	TradeSymbolFrameFighter,
+	TradeSymbolFrameFrigate,
+	TradeSymbolFrameShuttle,
+	TradeSymbolFrameExplorer,
+	TradeSymbolFrameMiner,
+	TradeSymbolFrameLightFreighter,
+	TradeSymbolFrameHeavyFreighter,
+	TradeSymbolFrameTransport,
+	TradeSymbolFrameDestroyer,
+	TradeSymbolFrameCruiser,
+	TradeSymbolFrameCarrier,
+	TradeSymbolReactorSolarI,
+	TradeSymbolReactorFusionI,
+	TradeSymbolReactorFissionI,
+	TradeSymbolReactorChemicalI,
+	TradeSymbolReactorAntimatterI,
+	TradeSymbolEngineImpulseDriveI,
+	TradeSymbolEngineIonDriveI,
+	TradeSymbolEngineIonDriveIi,
+	TradeSymbolEngineHyperDriveI,
+	TradeSymbolModuleMineralProcessorI,
+	TradeSymbolModuleGasProcessorI,
+	TradeSymbolModuleCargoHoldI,
+	TradeSymbolModuleCargoHoldIi,
+	TradeSymbolModuleCargoHoldIii,
+	TradeSymbolModuleCrewQuartersI,
+	TradeSymbolModuleEnvoyQuartersI,
+	TradeSymbolModulePassengerCabinI,
+	TradeSymbolModuleMicroRefineryI,
+	TradeSymbolModuleScienceLabI,
+	TradeSymbolModuleJumpDriveI,
+	TradeSymbolModuleJumpDriveIi,
+	TradeSymbolModuleJumpDriveIii,
+	TradeSymbolModuleWarpDriveI,
+	TradeSymbolModuleWarpDriveIi,
+	TradeSymbolModuleWarpDriveIii,
+	TradeSymbolModuleShieldGeneratorI,
+	TradeSymbolModuleShieldGeneratorIi,
+	TradeSymbolModuleOreRefineryI,
+	TradeSymbolModuleFuelRefineryI,
+	TradeSymbolMountGasSiphonI,
+	TradeSymbolMountGasSiphonIi,
+	TradeSymbolMountGasSiphonIii,
+	TradeSymbolMountSurveyorI,
+	TradeSymbolMountSurveyorIi,
+	TradeSymbolMountSurveyorIii,
+	TradeSymbolMountSensorArrayI,
+	TradeSymbolMountSensorArrayIi,
+	TradeSymbolMountSensorArrayIii,
+	TradeSymbolMountMiningLaserI,
+	TradeSymbolMountMiningLaserIi,
+	TradeSymbolMountMiningLaserIii,
+	TradeSymbolMountLaserCannonI,
+	TradeSymbolMountMissileLauncherI,
+	TradeSymbolMountTurretI,
+	TradeSymbolShipProbe,
+	TradeSymbolShipMiningDrone,
+	TradeSymbolShipSiphonDrone,
+	TradeSymbolShipInterceptor,
+	TradeSymbolShipLightHauler,
+	TradeSymbolShipCommandFrigate,
+	TradeSymbolShipExplorer,
+	TradeSymbolShipHeavyFreighter,
+	TradeSymbolShipLightShuttle,
+	TradeSymbolShipOreHound,
+	TradeSymbolShipRefiningFreighter,
+	TradeSymbolShipSurveyor,
+}
+
+func (v TradeSymbol) Valid() bool {
+	for _, known := range TradeSymbolValues {
+		if v == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+type Waypoint struct {
+	Chart               Chart              `json:"chart,omitempty"`
+	Faction             WaypointFaction    `json:"faction,omitempty"`
+	IsUnderConstruction bool               `json:"isUnderConstruction"`
+	Modifiers           []WaypointModifier `json:"modifiers,omitempty"`
+	Orbitals            []WaypointOrbital  `json:"orbitals"`
+	Orbits              string             `json:"orbits,omitempty"`
+	Symbol              string             `json:"symbol"`
+	SystemSymbol        string             `json:"systemSymbol"`
+	Traits              []WaypointTrait    `json:"traits"`
+	Type                WaypointType       `json:"type"`
+	PosX                int                `json:"x"`
+	PosY                int                `json:"y"`
+}
+
+type WaypointFaction struct {
+	Symbol FactionSymbol `json:"symbol"`
+}
+
+type WaypointModifier struct {
+	Description string                 `json:"description"`
+	Name        string                 `json:"name"`
+	Symbol      WaypointModifierSymbol `json:"symbol"`
+}
+
+type WaypointModifierSymbol string
+
+const (
+	WaypointModifierSymbolStripped      WaypointModifierSymbol = "STRIPPED"
+	WaypointModifierSymbolUnstable      WaypointModifierSymbol = "UNSTABLE"
+	WaypointModifierSymbolRadiationLeak WaypointModifierSymbol = "RADIATION_LEAK"
+	WaypointModifierSymbolCriticalLimit WaypointModifierSymbol = "CRITICAL_LIMIT"
+	WaypointModifierSymbolCivilUnrest   WaypointModifierSymbol = "CIVIL_UNREST"
+)
+
+var WaypointModifierSymbolValues = []WaypointModifierSymbol{
+	WaypointModifierSymbolStripped,
+	WaypointModifierSymbolUnstable,
+	WaypointModifierSymbolRadiationLeak,
+	WaypointModifierSymbolCriticalLimit,
+	WaypointModifierSymbolCivilUnrest,
+}
+
+func (v WaypointModifierSymbol) Valid() bool {
+	for _, known := range WaypointModifierSymbolValues {
+		if v == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+type WaypointOrbital struct {
+	Symbol string `json:"symbol"`
+}
+
+type WaypointTrait struct {
+	Description string              `json:"description"`
+	Name        string              `json:"name"`
+	Symbol      WaypointTraitSymbol `json:"symbol"`
+}
+
+// WaypointTraitSymbol: The unique identifier of the trait.
+type WaypointTraitSymbol string
+
+const (
+	WaypointTraitSymbolUncharted             WaypointTraitSymbol = "UNCHARTED"
+	WaypointTraitSymbolUnderConstruction     WaypointTraitSymbol = "UNDER_CONSTRUCTION"
+	WaypointTraitSymbolMarketplace           WaypointTraitSymbol = "MARKETPLACE"
+	WaypointTraitSymbolShipyard              WaypointTraitSymbol = "SHIPYARD"
+	WaypointTraitSymbolOutpost               WaypointTraitSymbol = "OUTPOST"
+	WaypointTraitSymbolScatteredSettlements  WaypointTraitSymbol = "SCATTERED_SETTLEMENTS"
+	WaypointTraitSymbolSprawlingCities       WaypointTraitSymbol = "SPRAWLING_CITIES"
+	WaypointTraitSymbolMegaStructures        WaypointTraitSymbol = "MEGA_STRUCTURES"
+	WaypointTraitSymbolPirateBase            WaypointTraitSymbol = "PIRATE_BASE"
+	WaypointTraitSymbolOvercrowded           WaypointTraitSymbol = "OVERCROWDED"
+	WaypointTraitSymbolHighTech              WaypointTraitSymbol = "HIGH_TECH"
+	WaypointTraitSymbolCorrupt               WaypointTraitSymbol = "CORRUPT"
+	WaypointTraitSymbolBureaucratic          WaypointTraitSymbol = "BUREAUCRATIC"
+	WaypointTraitSymbolTradingHub            WaypointTraitSymbol = "TRADING_HUB"
+	WaypointTraitSymbolIndustrial            WaypointTraitSymbol = "INDUSTRIAL"
+	WaypointTraitSymbolBlackMarket           WaypointTraitSymbol = "BLACK_MARKET"
+	WaypointTraitSymbolResearchFacility      WaypointTraitSymbol = "RESEARCH_FACILITY"
+	WaypointTraitSymbolMilitaryBase          WaypointTraitSymbol = "MILITARY_BASE"
+	WaypointTraitSymbolSurveillanceOutpost   WaypointTraitSymbol = "SURVEILLANCE_OUTPOST"
+	WaypointTraitSymbolExplorationOutpost    WaypointTraitSymbol = "EXPLORATION_OUTPOST"
+	WaypointTraitSymbolMineralDeposits       WaypointTraitSymbol = "MINERAL_DEPOSITS"
+	WaypointTraitSymbolCommonMetalDeposits   WaypointTraitSymbol = "COMMON_METAL_DEPOSITS"
+	WaypointTraitSymbolPreciousMetalDeposits WaypointTraitSymbol = "PRECIOUS_METAL_DEPOSITS"
+	WaypointTraitSymbolRareMetalDeposits     WaypointTraitSymbol = "RARE_METAL_DEPOSITS"
+	WaypointTraitSymbolMethanePools          WaypointTraitSymbol = "METHANE_POOLS"
+	WaypointTraitSymbolIceCrystals           WaypointTraitSymbol = "ICE_CRYSTALS"
+	WaypointTraitSymbolExplosiveGases        WaypointTraitSymbol = "EXPLOSIVE_GASES"
+	WaypointTraitSymbolStrongMagnetosphere   WaypointTraitSymbol = "STRONG_MAGNETOSPHERE"
+	WaypointTraitSymbolVibrantAuroras        WaypointTraitSymbol = "VIBRANT_AURORAS"
+	WaypointTraitSymbolSaltFlats             WaypointTraitSymbol = "SALT_FLATS"
+	WaypointTraitSymbolCanyons               WaypointTraitSymbol = "CANYONS"
+	WaypointTraitSymbolPerpetualDaylight     WaypointTraitSymbol = "PERPETUAL_DAYLIGHT"
+	WaypointTraitSymbolPerpetualOvercast     WaypointTraitSymbol = "PERPETUAL_OVERCAST"
+	WaypointTraitSymbolDrySeabeds            WaypointTraitSymbol = "DRY_SEABEDS"
+	WaypointTraitSymbolMagmaSeas             WaypointTraitSymbol = "MAGMA_SEAS"
+	WaypointTraitSymbolSupervolcanoes        WaypointTraitSymbol = "SUPERVOLCANOES"
+	WaypointTraitSymbolAshClouds             WaypointTraitSymbol = "ASH_CLOUDS"
+	WaypointTraitSymbolVastRuins             WaypointTraitSymbol = "VAST_RUINS"
+	WaypointTraitSymbolMutatedFlora          WaypointTraitSymbol = "MUTATED_FLORA"
+	WaypointTraitSymbolTerraformed           WaypointTraitSymbol = "TERRAFORMED"
+	WaypointTraitSymbolExtremeTemperatures   WaypointTraitSymbol = "EXTREME_TEMPERATURES"
+	WaypointTraitSymbolExtremePressure       WaypointTraitSymbol = "EXTREME_PRESSURE"
+	WaypointTraitSymbolDiverseLife           WaypointTraitSymbol = "DIVERSE_LIFE"
+	WaypointTraitSymbolScarceLife            WaypointTraitSymbol = "SCARCE_LIFE"
+	WaypointTraitSymbolFossils               WaypointTraitSymbol = "FOSSILS"
+	WaypointTraitSymbolWeakGravity           WaypointTraitSymbol = "WEAK_GRAVITY"
+	WaypointTraitSymbolStrongGravity         WaypointTraitSymbol = "STRONG_GRAVITY"
+	WaypointTraitSymbolCrushingGravity       WaypointTraitSymbol = "CRUSHING_GRAVITY"
+	WaypointTraitSymbolToxicAtmosphere       WaypointTraitSymbol = "TOXIC_ATMOSPHERE"
+	WaypointTraitSymbolCorrosiveAtmosphere   WaypointTraitSymbol = "CORROSIVE_ATMOSPHERE"
+	WaypointTraitSymbolBreathableAtmosphere  WaypointTraitSymbol = "BREATHABLE_ATMOSPHERE"
+	WaypointTraitSymbolThinAtmosphere        WaypointTraitSymbol = "THIN_ATMOSPHERE"
+	WaypointTraitSymbolJovian                WaypointTraitSymbol = "JOVIAN"
+	WaypointTraitSymbolRocky                 WaypointTraitSymbol = "ROCKY"
+	WaypointTraitSymbolVolcanic              WaypointTraitSymbol = "VOLCANIC"
+	WaypointTraitSymbolFrozen                WaypointTraitSymbol = "FROZEN"
+	WaypointTraitSymbolSwamp                 WaypointTraitSymbol = "SWAMP"
+	WaypointTraitSymbolBarren                WaypointTraitSymbol = "BARREN"
+	WaypointTraitSymbolTemperate             WaypointTraitSymbol = "TEMPERATE"
+	WaypointTraitSymbolJungle                WaypointTraitSymbol = "JUNGLE"
+	WaypointTraitSymbolOcean                 WaypointTraitSymbol = "OCEAN"
+	WaypointTraitSymbolRadioactive           WaypointTraitSymbol = "RADIOACTIVE"
+	WaypointTraitSymbolMicroGravityAnomalies WaypointTraitSymbol = "MICRO_GRAVITY_ANOMALIES"
+	WaypointTraitSymbolDebrisCluster         WaypointTraitSymbol = "DEBRIS_CLUSTER"
+	WaypointTraitSymbolDeepCraters           WaypointTraitSymbol = "DEEP_CRATERS"
+	WaypointTraitSymbolShallowCraters        WaypointTraitSymbol = "SHALLOW_CRATERS"
+	WaypointTraitSymbolUnstableComposition   WaypointTraitSymbol = "UNSTABLE_COMPOSITION"
+	WaypointTraitSymbolHollowedInterior      WaypointTraitSymbol = "HOLLOWED_INTERIOR"
+	WaypointTraitSymbolStripped              WaypointTraitSymbol = "STRIPPED"
+)
+
+var WaypointTraitSymbolValues = []WaypointTraitSymbol{
+	WaypointTraitSymbolUncharted,
+	WaypointTraitSymbolUnderConstruction,
+	WaypointTraitSymbolMarketplace,
+	WaypointTraitSymbolShipyard,
+	WaypointTraitSymbolOutpost,
+	WaypointTraitSymbolScatteredSettlements,
+	WaypointTraitSymbolSprawlingCities,
+	WaypointTraitSymbolMegaStructures,
+	WaypointTraitSymbolPirateBase,
+	WaypointTraitSymbolOvercrowded,
+	WaypointTraitSymbolHighTech,
+	WaypointTraitSymbolCorrupt,
+	WaypointTraitSymbolBureaucratic,
+	WaypointTraitSymbolTradingHub,
+	WaypointTraitSymbolIndustrial,
+	WaypointTraitSymbolBlackMarket,
+	WaypointTraitSymbolResearchFacility,
+	WaypointTraitSymbolMilitaryBase,
+	WaypointTraitSymbolSurveillanceOutpost,
+	WaypointTraitSymbolExplorationOutpost,
+	WaypointTraitSymbolMineralDeposits,
+	WaypointTraitSymbolCommonMetalDeposits,
+	WaypointTraitSymbolPreciousMetalDeposits,
+	WaypointTraitSymbolRareMetalDeposits,
+	WaypointTraitSymbolMethanePools,
+	WaypointTraitSymbolIceCrystals,
+	WaypointTraitSymbolExplosiveGases,
+	WaypointTraitSymbolStrongMagnetosphere,
+	WaypointTraitSymbolVibrantAuroras,
+	WaypointTraitSymbolSaltFlats,
+	WaypointTraitSymbolCanyons,
+	WaypointTraitSymbolPerpetualDaylight,
+	WaypointTraitSymbolPerpetualOvercast,
+	WaypointTraitSymbolDrySeabeds,
+	WaypointTraitSymbolMagmaSeas,
+	WaypointTraitSymbolSupervolcanoes,
+	WaypointTraitSymbolAshClouds,
+	WaypointTraitSymbolVastRuins,
+	WaypointTraitSymbolMutatedFlora,
+	WaypointTraitSymbolTerraformed,
+	WaypointTraitSymbolExtremeTemperatures,
+	WaypointTraitSymbolExtremePressure,
+	WaypointTraitSymbolDiverseLife,
+	WaypointTraitSymbolScarceLife,
+	WaypointTraitSymbolFossils,
+	WaypointTraitSymbolWeakGravity,
+	WaypointTraitSymbolStrongGravity,
+	WaypointTraitSymbolCrushingGravity,
+	WaypointTraitSymbolToxicAtmosphere,
+	WaypointTraitSymbolCorrosiveAtmosphere,
+	WaypointTraitSymbolBreathableAtmosphere,
+	WaypointTraitSymbolThinAtmosphere,
+	WaypointTraitSymbolJovian,
+	WaypointTraitSymbolRocky,
+	WaypointTraitSymbolVolcanic,
+	WaypointTraitSymbolFrozen,
+	WaypointTraitSymbolSwamp,
+	WaypointTraitSymbolBarren,
+	WaypointTraitSymbolTemperate,
+	WaypointTraitSymbolJungle,
+	WaypointTraitSymbolOcean,
+	WaypointTraitSymbolRadioactive,
+	WaypointTraitSymbolMicroGravityAnomalies,
+	WaypointTraitSymbolDebrisCluster,
+	WaypointTraitSymbolDeepCraters,
+	WaypointTraitSymbolShallowCraters,
+	WaypointTraitSymbolUnstableComposition,
+	WaypointTraitSymbolHollowedInterior,
+	WaypointTraitSymbolStripped,
+}
+
+func (v WaypointTraitSymbol) Valid() bool {
+	for _, known := range WaypointTraitSymbolValues {
+		if v == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WaypointType: The type of waypoint.
+type WaypointType string
+
+const (
+	WaypointTypePlanet                WaypointType = "PLANET"
+	WaypointTypeGasGiant              WaypointType = "GAS_GIANT"
+	WaypointTypeMoon                  WaypointType = "MOON"
+	WaypointTypeOrbitalStation        WaypointType = "ORBITAL_STATION"
+	WaypointTypeJumpGate              WaypointType = "JUMP_GATE"
+	WaypointTypeAsteroidField         WaypointType = "ASTEROID_FIELD"
+	WaypointTypeAsteroid              WaypointType = "ASTEROID"
+	WaypointTypeEngineeredAsteroid    WaypointType = "ENGINEERED_ASTEROID"
+	WaypointTypeAsteroidBase          WaypointType = "ASTEROID_BASE"
+	WaypointTypeNebula                WaypointType = "NEBULA"
+	WaypointTypeDebrisField           WaypointType = "DEBRIS_FIELD"
+	WaypointTypeGravityWell           WaypointType = "GRAVITY_WELL"
+	WaypointTypeArtificialGravityWell WaypointType = "ARTIFICIAL_GRAVITY_WELL"
+	WaypointTypeFuelStation           WaypointType = "FUEL_STATION"
+)
+
+var WaypointTypeValues = []WaypointType{
+	WaypointTypePlanet,
+	WaypointTypeGasGiant,
+	WaypointTypeMoon,
+	WaypointTypeOrbitalStation,
+	WaypointTypeJumpGate,
+	WaypointTypeAsteroidField,
+	WaypointTypeAsteroid,
+	WaypointTypeEngineeredAsteroid,
+	WaypointTypeAsteroidBase,
+	WaypointTypeNebula,
+	WaypointTypeDebrisField,
+	WaypointTypeGravityWell,
+	WaypointTypeArtificialGravityWell,
+	WaypointTypeFuelStation,
+}
+
+func (v WaypointType) Valid() bool {
+	for _, known := range WaypointTypeValues {
+		if v == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+type ContractUpdate struct {
+	Agent    Agent    `json:"agent"`
+	Contract Contract `json:"contract"`
+}
+
+type DeliverContractRequest struct {
+	ShipSymbol  string      `json:"shipSymbol"`
+	TradeSymbol TradeSymbol `json:"tradeSymbol"`
+	Units       int         `json:"units"`
+}
+
+type ContractDeliveryUpdate struct {
+	Cargo    ShipCargo `json:"cargo"`
+	Contract Contract  `json:"contract"`
+}
+
+type Extract struct {
+	Cargo      ShipCargo  `json:"cargo"`
+	Cooldown   Cooldown   `json:"cooldown"`
+	Extraction Extraction `json:"extraction"`
+}
+
+type JettisonRequest struct {
+	Symbol TradeSymbol `json:"symbol"`
+	Units  int         `json:"units"`
+}
+
+type JettisonResult struct {
+	Cargo ShipCargo `json:"cargo"`
+}
+
+type JumpShipRequest struct {
+	WaypointSymbol string `json:"waypointSymbol"`
+}
+
+type ShipJump struct {
+	Agent       Agent             `json:"agent"`
+	Cooldown    Cooldown          `json:"cooldown"`
+	Nav         ShipNav           `json:"nav"`
+	Transaction MarketTransaction `json:"transaction"`
+}
+
+type InstallShipModuleRequest struct {
+	Symbol TradeSymbol `json:"symbol"`
+}
+
+type ModuleChange struct {
+	Agent       Agent                       `json:"agent"`
+	Cargo       ShipCargo                   `json:"cargo"`
+	Modules     []ShipModule                `json:"modules"`
+	Transaction ShipModificationTransaction `json:"transaction"`
+}
+
+type RemoveShipModuleRequest struct {
+	Symbol TradeSymbol `json:"symbol"`
+}
+
+type InstallMountRequest struct {
+	Symbol TradeSymbol `json:"symbol"`
+}
+
+type MountChange struct {
+	Agent       Agent                       `json:"agent"`
+	Cargo       ShipCargo                   `json:"cargo"`
+	Mounts      []ShipMount                 `json:"mounts"`
+	Transaction ShipModificationTransaction `json:"transaction"`
+}
+
+type RemoveMountRequest struct {
+	Symbol TradeSymbol `json:"symbol"`
+}
+
+type PatchShipNavRequest struct {
+	FlightMode ShipNavFlightMode `json:"flightMode,omitempty"`
+}
+
+type NavigateShipRequest struct {
+	WaypointSymbol string `json:"waypointSymbol"`
+}
+
+type ShipTransit struct {
+	Fuel ShipFuel `json:"fuel"`
+	Nav  ShipNav  `json:"nav"`
+}
+
+type PurchaseCargoRequest struct {
+	Symbol TradeSymbol `json:"symbol"`
+	Units  int         `json:"units"`
+}
+
+type ShipRefineRequest struct {
+	Produce ShipRefineRequestProduce `json:"produce"`
+}
+
+type ShipRefineRequestProduce string
+
+const (
+	ShipRefineRequestProduceIron     ShipRefineRequestProduce = "IRON"
+	ShipRefineRequestProduceCopper   ShipRefineRequestProduce = "COPPER"
+	ShipRefineRequestProduceSilver   ShipRefineRequestProduce = "SILVER"
+	ShipRefineRequestProduceGold     ShipRefineRequestProduce = "GOLD"
+	ShipRefineRequestProduceAluminum ShipRefineRequestProduce = "ALUMINUM"
+	ShipRefineRequestProducePlatinum ShipRefineRequestProduce = "PLATINUM"
+	ShipRefineRequestProduceUranite  ShipRefineRequestProduce = "URANITE"
+	ShipRefineRequestProduceMeritium ShipRefineRequestProduce = "MERITIUM"
+	ShipRefineRequestProduceFuel     ShipRefineRequestProduce = "FUEL"
+)
+
+var ShipRefineRequestProduceValues = []ShipRefineRequestProduce{
+	ShipRefineRequestProduceIron,
+	ShipRefineRequestProduceCopper,
+	ShipRefineRequestProduceSilver,
+	ShipRefineRequestProduceGold,
+	ShipRefineRequestProduceAluminum,
+	ShipRefineRequestProducePlatinum,
+	ShipRefineRequestProduceUranite,
+	ShipRefineRequestProduceMeritium,
+	ShipRefineRequestProduceFuel,
+}
+
+func (v ShipRefineRequestProduce) Valid() bool {
+	for _, known := range ShipRefineRequestProduceValues {
+		if v == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+type Refinement struct {
+	Cargo    ShipCargo `json:"cargo"`
+	Consumed []Yield   `json:"consumed"`
+	Cooldown Cooldown  `json:"cooldown"`
+	Produced []Yield   `json:"produced"`
+}
+
+type Yield struct {
+	TradeSymbol TradeSymbol `json:"tradeSymbol"`
+	Units       int         `json:"units"`
+}
+
+type RefuelShipRequest struct {
+	FromCargo bool `json:"fromCargo,omitempty"`
+	Units     int  `json:"units,omitempty"`
+}
+
+type ShipRefuel struct {
+	Agent       Agent             `json:"agent"`
+	Fuel        ShipFuel          `json:"fuel"`
+	Transaction MarketTransaction `json:"transaction"`
+}
+
+type GetRepairShipResult struct {
+	Transaction RepairTransaction `json:"transaction"`
+}
+
+type ShipRepair struct {
+	Agent       Agent             `json:"agent"`
+	Ship        Ship              `json:"ship"`
+	Transaction RepairTransaction `json:"transaction"`
+}
+
+type GetScrapShipResult struct {
+	Transaction ScrapTransaction `json:"transaction"`
+}
+
+type ShipScrap struct {
+	Agent       Agent            `json:"agent"`
+	Transaction ScrapTransaction `json:"transaction"`
+}
+
+type SellCargoRequest struct {
+	Symbol TradeSymbol `json:"symbol"`
+	Units  int         `json:"units"`
+}
+
+type CargoTrade struct {
+	Agent       Agent             `json:"agent"`
+	Cargo       ShipCargo         `json:"cargo"`
+	Transaction MarketTransaction `json:"transaction"`
+}
+
+type SiphonResourcesResult struct {
+	Cargo    ShipCargo `json:"cargo"`
+	Cooldown Cooldown  `json:"cooldown"`
+	Siphon   Siphon    `json:"siphon"`
+}
+
+type ShipSurvey struct {
+	Cooldown Cooldown `json:"cooldown"`
+	Surveys  []Survey `json:"surveys"`
+}
+
+type TransferCargoRequest struct {
+	ShipSymbol  string      `json:"shipSymbol"`
+	TradeSymbol TradeSymbol `json:"tradeSymbol"`
+	Units       int         `json:"units"`
+}
+
+type TransferCargoResult struct {
+	Cargo ShipCargo `json:"cargo"`
+}
+
+type WarpShipRequest struct {
+	WaypointSymbol string `json:"waypointSymbol"`
+}
+
+type SupplyConstructionRequest struct {
+	ShipSymbol  string      `json:"shipSymbol"`
+	TradeSymbol TradeSymbol `json:"tradeSymbol"`
+	Units       int         `json:"units"`
+}
+
+type ConstructionSupply struct {
+	Cargo        ShipCargo    `json:"cargo"`
+	Construction Construction `json:"construction"`
+}
@@ -0,0 +1,68 @@
+package spacetrader
+
+import (
+	"math"
+	"time"
+)
+
+// Distance is how far apart two points in a system (or two systems in the galaxy) are
+func Distance(x1 int, y1 int, x2 int, y2 int) float64 {
+	return math.Hypot(float64(x2-x1), float64(y2-y1))
+}
+
+// FuelCost follows the game's fuel formula for each flight mode. Drifting always costs a single unit
+// and burning costs double. Ships without a fuel tank don't use any, that's up to the caller to check.
+func FuelCost(mode FlightMode, distance float64) int {
+	rounded := int(math.Round(distance))
+
+	switch mode {
+	case FlightModeDrift:
+		return 1
+	case FlightModeBurn:
+		return max(2, 2*rounded)
+	default:
+		return max(1, rounded)
+	}
+}
+
+// TravelTime follows the game's travel time formula for an engine speed and flight mode
+func TravelTime(mode FlightMode, speed int, distance float64) time.Duration {
+	multiplier := 25.0
+	switch mode {
+	case FlightModeDrift:
+		multiplier = 250
+	case FlightModeBurn:
+		multiplier = 12.5
+	case FlightModeStealth:
+		multiplier = 30
+	}
+
+	speed = max(1, speed)
+	seconds := math.Round(math.Max(1, math.Round(distance))*(multiplier/float64(speed)) + 15)
+
+	return time.Duration(seconds) * time.Second
+}
+
+// WarpTime is how long a warp between systems takes. Warping works like navigating only a lot
+// slower, these multipliers are our best estimate rather than anything the game publishes.
+func WarpTime(mode FlightMode, speed int, distance float64) time.Duration {
+	multiplier := 50.0
+	switch mode {
+	case FlightModeDrift:
+		multiplier = 300
+	case FlightModeBurn:
+		multiplier = 25
+	}
+
+	speed = max(1, speed)
+	seconds := math.Round(math.Max(1, math.Round(distance))*(multiplier/float64(speed)) + 15)
+
+	return time.Duration(seconds) * time.Second
+}
+
+// JumpCooldown is our estimate of how long a ship's reactor needs after jumping, going up with
+// how far apart the two systems are. The game doesn't publish it, the jump itself hands back the
+// real one.
+func JumpCooldown(distance float64) time.Duration {
+	return time.Duration(max(60, math.Round(distance/10))) * time.Second
+}
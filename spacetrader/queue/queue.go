@@ -0,0 +1,558 @@
+// Package queue keeps a list of jobs that have to outlive the server. Every task is saved to a
+// JSON file whenever it changes, so whatever was waiting or halfway through when the server
+// stopped (or crashed) gets run again when it's next started.
+//
+// A task runs once it's due. If its handler fails it goes back in the queue after a pause that
+// doubles each time, and a task with a key can only be queued once at a time, so asking twice
+// for the same thing (a double click, a retry after a crash) doesn't do it twice:
+//
+//	tasks, err := queue.Open("tasks.json")
+//	tasks.Handle("mining", mine)
+//	tasks.Start()
+//	task, _ := queue.NewTask("mining", "mining:SHIP-1", job)
+//	tasks.Enqueue(task)
+//
+// Handlers may be run more than once for the same task, so they should work out where they're
+// up to from the game rather than assuming they're starting from scratch.
+//
+// It's meant for the handful of long running jobs one server has on the go, and has limits
+// that come with that:
+//
+//   - The whole queue is written out on every change, so it stays quick only while it holds
+//     tens or hundreds of tasks rather than thousands.
+//   - Only one process can use a file at a time, nothing stops a second one opening it and the
+//     two overwriting each other's saves.
+//   - A task runs at least once, not exactly once. One that was running when the server died
+//     is run again from the top.
+//   - Tasks don't run in any particular order, everything that's due starts at once.
+//   - Keys are only unique among tasks still to finish. Queueing a key again once its task has
+//     finished replaces the finished one.
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"example.com/spacetrader"
+)
+
+type State string
+
+const (
+	StatePending   State = "PENDING"
+	StateRunning   State = "RUNNING"
+	StateDone      State = "DONE"
+	StateFailed    State = "FAILED"
+	StateCancelled State = "CANCELLED"
+)
+
+// Finished reports whether a task in this state will never run again
+func (s State) Finished() bool {
+	return s == StateDone || s == StateFailed || s == StateCancelled
+}
+
+// Task is a job and how it's getting on
+type Task struct {
+	ID   int    `json:"id"`
+	Kind string `json:"kind"`
+	// Key stops the same job being queued twice while one is still pending or running, leave it
+	// empty to allow it. Only the latest task with a key is kept once it's finished.
+	Key     string          `json:"key,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	// ScheduledAt is when the task is next due, a zero time means straight away
+	ScheduledAt time.Time `json:"scheduledAt"`
+	// Attempts counts the failed runs since the task last ran for a good while, MaxAttempts gives
+	// up after that many and zero never does
+	Attempts    int       `json:"attempts"`
+	MaxAttempts int       `json:"maxAttempts"`
+	State       State     `json:"state"`
+	Error       string    `json:"error,omitempty"`
+	Created     time.Time `json:"created"`
+	Updated     time.Time `json:"updated"`
+}
+
+// NewTask sets up a task to queue, with payload encoded as JSON
+func NewTask(kind string, key string, payload any) (Task, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return Task{}, err
+	}
+
+	return Task{Kind: kind, Key: key, Payload: encoded}, nil
+}
+
+// Decode reads the task's payload into v
+func (t Task) Decode(v any) error {
+	return json.Unmarshal(t.Payload, v)
+}
+
+// Handler does a task's work. It should give up once ctx is done, which happens when the task
+// is cancelled or the queue is shutting down.
+type Handler func(ctx context.Context, task Task) error
+
+// permanent is an error trying again won't fix
+type permanent struct {
+	err error
+}
+
+func (p permanent) Error() string {
+	return p.err.Error()
+}
+
+func (p permanent) Unwrap() error {
+	return p.err
+}
+
+// Permanent marks err as one that trying again won't fix, a handler returning it fails its
+// task for good however many attempts it has left
+func Permanent(err error) error {
+	return permanent{err}
+}
+
+// running is a task's handler at work
+type running struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// handling is the context key a handler's ctx holds its own running under
+type handling struct{}
+
+// Queue runs tasks, each on its own goroutine
+type Queue struct {
+	// Now is the clock tasks fall due by
+	Now func() time.Time
+	// Backoff is the pause before a failed task is tried again, doubling every time up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Retention is how long finished tasks are kept around to be looked at
+	Retention time.Duration
+
+	path string
+
+	mu       sync.Mutex
+	tasks    map[int]*Task
+	nextID   int
+	handlers map[string]Handler
+	running  map[int]*running
+	ctx      context.Context
+	stop     context.CancelFunc
+	wake     chan struct{}
+	workers  sync.WaitGroup
+}
+
+// Open loads any tasks saved at path, nothing gets run until Start is called. Tasks that were
+// running when the queue was last saved are put back as pending, they never got to finish.
+func Open(path string) (*Queue, error) {
+	q := &Queue{
+		Now:        time.Now,
+		Backoff:    10 * time.Second,
+		MaxBackoff: 5 * time.Minute,
+		Retention:  24 * time.Hour,
+		path:       path,
+		tasks:      map[int]*Task{},
+		nextID:     1,
+		handlers:   map[string]Handler{},
+		running:    map[int]*running{},
+		wake:       make(chan struct{}, 1),
+	}
+
+	encoded, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := []*Task{}
+	if err := json.Unmarshal(encoded, &tasks); err != nil {
+		return nil, fmt.Errorf("queue: could not read %s: %w", path, err)
+	}
+
+	for _, task := range tasks {
+		if task.State == StateRunning {
+			task.State = StatePending
+		}
+		q.tasks[task.ID] = task
+		q.nextID = max(q.nextID, task.ID+1)
+	}
+
+	return q, nil
+}
+
+// Handle sets the handler for a kind of task. Tasks of a kind nobody handles wait in the queue.
+func (q *Queue) Handle(kind string, handler Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.handlers[kind] = handler
+	q.poke()
+}
+
+// Enqueue adds a task, which runs once it's due. If there's already a pending or running task
+// with the same key that one's handed back instead, and it's false. A finished task with the
+// same key is dropped in favour of the new one.
+func (q *Queue) Enqueue(task Task) (Task, bool, error) {
+	if task.Kind == "" {
+		return Task{}, false, fmt.Errorf("a task needs a kind")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if existing, ok := q.live(task.Key); ok {
+		return *existing, false, nil
+	}
+
+	replaced := map[int]*Task{}
+	for id, finished := range q.tasks {
+		if task.Key != "" && finished.Key == task.Key {
+			replaced[id] = finished
+			delete(q.tasks, id)
+		}
+	}
+
+	now := q.Now()
+	task.ID = q.nextID
+	task.State, task.Attempts, task.Error = StatePending, 0, ""
+	task.Created, task.Updated = now, now
+	if task.ScheduledAt.IsZero() {
+		task.ScheduledAt = now
+	}
+
+	q.nextID++
+	q.tasks[task.ID] = &task
+	if err := q.save(); err != nil {
+		delete(q.tasks, task.ID)
+		for id, finished := range replaced {
+			q.tasks[id] = finished
+		}
+		return Task{}, false, err
+	}
+	q.poke()
+
+	return task, true, nil
+}
+
+// Cancel stops the pending or running task with key, waiting for its handler to give up. It's
+// false if there was nothing to cancel. A handler cancelling its own task has to pass its own
+// ctx, otherwise it would wait on itself forever.
+func (q *Queue) Cancel(ctx context.Context, key string) (bool, error) {
+	q.mu.Lock()
+
+	task, ok := q.live(key)
+	if !ok {
+		q.mu.Unlock()
+		return false, nil
+	}
+
+	task.State, task.Updated = StateCancelled, q.Now()
+	err := q.save()
+
+	r, busy := q.running[task.ID]
+	q.mu.Unlock()
+
+	if busy {
+		r.cancel()
+		if self, _ := ctx.Value(handling{}).(*running); self != r {
+			<-r.done
+		}
+	}
+
+	return true, err
+}
+
+// Task hands back a copy of the latest task with key, finished or not
+func (q *Queue) Task(key string) (Task, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var latest *Task
+	for _, task := range q.tasks {
+		if task.Key == key && (latest == nil || task.ID > latest.ID) {
+			latest = task
+		}
+	}
+	if latest == nil {
+		return Task{}, false
+	}
+
+	return *latest, true
+}
+
+// Tasks lists every task the queue remembers, oldest first
+func (q *Queue) Tasks() []Task {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	tasks := []Task{}
+	for _, task := range q.tasks {
+		tasks = append(tasks, *task)
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+
+	return tasks
+}
+
+// Start begins running tasks as they fall due
+func (q *Queue) Start() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.stop != nil {
+		return
+	}
+
+	q.ctx, q.stop = context.WithCancel(context.Background())
+	q.workers.Add(1)
+	go q.dispatch(q.ctx)
+}
+
+// Shutdown stops every handler and waits for them to give up. The tasks they were running are
+// left pending, so they carry on the next time the queue is started.
+func (q *Queue) Shutdown() {
+	q.mu.Lock()
+	if q.stop == nil {
+		q.mu.Unlock()
+		return
+	}
+	q.stop()
+	for _, r := range q.running {
+		r.cancel()
+	}
+	q.mu.Unlock()
+
+	q.workers.Wait()
+}
+
+// dispatch launches tasks as they fall due, until the queue shuts down
+func (q *Queue) dispatch(ctx context.Context) {
+	defer q.workers.Done()
+
+	for {
+		next := q.launch(ctx)
+
+		// Nothing's due at all, wait to be poked
+		wait := time.Hour
+		if !next.IsZero() {
+			wait = next.Sub(q.Now())
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-q.wake:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// launch starts every task that's due and has a handler, and says when the next one falls due
+func (q *Queue) launch(ctx context.Context) time.Time {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if ctx.Err() != nil {
+		return time.Time{}
+	}
+
+	now := q.Now()
+	changed := false
+	var next time.Time
+	for id, task := range q.tasks {
+		if task.State.Finished() && now.Sub(task.Updated) > q.Retention {
+			delete(q.tasks, id)
+			changed = true
+			continue
+		}
+
+		handler, ok := q.handlers[task.Kind]
+		if task.State != StatePending || !ok {
+			continue
+		}
+		if task.ScheduledAt.After(now) {
+			if next.IsZero() || task.ScheduledAt.Before(next) {
+				next = task.ScheduledAt
+			}
+			continue
+		}
+
+		task.State, task.Updated = StateRunning, now
+		changed = true
+
+		taskCtx, cancel := context.WithCancel(ctx)
+		r := &running{cancel: cancel, done: make(chan struct{})}
+		taskCtx = context.WithValue(taskCtx, handling{}, r)
+		q.running[id] = r
+
+		q.workers.Add(1)
+		go q.run(taskCtx, *task, handler, r)
+	}
+
+	if changed {
+		if err := q.save(); err != nil {
+			spacetrader.Logger.Printf("queue: could not save: %v", err)
+		}
+	}
+
+	return next
+}
+
+// run calls a task's handler and records how it went
+func (q *Queue) run(ctx context.Context, task Task, handler Handler, r *running) {
+	defer q.workers.Done()
+	defer close(r.done)
+
+	started := q.Now()
+	err := call(ctx, task, handler)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	r.cancel()
+	delete(q.running, task.ID)
+
+	current, ok := q.tasks[task.ID]
+	if !ok || current.State != StateRunning {
+		// Cancelled, Cancel has already said so
+		return
+	}
+
+	now := q.Now()
+	switch {
+	case q.ctx.Err() != nil:
+		// Shutting down, it gets run again next time
+		current.State = StatePending
+	case err == nil:
+		current.State, current.Error = StateDone, ""
+	case errors.As(err, new(permanent)):
+		current.Attempts++
+		current.State, current.Error = StateFailed, err.Error()
+		spacetrader.Logger.Printf("queue: %s %s failed for good: %v", current.Kind, current.Key, err)
+	default:
+		// A task that ran happily for a good while before failing starts its backoff over
+		if now.Sub(started) > q.MaxBackoff {
+			current.Attempts = 0
+		}
+		current.Attempts++
+		current.Error = err.Error()
+
+		if current.MaxAttempts > 0 && current.Attempts >= current.MaxAttempts {
+			current.State = StateFailed
+			spacetrader.Logger.Printf("queue: %s %s failed for good: %v", current.Kind, current.Key, err)
+			break
+		}
+
+		backoff := q.Backoff
+		for i := 1; i < current.Attempts && backoff < q.MaxBackoff; i++ {
+			backoff *= 2
+		}
+		backoff = min(backoff, q.MaxBackoff)
+
+		current.State, current.ScheduledAt = StatePending, now.Add(backoff)
+		spacetrader.Logger.Printf("queue: %s %s failed, retrying in %s: %v", current.Kind, current.Key, backoff, err)
+	}
+	current.Updated = now
+
+	if err := q.save(); err != nil {
+		spacetrader.Logger.Printf("queue: could not save: %v", err)
+	}
+	q.poke()
+}
+
+// call runs the handler, turning a panic into an error so the task can be retried
+func call(ctx context.Context, task Task, handler Handler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			spacetrader.Logger.Printf("queue: %s %s panicked: %v\n%s", task.Kind, task.Key, r, debug.Stack())
+			err = fmt.Errorf("panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, task)
+}
+
+// live is the pending or running task with key, q.mu must be held
+func (q *Queue) live(key string) (*Task, bool) {
+	if key == "" {
+		return nil, false
+	}
+
+	for _, task := range q.tasks {
+		if task.Key == key && !task.State.Finished() {
+			return task, true
+		}
+	}
+
+	return nil, false
+}
+
+// poke gets the dispatcher to look over the queue again, q.mu must be held
+func (q *Queue) poke() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// save writes every task out, q.mu must be held. It goes to a temporary file that's synced to
+// disk before it's renamed over the old save, so a crash or power cut halfway through never
+// leaves a broken or empty save behind.
+func (q *Queue) save() error {
+	tasks := []*Task{}
+	for _, task := range q.tasks {
+		tasks = append(tasks, task)
+	}
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+
+	encoded, err := json.MarshalIndent(tasks, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(q.path), 0o755); err != nil {
+		return err
+	}
+
+	temporary := q.path + ".tmp"
+	file, err := os.OpenFile(temporary, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(encoded); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(temporary, q.path); err != nil {
+		return err
+	}
+
+	// The rename only sticks once the directory has been synced too
+	dir, err := os.Open(filepath.Dir(q.path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	return dir.Sync()
+}
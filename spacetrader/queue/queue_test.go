@@ -0,0 +1,289 @@
+package queue_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"example.com/spacetrader"
+	"example.com/spacetrader/queue"
+)
+
+// clock only moves when it's told to, the queue reads it from its own goroutines
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *clock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// open starts a queue saved to a temporary directory, on a clock that's stopped until the test
+// moves it. Backoffs are a few milliseconds so the dispatcher never sleeps for long.
+func open(t *testing.T, path string) (*queue.Queue, *clock) {
+	t.Helper()
+
+	logger := spacetrader.Logger
+	spacetrader.Logger = log.New(io.Discard, "", 0)
+	t.Cleanup(func() { spacetrader.Logger = logger })
+
+	tasks, err := queue.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &clock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	tasks.Now = c.Now
+	tasks.Backoff = time.Millisecond
+	tasks.MaxBackoff = 3 * time.Millisecond
+	t.Cleanup(tasks.Shutdown)
+
+	return tasks, c
+}
+
+// waitFor polls until the task with key satisfies done, and hands it back
+func waitFor(t *testing.T, tasks *queue.Queue, key string, done func(queue.Task) bool) queue.Task {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		task, ok := tasks.Task(key)
+		if ok && done(task) {
+			return task
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("gave up waiting on %s, it's %+v", key, task)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	tasks, c := open(t, filepath.Join(t.TempDir(), "tasks.json"))
+
+	// Each run takes as long as the test says, then fails
+	runs := make(chan time.Duration)
+	tasks.Handle("flaky", func(ctx context.Context, task queue.Task) error {
+		select {
+		case ran := <-runs:
+			c.Advance(ran)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return errors.New("out of fuel")
+	})
+	tasks.Start()
+
+	task, _ := queue.NewTask("flaky", "flaky:SHIP-1", nil)
+	task.MaxAttempts = 4
+	if _, _, err := tasks.Enqueue(task); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name     string
+		ran      time.Duration
+		attempts int
+		backoff  time.Duration
+		state    queue.State
+	}{
+		{"first failure waits the backoff", 0, 1, time.Millisecond, queue.StatePending},
+		{"second failure doubles it", 0, 2, 2 * time.Millisecond, queue.StatePending},
+		{"third failure is capped", 0, 3, 3 * time.Millisecond, queue.StatePending},
+		{"failing after a long run starts over", 4 * time.Millisecond, 1, time.Millisecond, queue.StatePending},
+		{"and doubles again from there", 0, 2, 2 * time.Millisecond, queue.StatePending},
+		{"up to the cap", 0, 3, 3 * time.Millisecond, queue.StatePending},
+		{"until it runs out of attempts", 0, 4, 0, queue.StateFailed},
+	}
+	for _, step := range steps {
+		runs <- step.ran
+		got := waitFor(t, tasks, "flaky:SHIP-1", func(task queue.Task) bool {
+			return task.State != queue.StateRunning && task.Attempts == step.attempts
+		})
+
+		if got.State != step.state || got.Error != "out of fuel" {
+			t.Fatalf("%s: task is %s with error %q, want %s with out of fuel", step.name, got.State, got.Error, step.state)
+		}
+		if backoff := got.ScheduledAt.Sub(got.Updated); step.state == queue.StatePending && backoff != step.backoff {
+			t.Errorf("%s: retrying in %s, want %s", step.name, backoff, step.backoff)
+		}
+
+		c.Set(got.ScheduledAt)
+	}
+}
+
+func TestEnqueueDedupesByKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	tasks, c := open(t, path)
+
+	// Nobody handles these, so they sit pending until they're cancelled
+	steps := []struct {
+		name   string
+		key    string
+		cancel bool
+		added  bool
+		// kept is how many tasks the queue has with key afterwards
+		kept int
+	}{
+		{"a new key is queued", "mining:SHIP-1", false, true, 1},
+		{"the same key again is turned away", "mining:SHIP-1", false, false, 1},
+		{"a different key is queued", "mining:SHIP-10", false, true, 1},
+		{"no key is always queued", "", false, true, 1},
+		{"no key twice is queued twice", "", false, true, 2},
+		{"a key whose task was cancelled is queued again", "mining:SHIP-1", true, true, 1},
+		{"and turned away once it has been", "mining:SHIP-1", false, false, 1},
+	}
+	for _, step := range steps {
+		if step.cancel {
+			if cancelled, err := tasks.Cancel(context.Background(), step.key); !cancelled || err != nil {
+				t.Fatalf("%s: cancelling got %t, %v", step.name, cancelled, err)
+			}
+			c.Advance(time.Second)
+		}
+
+		task, _ := queue.NewTask("mining", step.key, nil)
+		queued, added, err := tasks.Enqueue(task)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if added != step.added || queued.State != queue.StatePending {
+			t.Errorf("%s: got added %t and a %s task, want added %t and a PENDING task", step.name, added, queued.State, step.added)
+		}
+
+		kept := 0
+		for _, task := range tasks.Tasks() {
+			if task.Key == step.key {
+				kept++
+			}
+		}
+		if kept != step.kept {
+			t.Errorf("%s: queue has %d tasks keyed %q, want %d", step.name, kept, step.key, step.kept)
+		}
+	}
+
+	// Everything was saved as it went
+	reopened, err := queue.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved, want := len(reopened.Tasks()), len(tasks.Tasks()); saved != want {
+		t.Errorf("reopened queue has %d tasks, want %d", saved, want)
+	}
+}
+
+func TestOpenResumesRunningTasks(t *testing.T) {
+	created := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		saved queue.State
+		want  queue.State
+	}{
+		{queue.StateRunning, queue.StatePending},
+		{queue.StatePending, queue.StatePending},
+		{queue.StateDone, queue.StateDone},
+		{queue.StateFailed, queue.StateFailed},
+		{queue.StateCancelled, queue.StateCancelled},
+	}
+
+	saved := []queue.Task{}
+	for i, c := range cases {
+		saved = append(saved, queue.Task{ID: i + 1, Kind: "mining", Key: string(c.saved), State: c.saved, Created: created, Updated: created})
+	}
+	encoded, err := json.Marshal(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	if err := os.WriteFile(path, encoded, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tasks, err := queue.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range cases {
+		if task, _ := tasks.Task(string(c.saved)); task.State != c.want {
+			t.Errorf("task saved %s was opened %s, want %s", c.saved, task.State, c.want)
+		}
+	}
+
+	// New tasks carry on numbering after the saved ones
+	task, _ := queue.NewTask("mining", "", nil)
+	if queued, _, err := tasks.Enqueue(task); err != nil || queued.ID != len(cases)+1 {
+		t.Errorf("new task got ID %d and %v, want ID %d", queued.ID, err, len(cases)+1)
+	}
+}
+
+func TestHandlerCancelsItself(t *testing.T) {
+	tasks, _ := open(t, filepath.Join(t.TempDir(), "tasks.json"))
+
+	cancelled := make(chan error, 1)
+	tasks.Handle("once", func(ctx context.Context, task queue.Task) error {
+		_, err := tasks.Cancel(ctx, task.Key)
+		cancelled <- err
+		return nil
+	})
+	tasks.Start()
+
+	task, _ := queue.NewTask("once", "once:SHIP-1", nil)
+	if _, _, err := tasks.Enqueue(task); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-cancelled:
+		if err != nil {
+			t.Fatalf("Cancel: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler is stuck cancelling its own task")
+	}
+
+	waitFor(t, tasks, "once:SHIP-1", func(task queue.Task) bool { return task.State == queue.StateCancelled })
+}
+
+func TestPermanentFailure(t *testing.T) {
+	tasks, _ := open(t, filepath.Join(t.TempDir(), "tasks.json"))
+
+	tasks.Handle("hopeless", func(ctx context.Context, task queue.Task) error {
+		return queue.Permanent(errors.New("X1-B-A1 is in another system"))
+	})
+	tasks.Start()
+
+	task, _ := queue.NewTask("hopeless", "hopeless:SHIP-1", nil)
+	if _, _, err := tasks.Enqueue(task); err != nil {
+		t.Fatal(err)
+	}
+
+	// No attempts limit, but it isn't tried again
+	got := waitFor(t, tasks, "hopeless:SHIP-1", func(task queue.Task) bool { return task.State.Finished() })
+	if got.State != queue.StateFailed || got.Attempts != 1 || got.Error != "X1-B-A1 is in another system" {
+		t.Errorf("task is %s after %d attempts with error %q, want FAILED after 1 with the handler's error", got.State, got.Attempts, got.Error)
+	}
+}
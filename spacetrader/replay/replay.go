@@ -0,0 +1,245 @@
+// Package replay records real exchanges with the SpaceTraders API into fixture files
+// and plays them back later, so decoding can be checked against real payloads offline.
+//
+// Recording sits in front of the real transport and writes every exchange out as it happens:
+//
+//	recorder := replay.NewRecorder("testdata/ships.json", http.DefaultTransport)
+//	spacetrader.HTTPClient = &http.Client{Transport: recorder}
+//
+// Replaying serves those same responses back, in order, without touching the network:
+//
+//	player, err := replay.NewPlayer("testdata/ships.json")
+//	spacetrader.HTTPClient = &http.Client{Transport: player}
+//
+// Request headers are never written down and the bearer token is scrubbed out of
+// anything that is, so fixtures are safe to commit.
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Scrubbed is what the bearer token gets replaced with in recorded fixtures
+const Scrubbed = "REDACTED"
+
+// Exchange is one request and the response the API gave it
+type Exchange struct {
+	Method      string          `json:"method"`
+	Path        string          `json:"path"`
+	RequestBody json.RawMessage `json:"requestBody,omitempty"`
+	Status      int             `json:"status"`
+	Body        json.RawMessage `json:"body,omitempty"`
+	// Text holds the body instead when the API answered with something that isn't JSON
+	Text string `json:"text,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that passes requests on and writes each exchange to a fixture file
+type Recorder struct {
+	mu        sync.Mutex
+	path      string
+	next      http.RoundTripper
+	exchanges []Exchange
+}
+
+// NewRecorder records into path, overwriting whatever was there, and sends requests on through next
+func NewRecorder(path string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &Recorder{path: path, next: next}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+
+	requestBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	exchange := Exchange{
+		Method: req.Method,
+		Path:   req.URL.RequestURI(),
+		Status: resp.StatusCode,
+	}
+	if len(requestBody) > 0 {
+		exchange.RequestBody = json.RawMessage(scrub(requestBody, token))
+	}
+	if json.Valid(responseBody) {
+		exchange.Body = json.RawMessage(scrub(responseBody, token))
+	} else {
+		exchange.Text = string(scrub(responseBody, token))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.exchanges = append(r.exchanges, exchange)
+
+	// Write as we go so a crash or ctrl-c doesn't lose the recording
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Exchanges hands back everything recorded so far
+func (r *Recorder) Exchanges() []Exchange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Exchange{}, r.exchanges...)
+}
+
+func (r *Recorder) save() error {
+	encoded, err := json.MarshalIndent(r.exchanges, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.path, encoded, 0o644)
+}
+
+// Player is an http.RoundTripper that answers requests out of a fixture file. Each exchange is
+// served once, in the order it was recorded, so the same request can get different answers.
+type Player struct {
+	mu        sync.Mutex
+	exchanges []Exchange
+	served    []bool
+}
+
+// NewPlayer loads a fixture file written by a Recorder
+func NewPlayer(path string) (*Player, error) {
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var exchanges []Exchange
+	if err := json.Unmarshal(encoded, &exchanges); err != nil {
+		return nil, fmt.Errorf("replay: could not read fixture %s: %w", path, err)
+	}
+
+	return &Player{exchanges: exchanges, served: make([]bool, len(exchanges))}, nil
+}
+
+func (p *Player) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, exchange := range p.exchanges {
+		if p.served[i] || exchange.Method != req.Method || exchange.Path != req.URL.RequestURI() {
+			continue
+		}
+
+		// Bodies are compared loosely since the key order is up to whoever encoded them
+		if !sameJSON(exchange.RequestBody, requestBody) {
+			continue
+		}
+
+		p.served[i] = true
+
+		body := []byte(exchange.Body)
+		if exchange.Text != "" {
+			body = []byte(exchange.Text)
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", exchange.Status, http.StatusText(exchange.Status)),
+			StatusCode:    exchange.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"application/json"}},
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("replay: no recorded exchange left for %s %s", req.Method, req.URL.RequestURI())
+}
+
+// Remaining lists the recorded exchanges that haven't been asked for yet
+func (p *Player) Remaining() []Exchange {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	remaining := []Exchange{}
+	for i, exchange := range p.exchanges {
+		if !p.served[i] {
+			remaining = append(remaining, exchange)
+		}
+	}
+
+	return remaining
+}
+
+// readBody drains a body and puts a fresh copy back so it can still be sent or read
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	read, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(read))
+
+	return read, nil
+}
+
+func scrub(body []byte, token string) []byte {
+	if token == "" {
+		return body
+	}
+
+	return bytes.ReplaceAll(body, []byte(token), []byte(Scrubbed))
+}
+
+func sameJSON(recorded []byte, sent []byte) bool {
+	if len(recorded) == 0 || len(sent) == 0 {
+		return len(recorded) == len(sent)
+	}
+
+	var want, got any
+	if json.Unmarshal(recorded, &want) != nil || json.Unmarshal(sent, &got) != nil {
+		return bytes.Equal(recorded, sent)
+	}
+
+	wantEncoded, _ := json.Marshal(want)
+	gotEncoded, _ := json.Marshal(got)
+
+	return bytes.Equal(wantEncoded, gotEncoded)
+}
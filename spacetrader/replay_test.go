@@ -0,0 +1,121 @@
+package spacetrader_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"example.com/spacetrader"
+	"example.com/spacetrader/replay"
+)
+
+// replaying answers every request out of a fixture in testdata, and fails the test if any of it
+// goes unasked for
+func replaying(t *testing.T, fixture string) {
+	t.Helper()
+
+	player, err := replay.NewPlayer("testdata/" + fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, baseURL := spacetrader.HTTPClient, spacetrader.BaseURL
+	spacetrader.HTTPClient = &http.Client{Transport: player}
+	spacetrader.BaseURL = "https://api.spacetraders.io/v2"
+	t.Cleanup(func() {
+		spacetrader.HTTPClient, spacetrader.BaseURL = client, baseURL
+		for _, exchange := range player.Remaining() {
+			t.Errorf("%s %s was never asked for", exchange.Method, exchange.Path)
+		}
+	})
+}
+
+func TestReplayShip(t *testing.T) {
+	replaying(t, "ship.json")
+
+	ship, err := spacetrader.GetShip(replay.Scrubbed, "REDACTED-1")
+	if err != nil {
+		t.Fatalf("GetShip: %v", err)
+	}
+
+	if ship.Nav.Status != spacetrader.ShipNavStatusDocked || ship.Nav.Route.Destination.PosX != -18 || ship.Nav.Route.Destination.PosY != 27 {
+		t.Errorf("ship is %s at %d,%d, want DOCKED at -18,27", ship.Nav.Status, ship.Nav.Route.Destination.PosX, ship.Nav.Route.Destination.PosY)
+	}
+	if ship.Frame.Symbol != spacetrader.TradeFrameFrigate || ship.Engine.Speed != 36 || ship.Fuel.Current != 376 {
+		t.Errorf("got a %s with speed %d and %d fuel, want a FRAME_FRIGATE with speed 36 and 376 fuel", ship.Frame.Symbol, ship.Engine.Speed, ship.Fuel.Current)
+	}
+	if len(ship.Mounts) != 3 || len(ship.Mounts[2].Deposits) != 11 || ship.Mounts[2].Requirements.Crew != 2 {
+		t.Errorf("got mounts %+v, want a surveyor that can spot 11 deposits", ship.Mounts)
+	}
+	if !spacetrader.CanMine(ship) {
+		t.Errorf("ship with a mining laser can't mine")
+	}
+	if len(ship.Cargo.Inventory) != 1 || ship.Cargo.Inventory[0].Symbol != spacetrader.TradeIronOre {
+		t.Errorf("got cargo %+v, want 12 IRON_ORE", ship.Cargo.Inventory)
+	}
+
+	// The API turns the ship away since it's docked
+	_, err = spacetrader.NavigateShip(replay.Scrubbed, "REDACTED-1", "X1-DF55-J62")
+	var refused *spacetrader.APIError
+	if !errors.As(err, &refused) || refused.Code != 4236 || refused.StatusCode != http.StatusBadRequest {
+		t.Errorf("got %v, want a 4236 not in orbit error", err)
+	}
+}
+
+func TestReplayWaypoint(t *testing.T) {
+	replaying(t, "waypoint.json")
+
+	waypoint, err := spacetrader.GetWaypoint(replay.Scrubbed, "X1-DF55", "X1-DF55-A1")
+	if err != nil {
+		t.Fatalf("GetWaypoint: %v", err)
+	}
+	if waypoint.Type != spacetrader.WaypointTypePlanet || waypoint.PosX != -18 || waypoint.PosY != 27 {
+		t.Errorf("got a %s at %d,%d, want a PLANET at -18,27", waypoint.Type, waypoint.PosX, waypoint.PosY)
+	}
+	if !waypoint.HasTrait(spacetrader.WaypointTraitMarketplace) || !waypoint.HasTrait(spacetrader.WaypointTraitShipyard) {
+		t.Errorf("got traits %+v, want a marketplace and a shipyard", waypoint.Traits)
+	}
+
+	gate, err := spacetrader.GetWaypoint(replay.Scrubbed, "X1-DF55", "X1-DF55-I63")
+	if err != nil {
+		t.Fatalf("GetWaypoint: %v", err)
+	}
+	if gate.Type != spacetrader.WaypointTypeJumpGate || !gate.IsUnderConstruction {
+		t.Errorf("got a %s, under construction %t, want a JUMP_GATE still being built", gate.Type, gate.IsUnderConstruction)
+	}
+}
+
+func TestReplaySystem(t *testing.T) {
+	replaying(t, "system.json")
+
+	system, err := spacetrader.GetSystem(replay.Scrubbed, "X1-DF55")
+	if err != nil {
+		t.Fatalf("GetSystem: %v", err)
+	}
+	if system.SectorSymbol != "X1" || system.Type != "RED_STAR" || system.PosX != 4212 || system.PosY != -5567 {
+		t.Errorf("got %s %s at %d,%d, want X1 RED_STAR at 4212,-5567", system.SectorSymbol, system.Type, system.PosX, system.PosY)
+	}
+	if len(system.Waypoints) != 4 || system.Waypoints[3].Type != spacetrader.WaypointTypeJumpGate || system.Waypoints[3].PosX != -306 {
+		t.Errorf("got waypoints %+v, want four ending in the jump gate at -306,211", system.Waypoints)
+	}
+}
+
+func TestReplayContracts(t *testing.T) {
+	replaying(t, "contracts.json")
+
+	contracts, err := spacetrader.GetContracts(replay.Scrubbed)
+	if err != nil {
+		t.Fatalf("GetContracts: %v", err)
+	}
+	if len(contracts) != 1 {
+		t.Fatalf("got %d contracts, want 1", len(contracts))
+	}
+
+	contract := contracts[0]
+	if contract.Identifier != "cltlsr3f60dkls60cvbb3vtz4" || !contract.Accepted || contract.Terms.Payment.OnFulfilled != 12632 {
+		t.Errorf("got %+v, want the accepted iron ore contract paying 12632", contract)
+	}
+	if delivery := contract.Terms.Deliver[0]; delivery.TradeSymbol != spacetrader.TradeIronOre || delivery.UnitsRequired != 53 || delivery.UnitsFulfilled != 12 {
+		t.Errorf("got delivery %+v, want 12 of 53 IRON_ORE", delivery)
+	}
+}
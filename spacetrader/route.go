@@ -0,0 +1,131 @@
+package spacetrader
+
+import (
+	"time"
+)
+
+// Fuel is sold in market units that each fill 100 units of a ship's tank
+const fuelPerMarketUnit = 100
+
+type HopAction string
+
+const (
+	HopNavigate HopAction = "NAVIGATE"
+	HopJump     HopAction = "JUMP"
+	HopWarp     HopAction = "WARP"
+)
+
+// RouteHop is one leg of a planned route. Refuel is how much fuel to buy at From before
+// setting off, and Cost is what that refuel comes to. Jumps don't fly anywhere, they spend
+// antimatter and leave the ship on Cooldown instead.
+type RouteHop struct {
+	// Action is how the hop gets flown, empty means navigating like routes always used to
+	Action     HopAction     `json:"action,omitempty"`
+	From       string        `json:"from"`
+	To         string        `json:"to"`
+	Mode       FlightMode    `json:"mode"`
+	Distance   float64       `json:"distance"`
+	Fuel       int           `json:"fuel"`
+	Duration   time.Duration `json:"duration"`
+	Refuel     int           `json:"refuel"`
+	Cost       int           `json:"cost"`
+	Antimatter int           `json:"antimatter,omitempty"`
+	Cooldown   time.Duration `json:"cooldown,omitempty"`
+}
+
+// Route is an ordered list of hops, along with what the whole trip adds up to
+type Route struct {
+	Hops       []RouteHop    `json:"hops"`
+	Fuel       int           `json:"fuel"`
+	Duration   time.Duration `json:"duration"`
+	Cost       int           `json:"cost"`
+	Antimatter int           `json:"antimatter,omitempty"`
+}
+
+// The flight modes worth planning with, stealth is as thirsty as cruise but slower
+var plannedFlightModes = []FlightMode{FlightModeBurn, FlightModeCruise, FlightModeDrift}
+
+// FindFuelStations looks through a system's marketplaces for the ones selling fuel, keyed by
+// waypoint symbol. The price per market unit is 0 when none of our ships are there to see it.
+func FindFuelStations(api MarketAPI, waypoints []Waypoint) (map[string]int, error) {
+	stations := map[string]int{}
+
+	for _, waypoint := range waypoints {
+		if !waypoint.HasTrait(WaypointTraitMarketplace) {
+			continue
+		}
+
+		market, err := api.GetMarket(SystemSymbol(waypoint.Symbol), waypoint.Symbol)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !market.Sells(TradeFuel) {
+			continue
+		}
+
+		good, _ := market.TradeGood(TradeFuel)
+		stations[waypoint.Symbol] = good.PurchasePrice
+	}
+
+	return stations, nil
+}
+
+// PlanRoute finds the quickest way for ship to get to destination inside its current system.
+// It can stop to fill up at any of fuelStations (waypoint symbol to price per market unit)
+// and picks the flight mode for each hop, falling back to drifting when nothing else will
+// make it. Ties on time go to whichever route spends fewer credits on fuel.
+func PlanRoute(ship Ship, waypoints []Waypoint, fuelStations map[string]int, destination string) (Route, error) {
+	// A single system with no gates is a galaxy where all you can do is navigate
+	galaxy := NewGalaxy(nil)
+	for _, waypoint := range waypoints {
+		galaxy.Waypoints[waypoint.Symbol] = waypoint
+	}
+
+	return galaxy.PlanVoyage(ship, fuelStations, destination)
+}
+
+// routeLabel is one way of getting to a waypoint, linked back to how we got there
+type routeLabel struct {
+	at       string
+	fuel     int
+	duration time.Duration
+	cost     int
+	previous *routeLabel
+	hop      RouteHop
+}
+
+func (l *routeLabel) better(other *routeLabel) bool {
+	if l.duration != other.duration {
+		return l.duration < other.duration
+	}
+
+	return l.cost < other.cost
+}
+
+func (l *routeLabel) route() Route {
+	route := Route{Duration: l.duration, Cost: l.cost}
+	for label := l; label.previous != nil; label = label.previous {
+		route.Hops = append([]RouteHop{label.hop}, route.Hops...)
+		route.Fuel += label.hop.Fuel
+		route.Antimatter += label.hop.Antimatter
+	}
+
+	return route
+}
+
+// routeQueue hands back the quickest label first
+type routeQueue []*routeLabel
+
+func (q routeQueue) Len() int           { return len(q) }
+func (q routeQueue) Less(i, j int) bool { return q[i].better(q[j]) }
+func (q routeQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *routeQueue) Push(x any)        { *q = append(*q, x.(*routeLabel)) }
+func (q *routeQueue) Pop() any {
+	old := *q
+	label := old[len(old)-1]
+	*q = old[:len(old)-1]
+
+	return label
+}
@@ -0,0 +1,108 @@
+package spacetrader_test
+
+import (
+	"testing"
+
+	"example.com/spacetrader"
+)
+
+func TestPlanRoute(t *testing.T) {
+	waypoints := []spacetrader.Waypoint{
+		{Symbol: "X1-S-HOME", PosX: 0, PosY: 0},
+		{Symbol: "X1-S-NEAR", PosX: 10, PosY: 0},
+		{Symbol: "X1-S-FUEL", PosX: 50, PosY: 0},
+		{Symbol: "X1-S-FAR", PosX: 100, PosY: 0},
+	}
+	stations := map[string]int{"X1-S-FUEL": 72}
+
+	type hop struct {
+		to     string
+		mode   spacetrader.FlightMode
+		refuel int
+	}
+
+	tests := []struct {
+		name        string
+		fuel        int
+		capacity    int
+		destination string
+		want        []hop
+		cost        int
+	}{
+		{
+			name: "burns when the tank allows it", fuel: 100, capacity: 100, destination: "X1-S-NEAR",
+			want: []hop{{"X1-S-NEAR", spacetrader.FlightModeBurn, 0}},
+		},
+		{
+			name: "cruises when burning would run dry", fuel: 15, capacity: 100, destination: "X1-S-NEAR",
+			want: []hop{{"X1-S-NEAR", spacetrader.FlightModeCruise, 0}},
+		},
+		{
+			name: "drifts on the last of the fuel", fuel: 5, capacity: 100, destination: "X1-S-NEAR",
+			want: []hop{{"X1-S-NEAR", spacetrader.FlightModeDrift, 0}},
+		},
+		{
+			name: "stops to refuel rather than drifting the whole way", fuel: 60, capacity: 60, destination: "X1-S-FAR",
+			want: []hop{
+				{"X1-S-FUEL", spacetrader.FlightModeCruise, 0},
+				{"X1-S-FAR", spacetrader.FlightModeCruise, 50},
+			},
+			cost: 72,
+		},
+		{
+			name: "probes without a tank go flat out", fuel: 0, capacity: 0, destination: "X1-S-FAR",
+			want: []hop{{"X1-S-FAR", spacetrader.FlightModeBurn, 0}},
+		},
+		{
+			name: "already there", fuel: 0, capacity: 100, destination: "X1-S-HOME",
+			want: []hop{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			route, err := spacetrader.PlanRoute(shipAt("X1-S-HOME", test.fuel, test.capacity), waypoints, stations, test.destination)
+			if err != nil {
+				t.Fatalf("PlanRoute: %v", err)
+			}
+
+			if len(route.Hops) != len(test.want) {
+				t.Fatalf("got %d hops, want %d: %+v", len(route.Hops), len(test.want), route.Hops)
+			}
+			for i, got := range route.Hops {
+				want := test.want[i]
+				if got.To != want.to || got.Mode != want.mode || got.Refuel != want.refuel {
+					t.Errorf("hop %d goes to %s in %s refuelling %d, want %s in %s refuelling %d", i, got.To, got.Mode, got.Refuel, want.to, want.mode, want.refuel)
+				}
+			}
+			if route.Cost != test.cost {
+				t.Errorf("route costs %d, want %d", route.Cost, test.cost)
+			}
+		})
+	}
+}
+
+func TestPlanRouteUnreachable(t *testing.T) {
+	waypoints := []spacetrader.Waypoint{
+		{Symbol: "X1-S-HOME", PosX: 0, PosY: 0},
+		{Symbol: "X1-S-NEAR", PosX: 10, PosY: 0},
+	}
+
+	tests := []struct {
+		name        string
+		fuel        int
+		destination string
+	}{
+		{name: "not even enough to drift", fuel: 0, destination: "X1-S-NEAR"},
+		{name: "off the map", fuel: 100, destination: "X1-S-NOWHERE"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			route, err := spacetrader.PlanRoute(shipAt("X1-S-HOME", test.fuel, 100), waypoints, map[string]int{}, test.destination)
+			if err == nil {
+				t.Errorf("got %+v, want no route", route.Hops)
+			}
+		})
+	}
+}
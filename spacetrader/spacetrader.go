@@ -1,362 +1,211 @@
 package spacetrader
 
 import (
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-)
+	"net/url"
+	"strconv"
+	"strings"
 
-type AgentWrap struct {
-	Data Agent `json:"data"`
-}
+	"example.com/spacetrader/model"
+)
 
-type Agent struct {
-	AccountId       string `json:"accountId"`
-	Symbol          string `json:"symbol"`
-	Headquarters    string `json:"headquarters"`
-	Credits         int64  `json:"credits"`
-	StartingFaction string `json:"startingFaction"`
-	ShipCount       int    `json:"shipCount"`
-}
+// Agent comes straight from the generated models, the spec and the API agree on it
+type Agent = model.Agent
 
 func ShowAgent(token string) (Agent, error) {
-	url := "https://api.spacetraders.io/v2/my/agent"
-
-	client := &http.Client{
-		CheckRedirect: nil,
-	}
+	agent, err := model.GetMyAgent(NewClient(token))
 
-	req, err := http.NewRequest("GET", url, nil)
-	req.Header.Add("Authorization", fmt.Sprint("Bearer ", token))
-
-	if err != nil {
-		return Agent{}, err
-	}
-
-	resp, err := client.Do(req)
 	if err != nil {
 		return Agent{}, err
 	}
 
-	// Closing the connection seems important and stuff
-	defer resp.Body.Close()
-	// Grab the deets
-	body, err := io.ReadAll(resp.Body)
-
-	if err != nil {
-		return Agent{}, err
-	}
-
-	var agent AgentWrap
-	openErr := json.Unmarshal([]byte(body), &agent)
-
-	if openErr != nil {
-		return Agent{}, openErr
-	}
-
-	fmt.Printf("AccountId: %s\nSymbol: %s\nCredits: %d\nShip Count: %d\n", agent.Data.AccountId, agent.Data.Symbol, agent.Data.Credits, agent.Data.ShipCount)
-
-	return agent.Data, nil
+	return agent, nil
 }
 
-type WaypointsWrap struct {
-	Data []Waypoint `json:"data"`
-}
-
-type Waypoint struct {
-	Symbol string  `json:"symbol"`
-	Type   string  `json:"type"`
-	PosX   int     `json:"x"`
-	PosY   int     `json:"y"`
-	Traits []Trait `json:"traits"`
-}
+// Waypoints and their traits come straight from the generated models too
+type (
+	Waypoint = model.Waypoint
+	Trait    = model.WaypointTrait
+)
 
-type Trait struct {
-	Symbol      string `json:"symbol"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+// WaypointQuery narrows down which waypoints GetWaypoints hands back. Anything left empty isn't filtered on.
+type WaypointQuery struct {
+	Type   WaypointType
+	Traits []WaypointTraitSymbol
+	Page   int
+	Limit  int
 }
 
-func GetWaypoints(token string, system string, kind string) (string, error) {
-	url := fmt.Sprint("https://api.spacetraders.io/v2/systems/", system, "/waypoints?traits=", kind)
+// Values checks the query and turns it into URL parameters
+func (q WaypointQuery) Values() (url.Values, error) {
+	values := url.Values{}
 
-	client := &http.Client{
-		CheckRedirect: nil,
+	if q.Type != "" {
+		if !q.Type.Valid() {
+			return nil, fmt.Errorf("unknown waypoint type %q", q.Type)
+		}
+		values.Set("type", string(q.Type))
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
-	req.Header.Add("Authorization", fmt.Sprint("Bearer ", token))
-
-	if err != nil {
-		return "Failed to create request client", err
+	for _, trait := range q.Traits {
+		if !trait.Valid() {
+			return nil, fmt.Errorf("unknown waypoint trait %q", trait)
+		}
+		values.Add("traits", string(trait))
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return "Failed to retrieve agent", err
+	// The API caps pages at 20 waypoints
+	if q.Limit < 0 || q.Limit > 20 {
+		return nil, fmt.Errorf("limit must be between 1 and 20, got %d", q.Limit)
 	}
-
-	// Closing the connection seems important and stuff
-	defer resp.Body.Close()
-	// Grab the deets
-	body, err := io.ReadAll(resp.Body)
-
-	if err != nil {
-		return "Failed to read response from the server", err
+	if q.Limit > 0 {
+		values.Set("limit", strconv.Itoa(q.Limit))
 	}
 
-	var waypoints WaypointsWrap
-	openErr := json.Unmarshal([]byte(body), &waypoints)
-
-	for _, waypoint := range waypoints.Data {
-		fmt.Println("=======================================")
-		fmt.Printf("Symbol: %s\nType: %s\nX: %d\nY: %d\nTraits: ", waypoint.Symbol, waypoint.Type, waypoint.PosX, waypoint.PosY)
-		for _, wTrait := range waypoint.Traits {
-			fmt.Printf("%s, ", wTrait.Name)
-		}
-		fmt.Println("\n=======================================")
+	if q.Page < 0 {
+		return nil, fmt.Errorf("page must be positive, got %d", q.Page)
 	}
-
-	if openErr != nil {
-		return "Failed to unmarshall JSON from server", openErr
+	if q.Page > 0 {
+		values.Set("page", strconv.Itoa(q.Page))
 	}
 
-	// Loop through each Waypoint and create a block with =========== around it to show what each waypoint looks like
-	// Maybe a follow-up task could be to modify it to use the ships current position to calculate the distance
-	//fmt.Printf("AccountId: %s\nSymbol: %s\nCredits: %d\nShip Count: %d\n", agent.Data.AccountId, agent.Data.Symbol, agent.Data.Credits, agent.Data.ShipCount)
-
-	return string(body), nil
-}
-
-type WaypointWrap struct {
-	Data Waypoint `json:"data"`
+	return values, nil
 }
 
-func GetWaypoint(token string, systemSymbol string, waypointSymbol string) (Waypoint, error) {
-	url := fmt.Sprint("https://api.spacetraders.io/v2/systems/", systemSymbol, "/waypoints/", waypointSymbol)
-
-	client := &http.Client{
-		CheckRedirect: nil,
-	}
-
-	req, err := http.NewRequest("GET", url, nil)
-	req.Header.Add("Authorization", fmt.Sprint("Bearer ", token))
-
-	if err != nil {
-		return Waypoint{}, err
-	}
+func GetWaypoints(token string, system string, query WaypointQuery) ([]Waypoint, Meta, error) {
+	values, err := query.Values()
 
-	resp, err := client.Do(req)
 	if err != nil {
-		return Waypoint{}, err
+		return []Waypoint{}, Meta{}, err
 	}
 
-	// Closing the connection seems important and stuff
-	defer resp.Body.Close()
-	// Grab the deets
-	body, err := io.ReadAll(resp.Body)
+	waypoints, meta, err := model.GetSystemWaypoints(NewClient(token), system, values)
 
 	if err != nil {
-		return Waypoint{}, err
-	}
-
-	var waypoint WaypointWrap
-	openErr := json.Unmarshal([]byte(body), &waypoint)
-
-	if openErr != nil {
-		return Waypoint{}, openErr
+		return []Waypoint{}, Meta{}, err
 	}
 
-	return waypoint.Data, nil
+	return waypoints, meta, nil
 }
 
-type SystemWrap struct {
-	Data System `json:"data"`
-}
+// AllWaypoints keeps paging through GetWaypoints until it has every waypoint matching query
+func AllWaypoints(api SystemsAPI, system string, query WaypointQuery) ([]Waypoint, error) {
+	query.Limit = 20
+	all := []Waypoint{}
 
-type System struct {
-	Symbol       string     `json:"symbol"`
-	SectorSymbol string     `json:"sectorSymbol"`
-	Type         string     `json:"type"`
-	PosX         int        `json:"x"`
-	PosY         int        `json:"y"`
-	Waypoints    []Waypoint `json:"waypoints"`
-}
+	for query.Page = 1; ; query.Page++ {
+		waypoints, meta, err := api.GetWaypoints(system, query)
 
-func GetSystem(token string, systemSymbol string) (System, error) {
-	url := fmt.Sprint("https://api.spacetraders.io/v2/systems/", systemSymbol)
+		if err != nil {
+			return []Waypoint{}, err
+		}
 
-	client := &http.Client{
-		CheckRedirect: nil,
+		all = append(all, waypoints...)
+		if len(waypoints) == 0 || len(all) >= meta.Total {
+			return all, nil
+		}
 	}
+}
 
-	req, err := http.NewRequest("GET", url, nil)
-	req.Header.Add("Authorization", fmt.Sprint("Bearer ", token))
+func GetWaypoint(token string, systemSymbol string, waypointSymbol string) (Waypoint, error) {
+	waypoint, err := model.GetWaypoint(NewClient(token), systemSymbol, waypointSymbol)
 
 	if err != nil {
-		return System{}, err
+		return Waypoint{}, err
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return System{}, err
-	}
+	return waypoint, nil
+}
+
+// System is the generated model too. The API lists a system's waypoints in a cut down form, but
+// everything here treats them as Waypoints, which decode from it just fine, so the spec types
+// them that way.
+type System = model.System
 
-	// Closing the connection seems important and stuff
-	defer resp.Body.Close()
-	// Grab the deets
-	body, err := io.ReadAll(resp.Body)
+func GetSystem(token string, systemSymbol string) (System, error) {
+	system, err := model.GetSystem(NewClient(token), systemSymbol)
 
 	if err != nil {
 		return System{}, err
 	}
 
-	var system SystemWrap
-	openErr := json.Unmarshal([]byte(body), &system)
-
-	if openErr != nil {
-		return System{}, openErr
-	}
-
-	return system.Data, nil
-}
-
-type ShipsWrap struct {
-	Data []Ship `json:"data"`
-}
-
-type Ship struct {
-	Symbol       string      `json:"symbol"`
-	SystemSymbol string      `json:"systemSymbol"`
-	Nav          ShipNav     `json:"nav"`
-	Fuel         ShipFuel    `json:"fuel"`
-	Mounts       []ShipMount `json:"mounts"`
-	Cargo        ShipCargo   `json:"cargo"`
-}
-
-type ShipNav struct {
-	Status         string    `json:"status"`
-	Route          ShipRoute `json:"route"`
-	SystemSymbol   string    `json:"systemSymbol"`
-	WaypointSymbol string    `json:"waypointSymbol"`
-}
-
-type ShipRoute struct {
-	Arrival     string          `json:"arrival"`
-	Destination ShipDestination `json:"destination"`
-}
-
-type ShipDestination struct {
-	PosX int `json:"x"`
-	PosY int `json:"y"`
+	return system, nil
 }
 
-type ShipFuel struct {
-	Current  int `json:"current"`
-	Capacity int `json:"capacity"`
-}
-
-type ShipMount struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-}
-
-type ShipCargo struct {
-	Capacity  int     `json:"capacity"`
-	Units     int     `json:"units"`
-	Inventory []Cargo `json:"inventory"`
-}
-
-type Cargo struct {
-	Symbol      string `json:"symbol"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Units       int    `json:"units"`
-}
-
-func GetShips(token string) ([]Ship, error) {
-	url := "https://api.spacetraders.io/v2/my/ships"
-
-	client := &http.Client{
-		CheckRedirect: nil,
-	}
-
-	req, err := http.NewRequest("GET", url, nil)
-	req.Header.Add("Authorization", fmt.Sprint("Bearer ", token))
-
-	if err != nil {
-		return []Ship{}, err
+// ListSystems pages through every system in the universe, the API caps limit at 20
+func ListSystems(token string, page int, limit int) ([]System, Meta, error) {
+	if page < 1 {
+		return []System{}, Meta{}, fmt.Errorf("page must be positive, got %d", page)
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return []Ship{}, err
+	if limit < 1 || limit > 20 {
+		return []System{}, Meta{}, fmt.Errorf("limit must be between 1 and 20, got %d", limit)
 	}
 
-	// Closing the connection seems important and stuff
-	defer resp.Body.Close()
-	// Grab the deets
-	body, err := io.ReadAll(resp.Body)
+	query := url.Values{"page": {strconv.Itoa(page)}, "limit": {strconv.Itoa(limit)}}
+	systems, meta, err := model.GetSystems(NewClient(token), query)
 
 	if err != nil {
-		return []Ship{}, err
+		return []System{}, Meta{}, err
 	}
 
-	var ships ShipsWrap
-	openErr := json.Unmarshal([]byte(body), &ships)
+	return systems, meta, nil
+}
 
-	if openErr != nil {
-		return []Ship{}, openErr
+// SystemSymbol works out which system a waypoint is in, "X1-DF55-20250Z" lives in "X1-DF55"
+func SystemSymbol(waypointSymbol string) string {
+	parts := strings.Split(waypointSymbol, "-")
+	if len(parts) < 3 {
+		return waypointSymbol
 	}
 
-	return ships.Data, nil
-}
-
-type ShipWrap struct {
-	Data Ship `json:"data"`
+	return strings.Join(parts[:2], "-")
 }
 
-func GetShip(token string, shipSymbol string) (Ship, error) {
-	url := fmt.Sprint("https://api.spacetraders.io/v2/my/ships/", shipSymbol)
+// A ship and everything bolted to it are the generated models, under the names the client
+// has always used for them
+type (
+	Ship             = model.Ship
+	ShipRegistration = model.ShipRegistration
+	ShipNav          = model.ShipNav
+	ShipRoute        = model.ShipNavRoute
+	ShipDestination  = model.ShipNavRouteWaypoint
+	ShipFuel         = model.ShipFuel
+	ShipCrew         = model.ShipCrew
+	ShipFrame        = model.ShipFrame
+	ShipReactor      = model.ShipReactor
+	ShipEngine       = model.ShipEngine
+	ShipModule       = model.ShipModule
+	ShipMount        = model.ShipMount
+	ShipCargo        = model.ShipCargo
+	Cargo            = model.ShipCargoItem
 
-	client := &http.Client{
-		CheckRedirect: nil,
-	}
+	// ShipRequirements is what a component needs from the rest of the ship to run
+	ShipRequirements = model.ShipRequirements
+)
 
-	req, err := http.NewRequest("GET", url, nil)
-	req.Header.Add("Authorization", fmt.Sprint("Bearer ", token))
+func GetShips(token string) ([]Ship, error) {
+	ships, _, err := model.GetMyShips(NewClient(token), nil)
 
 	if err != nil {
-		return Ship{}, err
+		return []Ship{}, err
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return Ship{}, err
-	}
+	return ships, nil
+}
 
-	// Closing the connection seems important and stuff
-	defer resp.Body.Close()
-	// Grab the deets
-	body, err := io.ReadAll(resp.Body)
+func GetShip(token string, shipSymbol string) (Ship, error) {
+	ship, err := model.GetMyShip(NewClient(token), shipSymbol)
 
 	if err != nil {
 		return Ship{}, err
 	}
 
-	var ship ShipWrap
-	openErr := json.Unmarshal([]byte(body), &ship)
-
-	if openErr != nil {
-		return Ship{}, openErr
-	}
-
-	return ship.Data, nil
+	return ship, nil
 }
 
-func DisplayShipNav(token string, shipSymbol string) (string, error) {
-	ship, err := GetShip(token, shipSymbol)
+func DisplayShipNav(fleet FleetAPI, shipSymbol string) (string, error) {
+	ship, err := fleet.GetShip(shipSymbol)
 
 	if err != nil {
 		return "", err
@@ -364,11 +213,12 @@ func DisplayShipNav(token string, shipSymbol string) (string, error) {
 
 	flightWidget := ""
 
-	if ship.Nav.Status == "DOCKED" {
+	switch ship.Nav.Status {
+	case ShipNavStatusDocked:
 		flightWidget = fmt.Sprintf(`%s (<a class="hover:underline cursor-pointer" hx-post="/ships/%s:launch">Go to orbit</a>)`, ship.Nav.Status, ship.Symbol)
-	} else if ship.Nav.Status == "IN_ORBIT" {
+	case ShipNavStatusInOrbit:
 		flightWidget = fmt.Sprintf(`%s (<a class="hover:underline cursor-pointer" hx-post="/ships/%s:dock">Dock this ship</a>)`, ship.Nav.Status, ship.Symbol)
-	} else {
+	default:
 		flightWidget = fmt.Sprintf(`%s`, ship.Nav.Status)
 	}
 
@@ -377,197 +227,141 @@ func DisplayShipNav(token string, shipSymbol string) (string, error) {
 	return navDisplay, nil
 }
 
-type TransitNavWrap struct {
-	Data ShipNav `json:"data"`
-}
+// ShipTransit is what comes back from orbit, dock and navigate. Dock and orbit only fill in the Nav.
+type ShipTransit = model.ShipTransit
 
 func LaunchToOrbit(token string, shipSymbol string) (bool, error) {
-	url := fmt.Sprintf("https://api.spacetraders.io/v2/my/ships/%s/orbit", shipSymbol)
-
-	client := &http.Client{
-		CheckRedirect: nil,
-	}
-
-	req, err := http.NewRequest("POST", url, nil)
-	req.Header.Add("Authorization", fmt.Sprint("Bearer ", token))
-
-	if err != nil {
-		return false, err
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return false, err
-	}
-
-	// Closing the connection seems important and stuff
-	defer resp.Body.Close()
-	// Grab the deets
-	body, err := io.ReadAll(resp.Body)
+	// Should I do something with this Nav item? Maybe pass back the time?
+	_, err := model.OrbitShip(NewClient(token), shipSymbol)
 
 	if err != nil {
 		return false, err
 	}
 
-	// While this is SORT OF true, this will only actually return a nav object
-	var transit TransitNavWrap
-	openErr := json.Unmarshal([]byte(body), &transit)
-
-	if openErr != nil {
-		return false, openErr
-	}
-
-	// Should I do something with this Nav item? Maybe pass back the time?
 	return true, nil
 }
 
 func DockShip(token string, shipSymbol string) (bool, error) {
-	url := fmt.Sprintf("https://api.spacetraders.io/v2/my/ships/%s/dock", shipSymbol)
-
-	client := &http.Client{
-		CheckRedirect: nil,
-	}
-
-	req, err := http.NewRequest("POST", url, nil)
-	req.Header.Add("Authorization", fmt.Sprint("Bearer ", token))
+	// Should I do something with this Nav item? Maybe pass back the time?
+	_, err := model.DockShip(NewClient(token), shipSymbol)
 
 	if err != nil {
 		return false, err
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return false, err
-	}
+	return true, nil
+}
 
-	// Closing the connection seems important and stuff
-	defer resp.Body.Close()
-	// Grab the deets
-	body, err := io.ReadAll(resp.Body)
+func NavigateShip(token string, shipSymbol string, waypointSymbol string) (bool, error) {
+	destination := model.NavigateShipRequest{WaypointSymbol: waypointSymbol}
+	_, err := model.NavigateShip(NewClient(token), shipSymbol, destination)
 
 	if err != nil {
 		return false, err
 	}
 
-	// While this is SORT OF true, this will only actually return a nav object
-	var transit TransitNavWrap
-	openErr := json.Unmarshal([]byte(body), &transit)
-
-	if openErr != nil {
-		return false, openErr
-	}
-
-	// Should I do something with this Nav item? Maybe pass back the time?
 	return true, nil
 }
 
-func NavigateShip(token string, shipSymbol string, waypointSymbol string) (bool, error) {
-	url := fmt.Sprintf("https://api.spacetraders.io/v2/my/ships/%s/navigate", shipSymbol)
-
-	client := &http.Client{
-		CheckRedirect: nil,
+// SetFlightMode changes how the ship flies from here on, it can be changed mid-flight
+func SetFlightMode(token string, shipSymbol string, mode FlightMode) (ShipNav, error) {
+	if !mode.Valid() {
+		return ShipNav{}, fmt.Errorf("unknown flight mode %q", mode)
 	}
 
-	req, err := http.NewRequest("POST", url, nil)
-	req.Header.Add("Authorization", fmt.Sprint("Bearer ", token))
+	nav, err := model.PatchShipNav(NewClient(token), shipSymbol, model.PatchShipNavRequest{FlightMode: mode})
 
 	if err != nil {
-		return false, err
+		return ShipNav{}, err
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return false, err
-	}
+	return nav, nil
+}
 
-	// Closing the connection seems important and stuff
-	defer resp.Body.Close()
-	// Grab the deets
-	body, err := io.ReadAll(resp.Body)
+// ShipRefuel is what comes back after buying fuel
+type ShipRefuel = model.ShipRefuel
 
-	if err != nil {
-		return false, err
+// RefuelShip buys fuel at the market the ship is docked at. Leave units at 0 to fill the tank.
+func RefuelShip(token string, shipSymbol string, units int) (ShipRefuel, error) {
+	if units < 0 {
+		return ShipRefuel{}, fmt.Errorf("can't buy %d units of fuel", units)
 	}
 
-	// While this is SORT OF true, this will only actually return a nav object
-	var transit TransitNavWrap
-	openErr := json.Unmarshal([]byte(body), &transit)
+	// Units is left off the request when it's 0, which the API takes as filling up
+	refuel, err := model.RefuelShip(NewClient(token), shipSymbol, model.RefuelShipRequest{Units: units})
 
-	if openErr != nil {
-		return false, openErr
+	if err != nil {
+		return ShipRefuel{}, err
 	}
 
-	// Should I do something with this Nav item? Maybe pass back the time?
-	return true, nil
+	return refuel, nil
 }
 
-type Contract struct {
-	Identifier       string        `json:"id"`
-	FactionSymbol    string        `json:"factionSymbol"`
-	Type             string        `json:"type"`
-	Terms            ContractTerms `json:"terms"`
-	Accepted         bool          `json:"accepted"`
-	Fulfilled        bool          `json:"fulfilled"`
-	Expiration       string        `json:"expiration"`       // This should be a date
-	DeadlineToAccept string        `json:"deadlineToAccept"` // This should be a date
-}
+// Contracts are the generated models as well
+type (
+	Contract         = model.Contract
+	ContractTerms    = model.ContractTerms
+	ContractPayment  = model.ContractPayment
+	ContractDelivery = model.ContractDeliverGood
+)
 
-type ContractTerms struct {
-	Deadline string             `json:"deadline"` // This should be a date
-	Payment  ContractPayment    `json:"payment"`
-	Deliver  []ContractDelivery `json:"deliver"`
-}
+func GetContracts(token string) ([]Contract, error) {
+	contracts, _, err := model.GetContracts(NewClient(token), nil)
 
-type ContractPayment struct {
-	OnAccepted  int `json:"onAccepted"`
-	OnFulfilled int `json:"onFulfilled"`
-}
+	if err != nil {
+		return []Contract{}, err
+	}
 
-type ContractDelivery struct {
-	TradeSymbol       string `json:"tradeSymbol"`
-	DestinationSymbol string `json:"destinationSymbol"`
-	UnitsRequired     int    `json:"unitsRequired"`
-	UnitsFulfilled    int    `json:"unitsFulfilled"`
+	return contracts, nil
 }
 
-type ContractsWrap struct {
-	Data []Contract `json:"data"`
-}
+// ContractUpdate is what comes back after accepting or fulfilling a contract
+type ContractUpdate = model.ContractUpdate
 
-func GetContracts(token string) ([]Contract, error) {
-	url := fmt.Sprint("https://api.spacetraders.io/v2/my/contracts")
+// ContractDeliveryUpdate is what comes back after dropping goods off for a contract
+type ContractDeliveryUpdate = model.ContractDeliveryUpdate
+
+func AcceptContract(token string, contractId string) (ContractUpdate, error) {
+	update, err := model.AcceptContract(NewClient(token), contractId)
 
-	client := &http.Client{
-		CheckRedirect: nil,
+	if err != nil {
+		return ContractUpdate{}, err
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
-	req.Header.Add("Authorization", fmt.Sprint("Bearer ", token))
+	return update, nil
+}
 
-	if err != nil {
-		return []Contract{}, err
+// DeliverContract hands goods over from a ship docked at the delivery destination
+func DeliverContract(token string, contractId string, shipSymbol string, tradeSymbol TradeSymbol, units int) (ContractDeliveryUpdate, error) {
+	if !tradeSymbol.Valid() {
+		return ContractDeliveryUpdate{}, fmt.Errorf("unknown trade symbol %q", tradeSymbol)
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return []Contract{}, err
+	if units < 1 {
+		return ContractDeliveryUpdate{}, fmt.Errorf("must deliver at least 1 unit, got %d", units)
 	}
 
-	// Closing the connection seems important and stuff
-	defer resp.Body.Close()
-	// Grab the deets
-	body, err := io.ReadAll(resp.Body)
+	delivery := model.DeliverContractRequest{
+		ShipSymbol:  shipSymbol,
+		TradeSymbol: tradeSymbol,
+		Units:       units,
+	}
+	update, err := model.DeliverContract(NewClient(token), contractId, delivery)
 
 	if err != nil {
-		return []Contract{}, err
+		return ContractDeliveryUpdate{}, err
 	}
 
-	var contracts ContractsWrap
-	openErr := json.Unmarshal([]byte(body), &contracts)
+	return update, nil
+}
 
-	if openErr != nil {
-		return []Contract{}, openErr
+// FulfillContract collects payment once every delivery has been made
+func FulfillContract(token string, contractId string) (ContractUpdate, error) {
+	update, err := model.FulfillContract(NewClient(token), contractId)
+
+	if err != nil {
+		return ContractUpdate{}, err
 	}
 
-	return contracts.Data, nil
+	return update, nil
 }
@@ -0,0 +1,49 @@
+package spacetrader_test
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"example.com/spacetrader"
+)
+
+// A symbol is always one path segment, whatever is in it
+func TestSymbolsArePathEscaped(t *testing.T) {
+	asked := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		asked = append(asked, r.URL.EscapedPath())
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	baseURL, logger := spacetrader.BaseURL, spacetrader.Logger
+	spacetrader.BaseURL = server.URL
+	spacetrader.Logger = log.New(io.Discard, "", 0)
+	t.Cleanup(func() {
+		server.Close()
+		spacetrader.BaseURL, spacetrader.Logger = baseURL, logger
+	})
+
+	lookups := []struct {
+		name string
+		call func() error
+		want string
+	}{
+		{"system", func() error { _, err := spacetrader.GetSystem("token", "X1/../my"); return err }, "/systems/X1%2F..%2Fmy"},
+		{"waypoint", func() error { _, err := spacetrader.GetWaypoint("token", "X1", "A1?x=1"); return err }, "/systems/X1/waypoints/A1%3Fx=1"},
+		{"jump gate", func() error { _, err := spacetrader.GetJumpGate("token", "X1", "I9/market"); return err }, "/systems/X1/waypoints/I9%2Fmarket/jump-gate"},
+		{"market", func() error { _, err := spacetrader.GetMarket("token", "X1 2", "A1"); return err }, "/systems/X1%202/waypoints/A1/market"},
+	}
+	for _, lookup := range lookups {
+		t.Run(lookup.name, func(t *testing.T) {
+			asked = asked[:0]
+			if err := lookup.call(); err != nil {
+				t.Fatal(err)
+			}
+			if len(asked) != 1 || asked[0] != lookup.want {
+				t.Errorf("asked for %v, want %s", asked, lookup.want)
+			}
+		})
+	}
+}
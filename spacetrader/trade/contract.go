@@ -0,0 +1,153 @@
+package trade
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"example.com/spacetrader"
+	"example.com/spacetrader/history"
+)
+
+// DeliveryEstimate is what one of a contract's deliveries should take: buying what's still owed
+// at the cheapest market on record and hauling it to the destination a hold at a time
+type DeliveryEstimate struct {
+	spacetrader.ContractDelivery
+	// Source is where the goods are cheapest, Found is false when no market on record sells them
+	Source   history.Observation
+	Found    bool
+	Cost     int
+	FuelCost int
+	Trips    int
+	Duration time.Duration
+}
+
+// ContractEstimate sums a contract up in credits and time, for working out if it's worth taking
+type ContractEstimate struct {
+	Deliveries []DeliveryEstimate
+	// Payout is what's still to be paid, the advance drops out once the contract's been accepted
+	Payout      int
+	Procurement int
+	FuelCost    int
+	Profit      int
+	Duration    time.Duration
+	// TimeLeft is until the deadline, AcceptBy is how long there is to take the contract on
+	TimeLeft time.Duration
+	AcceptBy time.Duration
+	// Problems are the reasons the contract can't be done as things stand
+	Problems []string
+}
+
+// Feasible is true when nothing stands in the way of finishing the contract in time
+func (e ContractEstimate) Feasible() bool {
+	return len(e.Problems) == 0
+}
+
+// EvaluateContract estimates what a contract would cost ship to finish and what it would earn.
+// Goods come from the cheapest market on record in the destination's system, waypoints needs
+// to cover that system. The ship is assumed to already be in the system, getting it there isn't counted.
+func EvaluateContract(contract spacetrader.Contract, ship spacetrader.Ship, waypoints []spacetrader.Waypoint, prices *history.Store, now time.Time) ContractEstimate {
+	estimate := ContractEstimate{Payout: contract.Terms.Payment.OnFulfilled}
+	if !contract.Accepted {
+		estimate.Payout += contract.Terms.Payment.OnAccepted
+	}
+
+	if due, err := time.Parse(time.RFC3339Nano, contract.Terms.Deadline); err == nil {
+		estimate.TimeLeft = due.Sub(now)
+	}
+	if due, err := time.Parse(time.RFC3339Nano, contract.DeadlineToAccept); err == nil {
+		estimate.AcceptBy = due.Sub(now)
+	}
+
+	switch {
+	case contract.Fulfilled:
+		estimate.Problems = append(estimate.Problems, "already fulfilled")
+		return estimate
+	case estimate.TimeLeft <= 0:
+		estimate.Problems = append(estimate.Problems, "past its deadline")
+		return estimate
+	case !contract.Accepted && estimate.AcceptBy <= 0:
+		estimate.Problems = append(estimate.Problems, "too late to accept")
+		return estimate
+	case ship.Cargo.Capacity == 0:
+		estimate.Problems = append(estimate.Problems, fmt.Sprintf("%s has no cargo hold", ship.Symbol))
+		return estimate
+	}
+
+	byName := map[string]spacetrader.Waypoint{}
+	for _, waypoint := range waypoints {
+		byName[waypoint.Symbol] = waypoint
+	}
+
+	// Everything the system trades, by good
+	selling := map[spacetrader.TradeSymbol][]history.Observation{}
+	for _, waypointSymbol := range prices.Waypoints() {
+		if _, ok := byName[waypointSymbol]; !ok {
+			continue
+		}
+		for _, observation := range prices.LatestMarket(waypointSymbol) {
+			selling[observation.Symbol] = append(selling[observation.Symbol], observation)
+		}
+	}
+	fuelPrice := cheapestFuel(selling[spacetrader.TradeFuel])
+
+	// The ship does the deliveries one after the other, starting from wherever it is now
+	at := ship.Nav.WaypointSymbol
+	for _, delivery := range contract.Terms.Deliver {
+		owed := delivery.UnitsRequired - delivery.UnitsFulfilled
+		estimated := DeliveryEstimate{ContractDelivery: delivery}
+		if owed <= 0 {
+			estimate.Deliveries = append(estimate.Deliveries, estimated)
+			continue
+		}
+
+		if _, ok := byName[delivery.DestinationSymbol]; !ok {
+			estimate.Problems = append(estimate.Problems, fmt.Sprintf("%s isn't on the map", delivery.DestinationSymbol))
+			estimate.Deliveries = append(estimate.Deliveries, estimated)
+			continue
+		}
+
+		estimated.Trips = (owed + ship.Cargo.Capacity - 1) / ship.Cargo.Capacity
+
+		// Cheapest all in, a market next door can beat a cheaper one across the system
+		best := math.MaxInt
+		for _, source := range selling[delivery.TradeSymbol] {
+			if source.PurchasePrice == 0 {
+				continue
+			}
+
+			stops := []string{at}
+			for range estimated.Trips {
+				stops = append(stops, source.Waypoint, delivery.DestinationSymbol)
+			}
+			fuel, duration := travel(ship, byName, stops...)
+			fuelCost := int(math.Ceil(float64(fuel) * fuelPrice))
+			cost := purchaseCost(source.MarketTradeGood, owed)
+
+			if cost+fuelCost < best {
+				best = cost + fuelCost
+				estimated.Source, estimated.Found = source, true
+				estimated.Cost, estimated.FuelCost, estimated.Duration = cost, fuelCost, duration
+			}
+		}
+
+		if !estimated.Found {
+			estimate.Problems = append(estimate.Problems, fmt.Sprintf("no market on record sells %s", delivery.TradeSymbol))
+		} else {
+			at = delivery.DestinationSymbol
+		}
+
+		estimate.Procurement += estimated.Cost
+		estimate.FuelCost += estimated.FuelCost
+		estimate.Duration += estimated.Duration
+		estimate.Deliveries = append(estimate.Deliveries, estimated)
+	}
+
+	estimate.Profit = estimate.Payout - estimate.Procurement - estimate.FuelCost
+
+	if estimate.Duration > estimate.TimeLeft {
+		estimate.Problems = append(estimate.Problems, fmt.Sprintf("needs %s but only %s is left", estimate.Duration.Round(time.Minute), estimate.TimeLeft.Round(time.Minute)))
+	}
+
+	return estimate
+}
@@ -0,0 +1,91 @@
+package trade
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"example.com/spacetrader"
+)
+
+// ironContract wants 30 iron ore at X1-T-B, paying 1000 up front and 5000 on delivery, and has
+// an hour to run with half an hour left to accept it
+func ironContract(change func(*spacetrader.Contract)) spacetrader.Contract {
+	contract := spacetrader.Contract{
+		Identifier:       "contract-1",
+		DeadlineToAccept: now.Add(30 * time.Minute).Format(time.RFC3339Nano),
+		Terms: spacetrader.ContractTerms{
+			Deadline: now.Add(time.Hour).Format(time.RFC3339Nano),
+			Payment:  spacetrader.ContractPayment{OnAccepted: 1000, OnFulfilled: 5000},
+			Deliver: []spacetrader.ContractDelivery{
+				{TradeSymbol: spacetrader.TradeIronOre, DestinationSymbol: "X1-T-B", UnitsRequired: 30},
+			},
+		},
+	}
+	if change != nil {
+		change(&contract)
+	}
+
+	return contract
+}
+
+func TestEvaluateContract(t *testing.T) {
+	prices := ledger(t, tradeSightings()...)
+
+	// 30 units is two holds, so two round trips between home and X1-T-B: 300 fuel and 3*98 seconds.
+	// The second lot of 10 costs 3% more, 51.5 rounds up to 52.
+	const cost, fuel, duration = 20*50 + 10*52, 300, 3 * 98 * time.Second
+
+	cases := []struct {
+		name     string
+		contract spacetrader.Contract
+		ship     spacetrader.Ship
+		payout   int
+		profit   int
+		trips    int
+		duration time.Duration
+		problems []string
+	}{
+		{"an open contract pays the advance too", ironContract(nil), hauler(20, 0), 6000, 6000 - cost - fuel, 2, duration, nil},
+		{"an accepted one only has delivery left to pay", ironContract(func(c *spacetrader.Contract) { c.Accepted = true }), hauler(20, 0), 5000, 5000 - cost - fuel, 2, duration, nil},
+		{"what's delivered already isn't bought again", ironContract(func(c *spacetrader.Contract) { c.Terms.Deliver[0].UnitsFulfilled = 20 }), hauler(20, 0), 6000, 6000 - 500 - 100, 1, 98 * time.Second, nil},
+		{"a bigger hold saves a trip", ironContract(nil), hauler(40, 0), 6000, 6000 - cost - 100, 1, 98 * time.Second, nil},
+		{"accepted after the deadline to accept is fine", ironContract(func(c *spacetrader.Contract) {
+			c.Accepted = true
+			c.DeadlineToAccept = now.Add(-time.Minute).Format(time.RFC3339Nano)
+		}), hauler(20, 0), 5000, 5000 - cost - fuel, 2, duration, nil},
+		{"too late to accept", ironContract(func(c *spacetrader.Contract) { c.DeadlineToAccept = now.Add(-time.Minute).Format(time.RFC3339Nano) }), hauler(20, 0), 6000, 0, 0, 0, []string{"too late to accept"}},
+		{"past its deadline", ironContract(func(c *spacetrader.Contract) { c.Terms.Deadline = now.Add(-time.Minute).Format(time.RFC3339Nano) }), hauler(20, 0), 6000, 0, 0, 0, []string{"past its deadline"}},
+		{"already fulfilled", ironContract(func(c *spacetrader.Contract) { c.Fulfilled = true }), hauler(20, 0), 6000, 0, 0, 0, []string{"already fulfilled"}},
+		{"no hold to carry it in", ironContract(nil), hauler(0, 0), 6000, 0, 0, 0, []string{"SHIP-1 has no cargo hold"}},
+		{"not enough time for the trips", ironContract(func(c *spacetrader.Contract) { c.Terms.Deadline = now.Add(2 * time.Minute).Format(time.RFC3339Nano) }), hauler(20, 0), 6000, 6000 - cost - fuel, 2, duration, []string{"needs 5m0s but only 2m0s is left"}},
+		{"delivering to another system", ironContract(func(c *spacetrader.Contract) { c.Terms.Deliver[0].DestinationSymbol = "X1-U-Z" }), hauler(20, 0), 6000, 6000, 0, 0, []string{"X1-U-Z isn't on the map"}},
+		{"nobody on record sells it", ironContract(func(c *spacetrader.Contract) { c.Terms.Deliver[0].TradeSymbol = spacetrader.TradeGold }), hauler(20, 0), 6000, 6000, 2, 0, []string{"no market on record sells GOLD"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			estimate := EvaluateContract(c.contract, c.ship, tradeSystem, prices, now)
+
+			if !slices.Equal(estimate.Problems, c.problems) {
+				t.Fatalf("got problems %q, want %q", estimate.Problems, c.problems)
+			}
+			if estimate.Feasible() != (len(c.problems) == 0) {
+				t.Errorf("feasible is %t with problems %q", estimate.Feasible(), estimate.Problems)
+			}
+			if estimate.Payout != c.payout || estimate.Profit != c.profit || estimate.Duration != c.duration {
+				t.Errorf("pays %d for %d profit over %s, want %d for %d over %s", estimate.Payout, estimate.Profit, estimate.Duration, c.payout, c.profit, c.duration)
+			}
+
+			trips := 0
+			for _, delivery := range estimate.Deliveries {
+				trips += delivery.Trips
+				if delivery.Found && delivery.Source.Waypoint != "X1-T-A" {
+					t.Errorf("buying from %s, want X1-T-A where it's cheapest all in", delivery.Source.Waypoint)
+				}
+			}
+			if trips != c.trips {
+				t.Errorf("got %d trips, want %d", trips, c.trips)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+// Package trade looks for goods worth hauling: buy somewhere cheap, sell somewhere dear. It
+// works off the prices in the market history, so markets no ship has visited yet don't count.
+package trade
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"example.com/spacetrader"
+	"example.com/spacetrader/history"
+)
+
+// Markets move against big orders. Every tradeVolume units bought in one go pushes the
+// purchase price up by about this much, and every lot sold pulls the sell price down by it.
+// It's a rough figure from watching prices move, the API doesn't publish one.
+const PriceImpact = 0.03
+
+// Opportunity is one buy-here-sell-there run for a ship, already worked out to the units worth moving
+type Opportunity struct {
+	Good spacetrader.TradeSymbol
+	Buy  history.Observation
+	Sell history.Observation
+	// Units is how much to buy, limited by the hold, the credits to hand and where the price impact stops it paying
+	Units    int
+	Cost     int
+	Revenue  int
+	FuelCost int
+	// Profit is revenue less what the goods and the fuel to get them there cost
+	Profit   int
+	Duration time.Duration
+	// Age is how old the older of the two prices is, the staler it is the less it can be trusted
+	Age time.Duration
+}
+
+// ProfitPerHour is how well the run pays for the time it ties the ship up
+func (o Opportunity) ProfitPerHour() float64 {
+	if o.Duration <= 0 {
+		return 0
+	}
+
+	return float64(o.Profit) / o.Duration.Hours()
+}
+
+// Find ranks every profitable run in the ship's current system, best profit per hour first.
+// The ship flies to the buying market, then on to the selling market, cruising both legs.
+// Credits caps what can be spent on goods, anything below zero means there's no limit.
+func Find(ship spacetrader.Ship, waypoints []spacetrader.Waypoint, prices *history.Store, credits int64, now time.Time) []Opportunity {
+	byName := map[string]spacetrader.Waypoint{}
+	for _, waypoint := range waypoints {
+		byName[waypoint.Symbol] = waypoint
+	}
+
+	// Gather up the latest prices in the system, good by good
+	buying := map[spacetrader.TradeSymbol][]history.Observation{}
+	for _, waypointSymbol := range prices.Waypoints() {
+		if _, ok := byName[waypointSymbol]; !ok {
+			continue
+		}
+		for _, observation := range prices.LatestMarket(waypointSymbol) {
+			buying[observation.Symbol] = append(buying[observation.Symbol], observation)
+		}
+	}
+
+	fuelPrice := cheapestFuel(buying[spacetrader.TradeFuel])
+	space := ship.Cargo.Capacity - ship.Cargo.Units
+
+	opportunities := []Opportunity{}
+	if space <= 0 {
+		return opportunities
+	}
+
+	start := ship.Nav.WaypointSymbol
+	for good, observations := range buying {
+		for _, buy := range observations {
+			for _, sell := range observations {
+				if buy.Waypoint == sell.Waypoint || sell.SellPrice <= buy.PurchasePrice {
+					continue
+				}
+
+				opportunity := Opportunity{Good: good, Buy: buy, Sell: sell}
+				opportunity.Units, opportunity.Cost, opportunity.Revenue = units(buy.MarketTradeGood, sell.MarketTradeGood, space, credits)
+				if opportunity.Units == 0 {
+					continue
+				}
+
+				fuel, duration := travel(ship, byName, start, buy.Waypoint, sell.Waypoint)
+				opportunity.Duration = duration
+				opportunity.FuelCost = int(math.Ceil(float64(fuel) * fuelPrice))
+
+				opportunity.Profit = opportunity.Revenue - opportunity.Cost - opportunity.FuelCost
+				if opportunity.Profit <= 0 {
+					continue
+				}
+
+				opportunity.Age = now.Sub(buy.Time)
+				if older := now.Sub(sell.Time); older > opportunity.Age {
+					opportunity.Age = older
+				}
+
+				opportunities = append(opportunities, opportunity)
+			}
+		}
+	}
+
+	sort.Slice(opportunities, func(i, j int) bool {
+		if opportunities[i].ProfitPerHour() != opportunities[j].ProfitPerHour() {
+			return opportunities[i].ProfitPerHour() > opportunities[j].ProfitPerHour()
+		}
+		return opportunities[i].Profit > opportunities[j].Profit
+	})
+
+	return opportunities
+}
+
+// units works out how much is worth buying a lot at a time, stopping when the next lot wouldn't
+// turn a profit once prices have moved, the hold is full or the credits run out
+func units(buy spacetrader.MarketTradeGood, sell spacetrader.MarketTradeGood, space int, credits int64) (int, int, int) {
+	lot := max(1, min(buy.TradeVolume, sell.TradeVolume))
+
+	total, cost, revenue := 0, 0, 0
+	for i := 0; total < space; i++ {
+		size := min(lot, space-total)
+		purchasePrice := int(math.Round(float64(buy.PurchasePrice) * math.Pow(1+PriceImpact, float64(i))))
+		sellPrice := int(math.Round(float64(sell.SellPrice) * math.Pow(1-PriceImpact, float64(i))))
+
+		if sellPrice <= purchasePrice {
+			break
+		}
+
+		// Buy what can be afforded of this lot and stop there
+		if credits >= 0 && int64(cost+size*purchasePrice) > credits {
+			size = int((credits - int64(cost)) / int64(purchasePrice))
+			if size <= 0 {
+				break
+			}
+		}
+
+		total += size
+		cost += size * purchasePrice
+		revenue += size * sellPrice
+
+		if size < lot {
+			break
+		}
+	}
+
+	return total, cost, revenue
+}
+
+// travel adds up the fuel and time for cruising from stop to stop, in the order given
+func travel(ship spacetrader.Ship, byName map[string]spacetrader.Waypoint, stops ...string) (int, time.Duration) {
+	fuel, duration := 0, time.Duration(0)
+	for i := 1; i < len(stops); i++ {
+		from, to := byName[stops[i-1]], byName[stops[i]]
+		if from.Symbol == to.Symbol {
+			continue
+		}
+
+		distance := spacetrader.Distance(from.PosX, from.PosY, to.PosX, to.PosY)
+		if ship.Fuel.Capacity > 0 {
+			fuel += spacetrader.FuelCost(spacetrader.FlightModeCruise, distance)
+		}
+		duration += spacetrader.TravelTime(spacetrader.FlightModeCruise, ship.Engine.Speed, distance)
+	}
+
+	return fuel, duration
+}
+
+// purchaseCost is what buying units of a good comes to, a lot at a time with the price rising after each
+func purchaseCost(good spacetrader.MarketTradeGood, units int) int {
+	lot := max(1, good.TradeVolume)
+
+	cost := 0
+	for i := 0; units > 0; i++ {
+		size := min(lot, units)
+		cost += size * int(math.Round(float64(good.PurchasePrice)*math.Pow(1+PriceImpact, float64(i))))
+		units -= size
+	}
+
+	return cost
+}
+
+// cheapestFuel is the lowest price per unit of fuel on record, fuel comes 100 units to the market unit
+func cheapestFuel(observations []history.Observation) float64 {
+	cheapest := 0
+	for _, observation := range observations {
+		if cheapest == 0 || observation.PurchasePrice < cheapest {
+			cheapest = observation.PurchasePrice
+		}
+	}
+
+	return float64(cheapest) / 100
+}
@@ -0,0 +1,176 @@
+package trade
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"example.com/spacetrader"
+	"example.com/spacetrader/history"
+)
+
+// now is when every test here takes place
+var now = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// sighting is a market's prices as a ship saw them some time before now
+type sighting struct {
+	waypoint string
+	ago      time.Duration
+	goods    []spacetrader.MarketTradeGood
+}
+
+// ledger is a price history with every sighting already recorded in it
+func ledger(t *testing.T, sightings ...sighting) *history.Store {
+	t.Helper()
+
+	prices, err := history.Open(filepath.Join(t.TempDir(), "market-history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { prices.Close() })
+
+	for _, seen := range sightings {
+		if err := prices.Record(spacetrader.Market{Symbol: seen.waypoint, TradeGoods: seen.goods}, now.Add(-seen.ago)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return prices
+}
+
+func priced(symbol spacetrader.TradeSymbol, purchase int, sell int, volume int) spacetrader.MarketTradeGood {
+	return spacetrader.MarketTradeGood{Symbol: symbol, PurchasePrice: purchase, SellPrice: sell, TradeVolume: volume}
+}
+
+// X1-T has a market at home, one 100 out and one 50 out the other way, and fuel's a credit a unit at home
+var tradeSystem = []spacetrader.Waypoint{
+	{Symbol: "X1-T-A", SystemSymbol: "X1-T", PosX: 0, PosY: 0},
+	{Symbol: "X1-T-B", SystemSymbol: "X1-T", PosX: 100, PosY: 0},
+	{Symbol: "X1-T-C", SystemSymbol: "X1-T", PosX: 0, PosY: 50},
+}
+
+func tradeSightings() []sighting {
+	return []sighting{
+		{"X1-T-A", 10 * time.Minute, []spacetrader.MarketTradeGood{priced(spacetrader.TradeIronOre, 50, 45, 20), priced(spacetrader.TradeFuel, 100, 90, 100)}},
+		{"X1-T-B", 30 * time.Minute, []spacetrader.MarketTradeGood{priced(spacetrader.TradeIronOre, 90, 80, 20)}},
+		{"X1-T-C", 5 * time.Minute, []spacetrader.MarketTradeGood{priced(spacetrader.TradeIronOre, 60, 70, 20)}},
+		// Another system's market would be the best deal going, but it's out of reach
+		{"X1-U-Z", time.Minute, []spacetrader.MarketTradeGood{priced(spacetrader.TradeIronOre, 500, 900, 20)}},
+	}
+}
+
+func hauler(capacity int, held int) spacetrader.Ship {
+	return spacetrader.Ship{
+		Symbol: "SHIP-1",
+		Nav:    spacetrader.ShipNav{SystemSymbol: "X1-T", WaypointSymbol: "X1-T-A"},
+		Cargo:  spacetrader.ShipCargo{Capacity: capacity, Units: held},
+		Fuel:   spacetrader.ShipFuel{Current: 400, Capacity: 400},
+		Engine: spacetrader.ShipEngine{Speed: 30},
+	}
+}
+
+func TestUnits(t *testing.T) {
+	cases := []struct {
+		name        string
+		buy, sell   spacetrader.MarketTradeGood
+		space       int
+		credits     int64
+		wantUnits   int
+		wantCost    int
+		wantRevenue int
+	}{
+		{"one lot fits the hold", priced(spacetrader.TradeIronOre, 100, 0, 10), priced(spacetrader.TradeIronOre, 0, 200, 10), 10, -1, 10, 1000, 2000},
+		// Each lot after the first buys 3% dearer and sells 3% cheaper
+		{"prices move a lot at a time", priced(spacetrader.TradeIronOre, 100, 0, 10), priced(spacetrader.TradeIronOre, 0, 200, 10), 25, -1, 25, 1000 + 1030 + 5*106, 2000 + 1940 + 5*188},
+		{"the smaller trade volume sets the lot", priced(spacetrader.TradeIronOre, 100, 0, 20), priced(spacetrader.TradeIronOre, 0, 200, 5), 10, -1, 10, 500 + 515, 1000 + 970},
+		{"stops once the next lot wouldn't pay", priced(spacetrader.TradeIronOre, 100, 0, 10), priced(spacetrader.TradeIronOre, 0, 106, 10), 50, -1, 10, 1000, 1060},
+		{"credits run out partway through a lot", priced(spacetrader.TradeIronOre, 100, 0, 10), priced(spacetrader.TradeIronOre, 0, 200, 10), 30, 1500, 14, 1000 + 4*103, 2000 + 4*194},
+		{"can't afford a single unit", priced(spacetrader.TradeIronOre, 100, 0, 10), priced(spacetrader.TradeIronOre, 0, 200, 10), 30, 50, 0, 0, 0},
+		{"no trade volume goes a unit at a time", priced(spacetrader.TradeIronOre, 100, 0, 0), priced(spacetrader.TradeIronOre, 0, 200, 0), 2, -1, 2, 100 + 103, 200 + 194},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			units, cost, revenue := units(c.buy, c.sell, c.space, c.credits)
+			if units != c.wantUnits || cost != c.wantCost || revenue != c.wantRevenue {
+				t.Errorf("got %d units costing %d for %d, want %d costing %d for %d", units, cost, revenue, c.wantUnits, c.wantCost, c.wantRevenue)
+			}
+		})
+	}
+}
+
+func TestPurchaseCost(t *testing.T) {
+	cases := []struct {
+		name  string
+		good  spacetrader.MarketTradeGood
+		units int
+		want  int
+	}{
+		{"nothing", priced(spacetrader.TradeIronOre, 100, 0, 10), 0, 0},
+		{"within one lot", priced(spacetrader.TradeIronOre, 100, 0, 10), 7, 700},
+		{"over several lots", priced(spacetrader.TradeIronOre, 100, 0, 10), 25, 1000 + 1030 + 5*106},
+		{"half a credit rounds up", priced(spacetrader.TradeIronOre, 50, 0, 20), 30, 1000 + 10*52},
+		{"no trade volume goes a unit at a time", priced(spacetrader.TradeIronOre, 100, 0, 0), 3, 100 + 103 + 106},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := purchaseCost(c.good, c.units); got != c.want {
+				t.Errorf("got %d, want %d", got, c.want)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	prices := ledger(t, tradeSightings()...)
+
+	type run struct {
+		buy, sell string
+		units     int
+		profit    int
+		duration  time.Duration
+		age       time.Duration
+	}
+	cases := []struct {
+		name    string
+		ship    spacetrader.Ship
+		credits int64
+		want    []run
+	}{
+		// Selling next door pays less but so much sooner that it comes out top
+		{"best profit per hour first", hauler(20, 0), -1, []run{
+			{"X1-T-A", "X1-T-C", 20, 1400 - 1000 - 50, 57 * time.Second, 10 * time.Minute},
+			{"X1-T-A", "X1-T-B", 20, 1600 - 1000 - 100, 98 * time.Second, 30 * time.Minute},
+			{"X1-T-C", "X1-T-B", 20, 1600 - 1200 - 162, 57*time.Second + 108*time.Second, 30 * time.Minute},
+		}},
+		{"credits cap the load", hauler(20, 0), 600, []run{
+			{"X1-T-A", "X1-T-C", 12, 840 - 600 - 50, 57 * time.Second, 10 * time.Minute},
+			{"X1-T-A", "X1-T-B", 12, 960 - 600 - 100, 98 * time.Second, 30 * time.Minute},
+			{"X1-T-C", "X1-T-B", 10, 800 - 600 - 162, 57*time.Second + 108*time.Second, 30 * time.Minute},
+		}},
+		{"what's already in the hold leaves less room", hauler(20, 15), -1, []run{
+			{"X1-T-A", "X1-T-C", 5, 350 - 250 - 50, 57 * time.Second, 10 * time.Minute},
+			{"X1-T-A", "X1-T-B", 5, 400 - 250 - 100, 98 * time.Second, 30 * time.Minute},
+		}},
+		{"a full hold finds nothing", hauler(20, 20), -1, []run{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := Find(c.ship, tradeSystem, prices, c.credits, now)
+			if len(got) != len(c.want) {
+				t.Fatalf("got %d runs %+v, want %d", len(got), got, len(c.want))
+			}
+
+			for i, want := range c.want {
+				o := got[i]
+				if o.Buy.Waypoint != want.buy || o.Sell.Waypoint != want.sell || o.Good != spacetrader.TradeIronOre {
+					t.Errorf("run %d buys %s at %s to sell at %s, want IRON_ORE from %s to %s", i, o.Good, o.Buy.Waypoint, o.Sell.Waypoint, want.buy, want.sell)
+					continue
+				}
+				if o.Units != want.units || o.Profit != want.profit || o.Duration != want.duration || o.Age != want.age {
+					t.Errorf("run %d is %d units for %d profit over %s on %s old prices, want %d for %d over %s on %s old",
+						i, o.Units, o.Profit, o.Duration, o.Age, want.units, want.profit, want.duration, want.age)
+				}
+			}
+		})
+	}
+}